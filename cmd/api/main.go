@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/fuenr/myteam/internal/adapter/handler"
+	"github.com/fuenr/myteam/internal/adapter/mail"
 	"github.com/fuenr/myteam/internal/adapter/middleware"
 	"github.com/fuenr/myteam/internal/adapter/storage/postgres"
+	"github.com/fuenr/myteam/internal/auth"
 	"github.com/fuenr/myteam/internal/config"
 	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
 	"github.com/fuenr/myteam/internal/server"
 	"github.com/fuenr/myteam/internal/service"
 )
@@ -29,15 +38,66 @@ func main() {
 	}
 	defer db.Close()
 
+	// Token signing keys
+	var keySet *auth.KeySet
+	switch {
+	case cfg.JWTKeysDir != "":
+		keySet, err = auth.LoadKeySet(cfg.JWTKeysDir, cfg.JWTSigningKeyID)
+	case cfg.JWTSecret != "":
+		keySet, err = auth.NewKeySet([]*auth.Key{auth.NewHMACKey("default", []byte(cfg.JWTSecret))}, "default")
+	default:
+		log.Printf("No JWT keys configured (JWT_KEYS_DIR or JWT_SECRET): using a random key, sessions end on restart")
+		keySet, err = auth.EphemeralKeySet()
+	}
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+	auth.SetKeySet(keySet)
+
 	// 3. Application Layers
 	repo := postgres.NewRepository(db)
 	companyService := service.NewCompanyService(repo)
 	userService := service.NewUserService(repo, repo)
-	dashboardService := service.NewDashboardService(repo, repo, repo)
-	contractService := service.NewContractService(repo, repo)
-	vacationService := service.NewVacationService(repo)
-	h := handler.NewHandler(companyService, userService, dashboardService, contractService)
+	overtimeService := service.NewOvertimeService(repo, repo, repo, repo, repo)
+	dashboardService := service.NewDashboardService(repo, repo, repo, overtimeService)
+	contractService := service.NewContractService(repo, repo, repo)
+	vacationService := service.NewVacationService(repo, repo, repo, repo, repo, repo, repo)
+	leaveTypeService := service.NewLeaveTypeService(repo, repo)
+	holidayService := service.NewHolidayService(repo, repo, repo)
+	absenceService := service.NewAbsenceService(repo, repo, repo, cfg.PublicURL)
+	timeEntryService := service.NewTimeEntryService(repo, repo, repo, overtimeService)
+	scheduleService := service.NewScheduleService(repo, repo, repo)
+	notificationService := service.NewNotificationService(repo)
+	taxTables := domain.DefaultTaxTables()
+	if cfg.TaxTablesFile != "" {
+		if taxTables, err = service.LoadTaxTables(cfg.TaxTablesFile, taxTables); err != nil {
+			log.Fatalf("Failed to load tax tables: %v", err)
+		}
+	}
+	payrollService := service.NewPayrollService(repo, repo, repo, repo, taxTables)
+	settlementService := service.NewSettlementService(repo, repo, vacationService)
+	authService := service.NewAuthService(repo, repo)
+	var mailer port.MailSender = mail.NewFileSender(cfg.MailDir, cfg.MailFrom)
+	if cfg.SMTPHost != "" {
+		mailer = mail.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.MailFrom)
+	} else {
+		log.Printf("No SMTP_HOST configured: emails are written to MAIL_DIR or the log")
+	}
+	accountService := service.NewAccountService(repo, repo, mailer, cfg.AppURL)
+	h := handler.NewHandler(companyService, userService, dashboardService, contractService, authService)
 	vacationHandler := server.NewVacationHandler(vacationService)
+	holidayHandler := server.NewHolidayHandler(holidayService)
+	absenceHandler := server.NewAbsenceHandler(absenceService)
+	leaveTypeHandler := server.NewLeaveTypeHandler(leaveTypeService)
+	timeEntryHandler := server.NewTimeEntryHandler(timeEntryService)
+	overtimeHandler := server.NewOvertimeHandler(overtimeService)
+	scheduleHandler := server.NewScheduleHandler(scheduleService)
+	notificationHandler := server.NewNotificationHandler(notificationService)
+	payrollHandler := server.NewPayrollHandler(payrollService)
+	settlementHandler := server.NewSettlementHandler(settlementService)
+	keysHandler := server.NewKeysHandler(keySet)
+	authHandler := server.NewAuthHandler(authService)
+	accountHandler := server.NewAccountHandler(accountService)
 
 	// 4. Router
 	mux := http.NewServeMux()
@@ -46,59 +106,225 @@ func main() {
 	mux.HandleFunc("POST /login", h.Login)
 	mux.HandleFunc("POST /companies", h.CreateCompany) // Assuming creating company is public for now (registration)
 
-	// Batch Create (Allow creating multiple users without auth for now as per requirement)
-	// "Además en el proceso de registro se crean usuarios de manera masiva, en ese caso sí está bien que se creen sin necesidad de comprobar la sesión"
-	mux.HandleFunc("POST /companies/{companyID}/users/batch", h.BatchCreateUsers)
+	// Renew the access token with the refresh token handed out on login
+	mux.HandleFunc("POST /token/refresh", authHandler.Refresh)
+
+	// Forgotten passwords: the emailed token (also the one of invitations) sets a new one
+	mux.HandleFunc("POST /password/forgot", accountHandler.ForgotPassword)
+	mux.HandleFunc("POST /password/reset", accountHandler.ResetPassword)
 
-	// Create User (Public for registration/onboarding flow)
-	mux.HandleFunc("POST /users", h.CreateUser)
-	mux.HandleFunc("GET /companies/{companyID}/users", h.GetUsersByCompany)
+	// Public keys to verify our tokens (JWKS)
+	mux.HandleFunc("GET /.well-known/jwks.json", keysHandler.GetJWKS)
+
+	// iCalendar subscription (the secret token in the URL authenticates the request)
+	mux.HandleFunc("GET /feeds/{token}/absences.ics", absenceHandler.GetFeed)
 
 	// Protected Routes
 	// Helper to wrap handlers with Auth Middleware
-	protected := middleware.AuthMiddleware
-	adminOnly := func(next http.HandlerFunc) http.Handler {
-		return protected(middleware.RequireRole(domain.RoleAdmin, next))
+	protected := middleware.AuthMiddleware(authService)
+	// Anonymous requests go through unscoped
+	optionalAuth := middleware.OptionalAuthMiddleware(authService)
+	// Roles grant permissions (domain.Permission), e.g. MANAGER has vacation:approve
+	allowed := func(perm domain.Permission, next http.HandlerFunc) http.Handler {
+		return protected(middleware.RequirePermission(perm, next))
 	}
-	selfOrAdmin := func(next http.HandlerFunc) http.Handler {
-		return protected(middleware.RequireSelfOrAdmin(next))
+	// Users act on themselves; acting on others takes the permission
+	selfOr := func(perm domain.Permission, next http.HandlerFunc) http.Handler {
+		return protected(middleware.RequireSelfOrPermission(perm, next))
 	}
 
+	// Sessions: ends one (or every one with "all") and changes the password, which ends them all
+	mux.Handle("POST /logout", protected(http.HandlerFunc(authHandler.Logout)))
+	mux.Handle("PUT /users/{userID}/password", selfOr(domain.PermUserManage, authHandler.ChangePassword))
+
 	mux.Handle("GET /companies/{id}", protected(http.HandlerFunc(h.GetCompany)))
 
 	// Dashboard Stats
 	mux.Handle("GET /dashboard/stats", protected(http.HandlerFunc(h.GetDashboardStats)))
 
+	// Notifications of the caller (e.g. contracts about to end, sent to admins)
+	mux.Handle("GET /notifications", protected(http.HandlerFunc(notificationHandler.GetNotifications)))
+	mux.Handle("POST /notifications/{id}/read", protected(http.HandlerFunc(notificationHandler.MarkRead)))
+
 	// User Management
-	// POST /users is now public (moved up)
+	// Registration creates the admin of a new company anonymously; afterwards Admins add the users
+	// (the service rejects anonymous requests for companies that already have users)
+	mux.Handle("POST /users", optionalAuth(http.HandlerFunc(h.CreateUser)))
+	mux.Handle("POST /companies/{companyID}/users/batch", optionalAuth(http.HandlerFunc(h.BatchCreateUsers)))
+	mux.Handle("GET /companies/{companyID}/users", protected(http.HandlerFunc(h.GetUsersByCompany)))
 
 	// Get and Update User (Self or Admin)
 	mux.Handle("GET /users/{id}", protected(http.HandlerFunc(h.GetUser))) // Reading is usually allowed for authenticated users, or restrict to company?
-	// For now, let's keep GET simple (Auth only) or RequireSelfOrAdmin if we want strict privacy.
+	// For now, let's keep GET simple (Auth only) or RequireSelfOrPermission if we want strict privacy.
 	// User request focused on "Create or Edit". Let's restrict Edit.
 
-	mux.Handle("PUT /users/{id}", selfOrAdmin(h.UpdateUser))
-	mux.Handle("DELETE /users/{id}", adminOnly(h.DeleteUser))
+	mux.Handle("PUT /users/{id}", selfOr(domain.PermUserManage, h.UpdateUser))
+	mux.Handle("DELETE /users/{id}", allowed(domain.PermUserManage, h.DeleteUser))
+	// Emails the user a link to choose their password (users may be created without one)
+	mux.Handle("POST /users/{id}/invite", allowed(domain.PermUserManage, accountHandler.Invite))
+
+	// Contract Management (contract:read / contract:manage)
+	mux.Handle("POST /users/{userID}/contracts", allowed(domain.PermContractManage, h.CreateContract))
+	mux.Handle("GET /users/{userID}/contracts", allowed(domain.PermContractRead, h.GetContractsByUser))
+	mux.Handle("GET /users/{userID}/contracts/current", allowed(domain.PermContractRead, h.GetCurrentContract))
+	// Contracts are not edited in place: changes of terms are recorded as amendments
+	mux.Handle("POST /contracts/{id}/amendments", allowed(domain.PermContractManage, h.AmendContract))
+	mux.Handle("DELETE /contracts/{id}", allowed(domain.PermContractManage, h.DeleteContract))
+	// Ends the contract and computes the final settlement (finiquito): pending salary, extra payments, vacation and severance
+	mux.Handle("POST /contracts/{id}/termination", allowed(domain.PermContractManage, settlementHandler.TerminateContract))
+	// Gross-to-net estimate: contributions by group and IRPF withholding (?year=&payments=12|14&group=)
+	mux.Handle("GET /contracts/{id}/payroll-simulation", allowed(domain.PermContractRead, payrollHandler.GetSimulation))
+
+	// Payslips (nóminas): issued monthly (payroll:issue) and archived; employees download their own
+	mux.Handle("POST /companies/{id}/payslips", allowed(domain.PermPayrollIssue, payrollHandler.IssuePayslips))
+	mux.Handle("GET /users/{userID}/payslips", selfOr(domain.PermPayrollRead, payrollHandler.GetPayslips))
+	mux.Handle("GET /users/{userID}/payslips/{payslipID}", selfOr(domain.PermPayrollRead, payrollHandler.GetPayslip))
+	mux.Handle("GET /users/{userID}/payslips/{payslipID}/pdf", selfOr(domain.PermPayrollRead, payrollHandler.DownloadPayslip))
+	// Bank accounts (IBAN) and the SEPA credit transfer file paying the net of a month's payslips
+	mux.Handle("GET /users/{userID}/bank-account", selfOr(domain.PermUserManage, payrollHandler.GetBankAccount))
+	mux.Handle("PUT /users/{userID}/bank-account", selfOr(domain.PermUserManage, payrollHandler.SetBankAccount))
+	mux.Handle("GET /companies/{id}/bank-account", allowed(domain.PermCompanyManage, payrollHandler.GetCompanyBankAccount))
+	mux.Handle("PUT /companies/{id}/bank-account", allowed(domain.PermCompanyManage, payrollHandler.SetCompanyBankAccount))
+	mux.Handle("GET /companies/{id}/payroll-transfers", allowed(domain.PermPayrollExport, payrollHandler.ExportPayrollTransfers))
+
+	// Working-time register (registro de jornada). Corrections are Admin only and require a justification.
+	mux.Handle("POST /users/{userID}/time-entries/clock-in", selfOr(domain.PermTimeManage, timeEntryHandler.ClockIn))
+	mux.Handle("POST /users/{userID}/time-entries/clock-out", selfOr(domain.PermTimeManage, timeEntryHandler.ClockOut))
+	mux.Handle("POST /users/{userID}/time-entries/break-start", selfOr(domain.PermTimeManage, timeEntryHandler.StartBreak))
+	mux.Handle("POST /users/{userID}/time-entries/break-end", selfOr(domain.PermTimeManage, timeEntryHandler.EndBreak))
+	mux.Handle("GET /users/{userID}/time-entries", selfOr(domain.PermTimeManage, timeEntryHandler.GetEntries))
+	mux.Handle("GET /users/{userID}/time-entries/summary", selfOr(domain.PermTimeManage, timeEntryHandler.GetMonthlySummary))
+	mux.Handle("PUT /time-entries/{id}", allowed(domain.PermTimeManage, timeEntryHandler.EditEntry))
+	mux.Handle("GET /time-entries/{id}/edits", allowed(domain.PermTimeManage, timeEntryHandler.GetEdits))
+	mux.Handle("GET /users/{userID}/overtime", selfOr(domain.PermTimeManage, overtimeHandler.GetReport))
+	// Monthly register for the labour inspection (?month=YYYY-MM&format=pdf|csv), signed by the employee
+	mux.Handle("GET /users/{userID}/time-register", selfOr(domain.PermTimeManage, timeEntryHandler.ExportTimeRegister))
+	mux.Handle("POST /users/{userID}/time-register/acknowledge", selfOr(domain.PermTimeManage, timeEntryHandler.AcknowledgeTimeRegister))
+	mux.Handle("GET /companies/{id}/time-register", allowed(domain.PermTimeManage, timeEntryHandler.ExportCompanyTimeRegisters))
 
-	// Contract Management (Admin Only)
-	mux.Handle("POST /users/{userID}/contracts", adminOnly(h.CreateContract))
-	mux.Handle("GET /users/{userID}/contracts", adminOnly(h.GetContractsByUser))
-	mux.Handle("PUT /contracts/{id}", adminOnly(h.UpdateContract))
-	mux.Handle("DELETE /contracts/{id}", adminOnly(h.DeleteContract))
+	// Shift planning: schedule templates, assignments with effective dates and the weekly roster
+	mux.Handle("GET /companies/{id}/schedule-templates", allowed(domain.PermScheduleManage, scheduleHandler.GetTemplates))
+	mux.Handle("POST /companies/{id}/schedule-templates", allowed(domain.PermScheduleManage, scheduleHandler.CreateTemplate))
+	mux.Handle("DELETE /companies/{id}/schedule-templates/{templateID}", allowed(domain.PermScheduleManage, scheduleHandler.DeleteTemplate))
+	mux.Handle("POST /users/{userID}/schedule-assignments", allowed(domain.PermScheduleManage, scheduleHandler.AssignSchedule))
+	mux.Handle("GET /users/{userID}/schedule-assignments", selfOr(domain.PermScheduleManage, scheduleHandler.GetAssignments))
+	mux.Handle("DELETE /schedule-assignments/{id}", allowed(domain.PermScheduleManage, scheduleHandler.DeleteAssignment))
+	mux.Handle("GET /companies/{id}/roster", protected(http.HandlerFunc(scheduleHandler.GetRoster)))
 
 	// Vacation Management
 	// Employees can request vacations (Self or Admin?) -> Let's say Protected for now, or SelfOrAdmin.
 	// Logic: User requests for themselves.
-	mux.Handle("POST /users/{userID}/vacations", selfOrAdmin(vacationHandler.CreateVacation))
-	mux.Handle("GET /users/{userID}/vacations", selfOrAdmin(vacationHandler.GetVacationsByUserID))
-	// Update/Delete -> User can cancel pending? Admin can approve/reject?
-	// For now, let's allow SelfOrAdmin to Update/Delete.
+	mux.Handle("POST /users/{userID}/vacations", selfOr(domain.PermVacationManage, vacationHandler.CreateVacation))
+	// Read by the user, their Manager or Admin (checked in the service)
+	mux.Handle("GET /users/{userID}/vacations", protected(http.HandlerFunc(vacationHandler.GetVacationsByUserID)))
+	mux.Handle("GET /users/{userID}/vacations/balance", protected(http.HandlerFunc(vacationHandler.GetBalance)))
+	// Update/Delete a pending request: owner or Admin (checked in the service).
 	mux.Handle("PUT /vacations/{id}", protected(http.HandlerFunc(vacationHandler.UpdateVacation)))
 	mux.Handle("DELETE /vacations/{id}", protected(http.HandlerFunc(vacationHandler.DeleteVacation)))
+	// Status changes: Admin approves/rejects any request and a Manager those of their direct reports;
+	// owner or Admin cancels (checked in the service).
+	mux.Handle("POST /vacations/{id}/approve", allowed(domain.PermVacationApprove, vacationHandler.ApproveVacation))
+	mux.Handle("POST /vacations/{id}/reject", allowed(domain.PermVacationApprove, vacationHandler.RejectVacation))
+	mux.Handle("POST /vacations/{id}/cancel", protected(http.HandlerFunc(vacationHandler.CancelVacation)))
 
-	// 5. Server
+	// Other leave types (sick leave, parental, unpaid...). /vacations is the built-in "vacation" type.
+	mux.Handle("POST /users/{userID}/absences", selfOr(domain.PermVacationManage, vacationHandler.CreateAbsence))
+	mux.Handle("GET /users/{userID}/absences", protected(http.HandlerFunc(vacationHandler.GetAbsencesByUserID)))
+	mux.Handle("GET /companies/{id}/leave-types", protected(http.HandlerFunc(leaveTypeHandler.GetLeaveTypes)))
+	mux.Handle("POST /companies/{id}/leave-types", allowed(domain.PermCompanyManage, leaveTypeHandler.CreateLeaveType))
+	mux.Handle("PUT /companies/{id}/leave-types/{leaveTypeID}", allowed(domain.PermCompanyManage, leaveTypeHandler.UpdateLeaveType))
+	mux.Handle("DELETE /companies/{id}/leave-types/{leaveTypeID}", allowed(domain.PermCompanyManage, leaveTypeHandler.DeleteLeaveType))
+
+	// Team Absences
+	mux.Handle("GET /companies/{companyID}/absences", protected(http.HandlerFunc(absenceHandler.GetCompanyAbsences)))
+	mux.Handle("POST /users/{userID}/calendar-feed", selfOr(domain.PermUserManage, absenceHandler.CreateFeed))
+	mux.Handle("DELETE /users/{userID}/calendar-feed", selfOr(domain.PermUserManage, absenceHandler.RevokeFeed))
+
+	// Vacation carry-over policy and year close (Admin Only)
+	mux.Handle("GET /companies/{id}/vacation-policy", allowed(domain.PermCompanyManage, vacationHandler.GetPolicy))
+	mux.Handle("PUT /companies/{id}/vacation-policy", allowed(domain.PermCompanyManage, vacationHandler.SavePolicy))
+	mux.Handle("POST /companies/{id}/vacations/year-close", allowed(domain.PermVacationManage, vacationHandler.CloseYear))
+	mux.Handle("GET /companies/{id}/vacations/carry-overs", allowed(domain.PermVacationManage, vacationHandler.GetCarryOvers))
+
+	// Blackout periods and minimum staffing checked on vacation requests (Admin Only)
+	mux.Handle("GET /companies/{id}/blackout-periods", allowed(domain.PermCompanyManage, vacationHandler.GetBlackoutPeriods))
+	mux.Handle("POST /companies/{id}/blackout-periods", allowed(domain.PermCompanyManage, vacationHandler.CreateBlackoutPeriod))
+	mux.Handle("DELETE /companies/{id}/blackout-periods/{blackoutID}", allowed(domain.PermCompanyManage, vacationHandler.DeleteBlackoutPeriod))
+	mux.Handle("GET /companies/{id}/staffing-rules", allowed(domain.PermCompanyManage, vacationHandler.GetStaffingRules))
+	mux.Handle("POST /companies/{id}/staffing-rules", allowed(domain.PermCompanyManage, vacationHandler.CreateStaffingRule))
+	mux.Handle("DELETE /companies/{id}/staffing-rules/{ruleID}", allowed(domain.PermCompanyManage, vacationHandler.DeleteStaffingRule))
+
+	// Holiday Calendars per work centre (Admin Only)
+	mux.Handle("POST /companies/{id}/calendars", allowed(domain.PermCompanyManage, holidayHandler.CreateCalendar))
+	mux.Handle("GET /companies/{id}/calendars", allowed(domain.PermCompanyManage, holidayHandler.GetCalendars))
+	mux.Handle("PUT /companies/{id}/calendars/{calendarID}", allowed(domain.PermCompanyManage, holidayHandler.UpdateCalendar))
+	mux.Handle("DELETE /companies/{id}/calendars/{calendarID}", allowed(domain.PermCompanyManage, holidayHandler.DeleteCalendar))
+	mux.Handle("GET /companies/{id}/calendars/{calendarID}/holidays", allowed(domain.PermCompanyManage, holidayHandler.GetHolidays))
+	mux.Handle("POST /companies/{id}/calendars/{calendarID}/holidays", allowed(domain.PermCompanyManage, holidayHandler.AddHolidays))
+	mux.Handle("POST /companies/{id}/calendars/{calendarID}/import", allowed(domain.PermCompanyManage, holidayHandler.ImportICal))
+	mux.Handle("DELETE /companies/{id}/calendars/{calendarID}/holidays/{holidayID}", allowed(domain.PermCompanyManage, holidayHandler.DeleteHoliday))
+	mux.Handle("PUT /companies/{id}/calendars/{calendarID}/users/{userID}", allowed(domain.PermCompanyManage, holidayHandler.AssignUser))
+
+	// 5. Background jobs, stopped with the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var jobs sync.WaitGroup
+	// Expire carried vacation days once their expiry date is over
+	runDaily(ctx, &jobs, func(ctx context.Context, now time.Time) {
+		if n, err := vacationService.ExpireCarryOvers(ctx, now); err != nil {
+			log.Printf("Failed to expire vacation carry-overs: %v", err)
+		} else if n > 0 {
+			log.Printf("Expired %d vacation carry-overs", n)
+		}
+	})
+	// End expired contracts, start upcoming ones and remind admins of contracts ending soon
+	runDaily(ctx, &jobs, func(ctx context.Context, now time.Time) {
+		if updated, notified, err := contractService.ProcessExpirations(ctx, now); err != nil {
+			log.Printf("Failed to process contract expirations: %v", err)
+		} else if updated > 0 || notified > 0 {
+			log.Printf("Updated the status of %d contracts and sent %d expiry notifications", updated, notified)
+		}
+	})
+
+	// 6. Server
 	srv := server.NewServer(cfg.ServerPort, mux)
-	if err := srv.Run(); err != nil {
+	if err := srv.Run(ctx); err != nil {
 		log.Fatalf("Server failed: %v", err)
 	}
+	jobs.Wait()
+}
+
+// The daily jobs run at this time of the time register zone, just after
+// midnight so contracts change status on their dates.
+const jobsHour, jobsMinute = 0, 5
+
+// runDaily runs the job at once, in case the server was down at its time,
+// and then every day at jobsHour:jobsMinute until ctx is cancelled. The jobs
+// work on every company, so their context is unscoped.
+func runDaily(ctx context.Context, jobs *sync.WaitGroup, job func(ctx context.Context, now time.Time)) {
+	ctx = domain.Unscoped(ctx)
+	jobs.Go(func() {
+		ticker := time.NewTicker(untilNextRun(time.Now()))
+		defer ticker.Stop()
+		for {
+			job(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Days last 23 or 25 hours when the clocks change
+				ticker.Reset(untilNextRun(time.Now()))
+			}
+		}
+	})
+}
+
+// untilNextRun returns how long from now until the next jobsHour:jobsMinute.
+func untilNextRun(now time.Time) time.Duration {
+	now = now.In(domain.RegisterLocation)
+	next := time.Date(now.Year(), now.Month(), now.Day(), jobsHour, jobsMinute, 0, 0, domain.RegisterLocation)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.Sub(now)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -17,14 +18,16 @@ type Handler struct {
 	userService      *service.UserService
 	dashboardService *service.DashboardService
 	contractService  *service.ContractService
+	authService      *service.AuthService
 }
 
-func NewHandler(companyService *service.CompanyService, userService *service.UserService, dashboardService *service.DashboardService, contractService *service.ContractService) *Handler {
+func NewHandler(companyService *service.CompanyService, userService *service.UserService, dashboardService *service.DashboardService, contractService *service.ContractService, authService *service.AuthService) *Handler {
 	return &Handler{
 		companyService:   companyService,
 		userService:      userService,
 		dashboardService: dashboardService,
 		contractService:  contractService,
+		authService:      authService,
 	}
 }
 
@@ -44,13 +47,15 @@ func (h *Handler) respondJSON(w http.ResponseWriter, status int, payload interfa
 
 func (h *Handler) respondError(w http.ResponseWriter, err error) {
 	statusCode := http.StatusInternalServerError
-	switch err {
-	case domain.ErrNotFound:
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
 		statusCode = http.StatusNotFound
-	case domain.ErrDuplicate:
+	case errors.Is(err, domain.ErrDuplicate):
 		statusCode = http.StatusConflict
-	case domain.ErrInvalidInput, domain.ErrInvalidCredentials:
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrInvalidCredentials):
 		statusCode = http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		statusCode = http.StatusForbidden
 	}
 	h.respondJSON(w, statusCode, map[string]string{"error": err.Error()})
 }
@@ -93,20 +98,36 @@ func (h *Handler) GetCompany(w http.ResponseWriter, r *http.Request) {
 
 // --- User Handlers ---
 
+// requireAdminIfAuthenticated lets anonymous requests through (the service
+// only accepts them to register a company) and the users managing the company's users.
+func (h *Handler) requireAdminIfAuthenticated(w http.ResponseWriter, r *http.Request) bool {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if ok && !claims.Role.Can(domain.PermUserManage) {
+		h.respondError(w, domain.ErrForbidden)
+		return false
+	}
+	return true
+}
+
 func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminIfAuthenticated(w, r) {
+		return
+	}
+
 	var req struct {
-		CompanyID uuid.UUID   `json:"company_id"`
-		Name      string      `json:"name"`
-		Email     string      `json:"email"`
-		Password  string      `json:"password"`
-		Role      domain.Role `json:"role"`
+		CompanyID  uuid.UUID   `json:"company_id"`
+		Name       string      `json:"name"`
+		Email      string      `json:"email"`
+		Password   string      `json:"password"`
+		Role       domain.Role `json:"role"`
+		Department string      `json:"department"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respondError(w, domain.ErrInvalidInput)
 		return
 	}
 
-	user, err := h.userService.Create(r.Context(), req.CompanyID, req.Name, req.Email, req.Password, req.Role)
+	user, err := h.userService.Create(r.Context(), req.CompanyID, req.Name, req.Email, req.Password, req.Role, req.Department)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -115,6 +136,10 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) BatchCreateUsers(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminIfAuthenticated(w, r) {
+		return
+	}
+
 	idStr := r.PathValue("companyID")
 	companyID, err := uuid.Parse(idStr)
 	if err != nil {
@@ -151,17 +176,20 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate Token
-	token, err := auth.GenerateToken(user)
+	// Access token plus the refresh token to renew it
+	tokens, err := h.authService.IssueTokens(r.Context(), user)
 	if err != nil {
 		h.respondError(w, domain.ErrInternal)
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "login successful",
-		"token":   token,
-		"user":    user,
+		"message":       "login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+		"permissions":   user.Role.Permissions(),
 	})
 }
 
@@ -190,9 +218,11 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name  string      `json:"name"`
-		Email string      `json:"email"`
-		Role  domain.Role `json:"role"`
+		Name       string      `json:"name"`
+		Email      string      `json:"email"`
+		Role       domain.Role `json:"role"`
+		Department string      `json:"department"`
+		ManagerID  *uuid.UUID  `json:"manager_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respondError(w, domain.ErrInvalidInput)
@@ -202,7 +232,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Security Check: Only Admin can change Roles
 	// Prevent Privilege Escalation if user is self-editing
 	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
-	if ok && claims.Role != domain.RoleAdmin {
+	if ok && !claims.Role.Can(domain.PermUserManage) {
 		// Force Role to be the current role (ignore input) or return error?
 		// Better: ignore input role if not admin.
 		// However, to do that we need the CURRENT user role first.
@@ -227,9 +257,13 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 		// Override input role with existing role
 		req.Role = existingUser.Role
+		// The department drives staffing rules, so it is also admin-only
+		req.Department = existingUser.Department
+		// So is the manager, who approves the user's vacations
+		req.ManagerID = existingUser.ManagerID
 	}
 
-	user, err := h.userService.Update(r.Context(), id, req.Name, req.Email, req.Role)
+	user, err := h.userService.Update(r.Context(), id, req.Name, req.Email, req.Role, req.Department, req.ManagerID)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -246,6 +280,10 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.userService.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrUserHasRecords) {
+			h.respondJSON(w, http.StatusConflict, map[string]string{"error": err.Error() + "; the user was deactivated instead"})
+			return
+		}
 		h.respondError(w, err)
 		return
 	}
@@ -281,6 +319,20 @@ func (h *Handler) GetDashboardStats(w http.ResponseWriter, r *http.Request) {
 
 // --- Contract Handlers ---
 
+// respondContractError lists the invalid fields of a contract so forms can
+// show each message next to its input.
+func (h *Handler) respondContractError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		h.respondJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":  err.Error(),
+			"fields": validationErr.Fields,
+		})
+		return
+	}
+	h.respondError(w, err)
+}
+
 func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.PathValue("userID")
 	userID, err := uuid.Parse(userIDStr)
@@ -290,11 +342,20 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type CreateContractRequest struct {
-		StartDate string              `json:"start_date"`
-		EndDate   *string             `json:"end_date"`
-		Type      domain.ContractType `json:"type"`
-		Position  string              `json:"position"`
-		Salary    float64             `json:"salary"`
+		StartDate         string               `json:"start_date"`
+		EndDate           *string              `json:"end_date"`
+		Type              domain.ContractType  `json:"type"`
+		Cause             domain.ContractCause `json:"cause"`
+		Justification     string               `json:"justification"`
+		ActivationPeriods []struct {
+			StartDate string `json:"start_date"`
+			EndDate   string `json:"end_date"`
+		} `json:"activation_periods"`
+		Position           string  `json:"position"`
+		Salary             float64 `json:"salary"`
+		WeeklyHours        float64 `json:"weekly_hours"`
+		PartTimePercentage float64 `json:"part_time_percentage"`
+		ContributionGroup  int     `json:"contribution_group"`
 	}
 	var req CreateContractRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -317,20 +378,24 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 		}
 		endDate = &t
 	}
-
-	contract, err := h.contractService.Create(r.Context(), userID, startDate, endDate, req.Type, req.Position, req.Salary)
-	if err != nil {
-		// Use respondError which handles internal vs domain errors (like InvalidInput)
-		// We might need to map errors better if NewContract returns generic errors.
-		if err.Error() == "contract not found" {
-			h.respondError(w, domain.ErrNotFound)
+	var activationPeriods []domain.ActivationPeriod
+	for _, p := range req.ActivationPeriods {
+		start, err := time.Parse("2006-01-02", p.StartDate)
+		if err != nil {
+			h.respondError(w, domain.ErrInvalidInput)
 			return
-		} else if err.Error() == domain.ErrInvalidInput.Error() {
-			// Check if it's a validation error
-			h.respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		end, err := time.Parse("2006-01-02", p.EndDate)
+		if err != nil {
+			h.respondError(w, domain.ErrInvalidInput)
 			return
 		}
-		h.respondError(w, err)
+		activationPeriods = append(activationPeriods, domain.ActivationPeriod{StartDate: start, EndDate: end})
+	}
+
+	contract, err := h.contractService.Create(r.Context(), userID, startDate, endDate, req.Type, req.Cause, req.Justification, activationPeriods, req.Position, req.Salary, req.WeeklyHours, req.PartTimePercentage, req.ContributionGroup)
+	if err != nil {
+		h.respondContractError(w, err)
 		return
 	}
 	h.respondJSON(w, http.StatusCreated, contract)
@@ -344,7 +409,9 @@ func (h *Handler) GetContractsByUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contracts, err := h.contractService.GetByUser(r.Context(), userID)
+	// ?status=UPCOMING|ACTIVE|ENDED
+	status := domain.ContractStatus(r.URL.Query().Get("status"))
+	contracts, err := h.contractService.GetByUser(r.Context(), userID, status)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -352,7 +419,25 @@ func (h *Handler) GetContractsByUser(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, contracts)
 }
 
-func (h *Handler) UpdateContract(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetCurrentContract(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		h.respondError(w, domain.ErrInvalidInput)
+		return
+	}
+
+	contract, err := h.contractService.GetCurrent(r.Context(), userID)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, contract)
+}
+
+// AmendContract records a change of terms (promotion, pay rise...) from an
+// effective date on. Omitted fields keep their value.
+func (h *Handler) AmendContract(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -360,41 +445,56 @@ func (h *Handler) UpdateContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	type UpdateContractRequest struct {
-		StartDate string              `json:"start_date"`
-		EndDate   *string             `json:"end_date"`
-		Type      domain.ContractType `json:"type"`
-		Position  string              `json:"position"`
-		Salary    float64             `json:"salary"`
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		h.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+		return
 	}
-	var req UpdateContractRequest
+
+	type AmendContractRequest struct {
+		EffectiveDate      string               `json:"effective_date"`
+		Reason             string               `json:"reason"`
+		Type               *domain.ContractType `json:"type"`
+		EndDate            *string              `json:"end_date"`
+		Position           *string              `json:"position"`
+		Salary             *float64             `json:"salary"`
+		WeeklyHours        *float64             `json:"weekly_hours"`
+		PartTimePercentage *float64             `json:"part_time_percentage"`
+	}
+	var req AmendContractRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respondError(w, domain.ErrInvalidInput)
 		return
 	}
 
 	// Parse Dates
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
 	if err != nil {
 		h.respondError(w, domain.ErrInvalidInput)
 		return
 	}
-	var endDate *time.Time
+	changes := domain.ContractChanges{
+		Type:               req.Type,
+		Position:           req.Position,
+		Salary:             req.Salary,
+		WeeklyHours:        req.WeeklyHours,
+		PartTimePercentage: req.PartTimePercentage,
+	}
 	if req.EndDate != nil && *req.EndDate != "" {
 		t, err := time.Parse("2006-01-02", *req.EndDate)
 		if err != nil {
 			h.respondError(w, domain.ErrInvalidInput)
 			return
 		}
-		endDate = &t
+		changes.EndDate = &t
 	}
 
-	contract, err := h.contractService.Update(r.Context(), id, startDate, endDate, req.Type, req.Position, req.Salary)
+	amendment, err := h.contractService.Amend(r.Context(), id, effectiveDate, changes, req.Reason, claims.UserID)
 	if err != nil {
-		h.respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		h.respondContractError(w, err)
 		return
 	}
-	h.respondJSON(w, http.StatusOK, contract)
+	h.respondJSON(w, http.StatusCreated, amendment)
 }
 
 func (h *Handler) DeleteContract(w http.ResponseWriter, r *http.Request) {
@@ -406,6 +506,10 @@ func (h *Handler) DeleteContract(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.contractService.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrContractHasPayslips) || errors.Is(err, domain.ErrContractHasAmendments) {
+			h.respondJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
 		h.respondError(w, err)
 		return
 	}
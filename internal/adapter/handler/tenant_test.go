@@ -0,0 +1,197 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fuenr/myteam/internal/adapter/handler"
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/fuenr/myteam/internal/server"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+// store is an in-memory repository that filters by the company scope of the
+// context like the Postgres queries do. The embedded ports are nil: calling
+// anything else panics.
+type store struct {
+	port.UserRepository
+	port.ContractRepository
+	port.NotificationRepository
+	port.VacationRepository
+	port.HolidayRepository
+	port.LeaveTypeRepository
+	port.VacationPolicyRepository
+	port.VacationConstraintRepository
+
+	users     map[uuid.UUID]*domain.User
+	contracts map[uuid.UUID]*domain.Contract
+	vacations map[uuid.UUID]*domain.Vacation
+}
+
+func (s *store) inScope(ctx context.Context, userID uuid.UUID) bool {
+	u, ok := s.users[userID]
+	if !ok {
+		return false
+	}
+	companyID, scoped := domain.CompanyScope(ctx)
+	if !scoped {
+		return domain.IsUnscoped(ctx)
+	}
+	return u.CompanyID == companyID
+}
+
+func (s *store) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if !s.inScope(ctx, id) {
+		return nil, domain.ErrNotFound
+	}
+	return s.users[id], nil
+}
+
+func (s *store) GetContractByID(ctx context.Context, id uuid.UUID) (*domain.Contract, error) {
+	c, ok := s.contracts[id]
+	if !ok || !s.inScope(ctx, c.UserID) {
+		return nil, domain.ErrNotFound
+	}
+	return c, nil
+}
+
+func (s *store) DeleteContract(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetContractByID(ctx, id); err != nil {
+		return err
+	}
+	delete(s.contracts, id)
+	return nil
+}
+
+func (s *store) GetVacationByID(ctx context.Context, id uuid.UUID) (*domain.Vacation, error) {
+	v, ok := s.vacations[id]
+	if !ok || !s.inScope(ctx, v.UserID) {
+		return nil, domain.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *store) UpdateVacation(ctx context.Context, v *domain.Vacation) error {
+	_, err := s.GetVacationByID(ctx, v.ID)
+	return err
+}
+
+func (s *store) DeleteVacation(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetVacationByID(ctx, id); err != nil {
+		return err
+	}
+	delete(s.vacations, id)
+	return nil
+}
+
+// tokenChecker accepts every token and resolves users like AuthService.
+type tokenChecker struct {
+	store *store
+}
+
+func (c tokenChecker) CheckToken(ctx context.Context, claims *auth.Claims) error {
+	return nil
+}
+
+func (c tokenChecker) CheckUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := c.store.GetUserByID(ctx, userID)
+	return err
+}
+
+// company adds a company with an admin, an employee, a contract of the
+// employee and a pending vacation of theirs.
+type company struct {
+	admin, employee *domain.User
+	contract        *domain.Contract
+	vacation        *domain.Vacation
+}
+
+func (s *store) addCompany() company {
+	companyID := uuid.New()
+	c := company{
+		admin:    &domain.User{ID: uuid.New(), CompanyID: companyID, Role: domain.RoleAdmin},
+		employee: &domain.User{ID: uuid.New(), CompanyID: companyID, Role: domain.RoleEmployee},
+	}
+	c.contract = &domain.Contract{ID: uuid.New(), UserID: c.employee.ID}
+	c.vacation = &domain.Vacation{ID: uuid.New(), UserID: c.employee.ID, Status: domain.VacationStatusPending}
+	s.users[c.admin.ID], s.users[c.employee.ID] = c.admin, c.employee
+	s.contracts[c.contract.ID] = c.contract
+	s.vacations[c.vacation.ID] = c.vacation
+	return c
+}
+
+func TestCrossTenantRequestsAreNotFound(t *testing.T) {
+	keys, err := auth.EphemeralKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetKeySet(keys)
+
+	s := &store{
+		users:     map[uuid.UUID]*domain.User{},
+		contracts: map[uuid.UUID]*domain.Contract{},
+		vacations: map[uuid.UUID]*domain.Vacation{},
+	}
+	a, b := s.addCompany(), s.addCompany()
+
+	h := handler.NewHandler(nil, service.NewUserService(s, nil), nil, service.NewContractService(s, s, s), nil)
+	vacationHandler := server.NewVacationHandler(service.NewVacationService(s, s, s, s, s, s, s))
+
+	// Same routes and guards as cmd/api
+	protected := middleware.AuthMiddleware(tokenChecker{s})
+	allowed := func(perm domain.Permission, h http.HandlerFunc) http.Handler {
+		return protected(middleware.RequirePermission(perm, h))
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", protected(http.HandlerFunc(h.GetUser)))
+	mux.Handle("DELETE /contracts/{id}", allowed(domain.PermContractManage, h.DeleteContract))
+	mux.Handle("DELETE /vacations/{id}", protected(http.HandlerFunc(vacationHandler.DeleteVacation)))
+	mux.Handle("POST /vacations/{id}/cancel", protected(http.HandlerFunc(vacationHandler.CancelVacation)))
+	mux.Handle("POST /vacations/{id}/approve", allowed(domain.PermVacationApprove, vacationHandler.ApproveVacation))
+
+	token, err := auth.GenerateToken(a.admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"own user", http.MethodGet, "/users/" + a.employee.ID.String(), http.StatusOK},
+		{"foreign user", http.MethodGet, "/users/" + b.employee.ID.String(), http.StatusNotFound},
+		{"foreign admin", http.MethodGet, "/users/" + b.admin.ID.String(), http.StatusNotFound},
+		{"foreign contract", http.MethodDelete, "/contracts/" + b.contract.ID.String(), http.StatusNotFound},
+		{"own contract", http.MethodDelete, "/contracts/" + a.contract.ID.String(), http.StatusNoContent},
+		{"foreign vacation approval", http.MethodPost, "/vacations/" + b.vacation.ID.String() + "/approve", http.StatusNotFound},
+		{"foreign vacation cancellation", http.MethodPost, "/vacations/" + b.vacation.ID.String() + "/cancel", http.StatusNotFound},
+		{"foreign vacation", http.MethodDelete, "/vacations/" + b.vacation.ID.String(), http.StatusNotFound},
+		{"own vacation", http.MethodDelete, "/vacations/" + a.vacation.ID.String(), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	if _, ok := s.contracts[b.contract.ID]; !ok {
+		t.Error("the contract of the other company was deleted")
+	}
+	if v := s.vacations[b.vacation.ID]; v == nil || v.Status != domain.VacationStatusPending {
+		t.Error("the vacation of the other company was changed")
+	}
+}
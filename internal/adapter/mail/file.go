@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fuenr/myteam/internal/port"
+)
+
+// FileSender is for local development: it writes each email to an .eml file
+// in a directory or, without one, to the log.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+func (s *FileSender) Send(ctx context.Context, m port.Mail) error {
+	msg, err := message(s.from, m)
+	if err != nil {
+		return err
+	}
+	if s.dir == "" {
+		log.Printf("Mail to %s: %s\n%s", m.To, m.Subject, m.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("%s.eml", time.Now().Format("20060102-150405.000000000")))
+	if err := os.WriteFile(name, msg, 0o600); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s written to %s", m.To, name)
+	return nil
+}
@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/fuenr/myteam/internal/port"
+)
+
+// sendTimeout bounds the delivery of an email, from the dial to QUIT.
+const sendTimeout = 30 * time.Second
+
+// SMTPSender delivers emails through an SMTP server, upgrading to TLS when
+// the server offers STARTTLS.
+type SMTPSender struct {
+	addr     string
+	host     string // Server name checked against the TLS certificate
+	auth     smtp.Auth
+	from     string // From header, e.g. "MyTeam <no-reply@example.com>"
+	envelope string // Bare address for MAIL FROM
+}
+
+// NewSMTPSender authenticates with PLAIN when a username is given.
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	envelope := from
+	if addr, err := netmail.ParseAddress(from); err == nil {
+		envelope = addr.Address
+	}
+	return &SMTPSender{addr: net.JoinHostPort(host, port), host: host, auth: auth, from: from, envelope: envelope}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, m port.Mail) error {
+	msg, err := message(s.from, m)
+	if err != nil {
+		return err
+	}
+	to, _ := netmail.ParseAddress(m.To) // Validated by message
+
+	// smtp.SendMail has no timeout: a server that does not answer would hang
+	// the caller, so the whole exchange gets a deadline.
+	deadline := time.Now().Add(sendTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.envelope); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/fuenr/myteam/internal/auth"
 	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
 )
 
 type contextKey string
@@ -15,36 +17,104 @@ const (
 	UserContextKey contextKey = "user"
 )
 
-// AuthMiddleware verifies the JWT token
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
+// TokenChecker rejects tokens revoked after they were issued and resolves
+// the users named in the path within the company of the caller.
+type TokenChecker interface {
+	CheckToken(ctx context.Context, claims *auth.Claims) error
+	CheckUser(ctx context.Context, userID uuid.UUID) error
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid token format", http.StatusUnauthorized)
-			return
-		}
+// AuthMiddleware verifies the JWT token and that it has not been revoked, and
+// scopes the request to the company of the caller
+func AuthMiddleware(checker TokenChecker) func(http.Handler) http.Handler {
+	return authMiddleware(checker, false)
+}
 
-		tokenString := parts[1]
-		claims, err := auth.ValidateToken(tokenString)
-		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
+// OptionalAuthMiddleware lets anonymous requests through with no scope, so
+// they find nothing until the handler scopes them, and checks the token of
+// the others like AuthMiddleware
+func OptionalAuthMiddleware(checker TokenChecker) func(http.Handler) http.Handler {
+	return authMiddleware(checker, true)
+}
 
-		// Store claims in context
-		ctx := context.WithValue(r.Context(), UserContextKey, claims)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+func authMiddleware(checker TokenChecker, optional bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				if optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				http.Error(w, "Invalid token format", http.StatusUnauthorized)
+				return
+			}
+
+			tokenString := parts[1]
+			claims, err := auth.ValidateToken(tokenString)
+			if err != nil {
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+			// Every lookup from here on is filtered by the company of the token
+			ctx := domain.WithCompanyScope(r.Context(), claims.CompanyID)
+			if err := checker.CheckToken(ctx, claims); err != nil {
+				if errors.Is(err, domain.ErrInvalidToken) {
+					http.Error(w, "Invalid token", http.StatusUnauthorized)
+					return
+				}
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			ctx = context.WithValue(ctx, UserContextKey, claims)
+			if err := checkPathScope(ctx, r, checker, claims); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					http.Error(w, "Not Found", http.StatusNotFound)
+					return
+				}
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// checkPathScope returns domain.ErrNotFound when the company or the user in
+// the path belong to another company: for the caller they do not exist.
+// Routes name them {companyID} and {userID}, or {id} under /companies and /users.
+func checkPathScope(ctx context.Context, r *http.Request, checker TokenChecker, claims *auth.Claims) error {
+	companyID, userID := r.PathValue("companyID"), r.PathValue("userID")
+	path := r.Pattern
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[i:]
+	}
+	switch {
+	case strings.HasPrefix(path, "/companies/{id}"):
+		companyID = r.PathValue("id")
+	case strings.HasPrefix(path, "/users/{id}"):
+		userID = r.PathValue("id")
+	}
+
+	// Malformed IDs are left to the handlers, which answer 400
+	if id, err := uuid.Parse(companyID); err == nil && id != claims.CompanyID {
+		return domain.ErrNotFound
+	}
+	if id, err := uuid.Parse(userID); err == nil {
+		return checker.CheckUser(ctx, id)
+	}
+	return nil
 }
 
-// RequireRole checks if the authenticated user has the required role
-func RequireRole(role domain.Role, next http.HandlerFunc) http.HandlerFunc {
+// RequirePermission checks if the role of the authenticated user grants the permission
+func RequirePermission(perm domain.Permission, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := r.Context().Value(UserContextKey).(*auth.Claims)
 		if !ok {
@@ -52,7 +122,7 @@ func RequireRole(role domain.Role, next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if claims.Role != role {
+		if !claims.Role.Can(perm) {
 			http.Error(w, "Forbidden", http.StatusForbidden)
 			return
 		}
@@ -61,8 +131,9 @@ func RequireRole(role domain.Role, next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// RequireSelfOrAdmin checks if the user is editing themselves or is an Admin
-func RequireSelfOrAdmin(next http.HandlerFunc) http.HandlerFunc {
+// RequireSelfOrPermission checks if the user is acting on themselves or their
+// role grants the permission over other users
+func RequireSelfOrPermission(perm domain.Permission, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := r.Context().Value(UserContextKey).(*auth.Claims)
 		if !ok {
@@ -70,14 +141,16 @@ func RequireSelfOrAdmin(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// If Admin, allow
-		if claims.Role == domain.RoleAdmin {
+		if claims.Role.Can(perm) {
 			next(w, r)
 			return
 		}
 
-		// If Self, allow
-		pathID := r.PathValue("id")
+		// If Self, allow. User routes name the user either {id} or {userID}.
+		pathID := r.PathValue("userID")
+		if pathID == "" {
+			pathID = r.PathValue("id")
+		}
 		if pathID == "" {
 			http.Error(w, "Bad Request", http.StatusBadRequest)
 			return
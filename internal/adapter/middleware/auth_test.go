@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// tokenChecker knows the company of each user and, like AuthService, only
+// finds them within the company scope.
+type tokenChecker map[uuid.UUID]uuid.UUID
+
+func (c tokenChecker) CheckToken(ctx context.Context, claims *auth.Claims) error {
+	if companyID, _ := domain.CompanyScope(ctx); c[claims.UserID] != companyID {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+func (c tokenChecker) CheckUser(ctx context.Context, userID uuid.UUID) error {
+	companyID, _ := domain.CompanyScope(ctx)
+	if c[userID] != companyID {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func TestCheckPathScope(t *testing.T) {
+	companyA, companyB := uuid.New(), uuid.New()
+	userA, userB := uuid.New(), uuid.New()
+	checker := tokenChecker{userA: companyA, userB: companyB}
+	claims := &auth.Claims{UserID: userA, CompanyID: companyA, Role: domain.RoleAdmin}
+
+	tests := []struct {
+		pattern string
+		path    string
+		want    error
+	}{
+		{"GET /companies/{id}", "/companies/" + companyA.String(), nil},
+		{"GET /companies/{id}", "/companies/" + companyB.String(), domain.ErrNotFound},
+		{"GET /companies/{id}/roster", "/companies/" + companyB.String() + "/roster", domain.ErrNotFound},
+		{"GET /companies/{companyID}/users", "/companies/" + companyB.String() + "/users", domain.ErrNotFound},
+		{"GET /companies/{companyID}/absences", "/companies/" + companyA.String() + "/absences", nil},
+		{"GET /users/{id}", "/users/" + userA.String(), nil},
+		{"GET /users/{id}", "/users/" + userB.String(), domain.ErrNotFound},
+		{"GET /users/{userID}/vacations", "/users/" + userB.String() + "/vacations", domain.ErrNotFound},
+		{"PUT /companies/{id}/calendars/{calendarID}/users/{userID}", "/companies/" + companyA.String() + "/calendars/" + uuid.NewString() + "/users/" + userB.String(), domain.ErrNotFound},
+		// Other ids are resolved by the scoped queries
+		{"DELETE /vacations/{id}", "/vacations/" + uuid.NewString(), nil},
+		// Malformed ids are left to the handlers
+		{"GET /companies/{id}", "/companies/abc", nil},
+		{"GET /users/{userID}/vacations", "/users/abc/vacations", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var err error
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, func(w http.ResponseWriter, r *http.Request) {
+				ctx := domain.WithCompanyScope(r.Context(), claims.CompanyID)
+				err = checkPathScope(ctx, r, checker, claims)
+			})
+			method, _, _ := strings.Cut(tt.pattern, " ")
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, tt.path, nil))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkPathScope(%s) = %v, want %v", tt.path, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareForeignCompany(t *testing.T) {
+	keys, err := auth.EphemeralKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetKeySet(keys)
+
+	companyA, companyB := uuid.New(), uuid.New()
+	admin := &domain.User{ID: uuid.New(), CompanyID: companyA, Role: domain.RoleAdmin}
+	token, err := auth.GenerateToken(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /companies/{companyID}/users", AuthMiddleware(tokenChecker{admin.ID: companyA})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for companyID, want := range map[uuid.UUID]int{companyA: http.StatusOK, companyB: http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodGet, "/companies/"+companyID.String()+"/users", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("GET /companies/%s/users = %d, want %d", companyID, rec.Code, want)
+		}
+	}
+}
+
+func TestRequireSelfOrPermission(t *testing.T) {
+	self := uuid.New()
+	tests := []struct {
+		name   string
+		role   domain.Role
+		userID uuid.UUID
+		want   int
+	}{
+		{"self", domain.RoleEmployee, self, http.StatusOK},
+		{"other employee", domain.RoleEmployee, uuid.New(), http.StatusForbidden},
+		{"manager without the permission", domain.RoleManager, uuid.New(), http.StatusForbidden},
+		{"admin", domain.RoleAdmin, uuid.New(), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/{userID}/payslips", RequireSelfOrPermission(domain.PermPayrollRead, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tt.userID.String()+"/payslips", nil)
+			claims := &auth.Claims{UserID: self, Role: tt.role}
+			req = req.WithContext(context.WithValue(req.Context(), UserContextKey, claims))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
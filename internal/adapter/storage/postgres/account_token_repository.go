@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- AccountTokenRepository ---
+
+const accountTokenColumns = `id, user_id, purpose, token_hash, expires_at, used_at, created_at`
+
+func (r *Repository) CreateAccountToken(ctx context.Context, t *domain.AccountToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only the last token sent works
+	query := `DELETE FROM account_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, t.UserID, t.Purpose); err != nil {
+		return err
+	}
+	query = `INSERT INTO account_tokens (` + accountTokenColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(ctx, query, t.ID, t.UserID, t.Purpose, t.TokenHash, t.ExpiresAt, t.UsedAt, t.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) GetAccountTokenByHash(ctx context.Context, hash string) (*domain.AccountToken, error) {
+	query := `SELECT t.id, t.user_id, u.company_id, t.purpose, t.token_hash, t.expires_at, t.used_at, t.created_at
+		FROM account_tokens t JOIN users u ON u.id = t.user_id WHERE t.token_hash = $1`
+	var t domain.AccountToken
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(&t.ID, &t.UserID, &t.CompanyID, &t.Purpose, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *Repository) UseAccountToken(ctx context.Context, id uuid.UUID, u *domain.User, usedAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE account_tokens SET used_at = $1 WHERE id = $2 AND user_id = $3 AND used_at IS NULL`
+	res, err := tx.ExecContext(ctx, query, usedAt, id, u.ID)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows != 1 {
+		return false, nil
+	}
+	query = `UPDATE users SET password_hash = $1, token_version = $2, updated_at = $3 WHERE id = $4 AND ($5::uuid IS NULL OR company_id = $5)`
+	res, err = tx.ExecContext(ctx, query, u.PasswordHash, u.TokenVersion, u.UpdatedAt, u.ID, companyScope(ctx))
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return false, domain.ErrNotFound
+	}
+	query = `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, usedAt, u.ID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- CalendarFeedRepository ---
+
+func (r *Repository) SaveCalendarFeed(ctx context.Context, f *domain.CalendarFeed) error {
+	query := `INSERT INTO calendar_feeds (user_id, company_id, token_hash, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET company_id = EXCLUDED.company_id, token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at`
+	_, err := r.db.ExecContext(ctx, query, f.UserID, f.CompanyID, f.TokenHash, f.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	query := `SELECT user_id, company_id, token_hash, created_at FROM calendar_feeds WHERE token_hash = $1`
+	row := r.db.QueryRowContext(ctx, query, tokenHash)
+	var f domain.CalendarFeed
+	if err := row.Scan(&f.UserID, &f.CompanyID, &f.TokenHash, &f.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *Repository) DeleteCalendarFeed(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM calendar_feeds WHERE user_id = $1`
+	res, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- HolidayRepository ---
+
+// calendarInScope filters rows of a holiday calendar through its company.
+const calendarInScope = `calendar_id IN (SELECT id FROM holiday_calendars WHERE company_id = $%d)`
+
+func (r *Repository) CreateCalendar(ctx context.Context, c *domain.HolidayCalendar) error {
+	query := `INSERT INTO holiday_calendars (id, company_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.ExecContext(ctx, query, c.ID, c.CompanyID, c.Name, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetCalendarByID(ctx context.Context, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	query := `SELECT id, company_id, name, created_at, updated_at FROM holiday_calendars WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	row := r.db.QueryRowContext(ctx, query, id, companyScope(ctx))
+	var c domain.HolidayCalendar
+	if err := row.Scan(&c.ID, &c.CompanyID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *Repository) GetCalendarsByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.HolidayCalendar, error) {
+	query := `SELECT id, company_id, name, created_at, updated_at FROM holiday_calendars WHERE company_id = $1 ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calendars []*domain.HolidayCalendar
+	for rows.Next() {
+		var c domain.HolidayCalendar
+		if err := rows.Scan(&c.ID, &c.CompanyID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, &c)
+	}
+	return calendars, rows.Err()
+}
+
+func (r *Repository) UpdateCalendar(ctx context.Context, c *domain.HolidayCalendar) error {
+	query := `UPDATE holiday_calendars SET name = $1, updated_at = $2 WHERE id = $3 AND ($4::uuid IS NULL OR company_id = $4)`
+	res, err := r.db.ExecContext(ctx, query, c.Name, c.UpdatedAt, c.ID, companyScope(ctx))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM holiday_calendars WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SaveHolidays(ctx context.Context, holidays []*domain.Holiday) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO holidays (id, calendar_id, date, name, scope, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (calendar_id, date) DO UPDATE SET name = EXCLUDED.name, scope = EXCLUDED.scope`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, h := range holidays {
+		if _, err := stmt.ExecContext(ctx, h.ID, h.CalendarID, h.Date, h.Name, h.Scope, h.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetHolidays(ctx context.Context, calendarID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error) {
+	query := `SELECT id, calendar_id, date, name, scope, created_at FROM holidays
+		WHERE calendar_id = $1 AND date BETWEEN $2 AND $3 AND ($4::uuid IS NULL OR ` + fmt.Sprintf(calendarInScope, 4) + `)
+		ORDER BY date`
+	rows, err := r.db.QueryContext(ctx, query, calendarID, from, to, companyScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holidays []*domain.Holiday
+	for rows.Next() {
+		var h domain.Holiday
+		if err := rows.Scan(&h.ID, &h.CalendarID, &h.Date, &h.Name, &h.Scope, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, &h)
+	}
+	return holidays, rows.Err()
+}
+
+func (r *Repository) DeleteHoliday(ctx context.Context, calendarID, id uuid.UUID) error {
+	query := `DELETE FROM holidays WHERE id = $1 AND calendar_id = $2 AND ($3::uuid IS NULL OR ` + fmt.Sprintf(calendarInScope, 3) + `)`
+	res, err := r.db.ExecContext(ctx, query, id, calendarID, companyScope(ctx))
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) AssignCalendarToUser(ctx context.Context, userID uuid.UUID, calendarID *uuid.UUID) error {
+	query := `UPDATE users SET holiday_calendar_id = $1, updated_at = NOW() WHERE id = $2 AND ($3::uuid IS NULL OR company_id = $3)`
+	res, err := r.db.ExecContext(ctx, query, calendarID, userID, companyScope(ctx))
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetCalendarByUserID(ctx context.Context, userID uuid.UUID) (*domain.HolidayCalendar, error) {
+	query := `SELECT c.id, c.company_id, c.name, c.created_at, c.updated_at
+		FROM holiday_calendars c JOIN users u ON u.holiday_calendar_id = c.id
+		WHERE u.id = $1 AND ($2::uuid IS NULL OR u.company_id = $2)`
+	row := r.db.QueryRowContext(ctx, query, userID, companyScope(ctx))
+	var c domain.HolidayCalendar
+	if err := row.Scan(&c.ID, &c.CompanyID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
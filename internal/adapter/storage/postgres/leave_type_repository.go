@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- LeaveTypeRepository ---
+
+func (r *Repository) CreateLeaveType(ctx context.Context, lt *domain.LeaveType) error {
+	query := `INSERT INTO leave_types (id, company_id, name, paid, counts_against_balance, requires_attachment, requires_approval, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.ExecContext(ctx, query, lt.ID, lt.CompanyID, lt.Name, lt.Paid, lt.CountsAgainstBalance, lt.RequiresAttachment, lt.RequiresApproval, lt.CreatedAt, lt.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetLeaveTypeByID(ctx context.Context, id uuid.UUID) (*domain.LeaveType, error) {
+	query := `SELECT id, company_id, name, paid, counts_against_balance, requires_attachment, requires_approval, created_at, updated_at FROM leave_types WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	row := r.db.QueryRowContext(ctx, query, id, companyScope(ctx))
+	var lt domain.LeaveType
+	if err := row.Scan(&lt.ID, &lt.CompanyID, &lt.Name, &lt.Paid, &lt.CountsAgainstBalance, &lt.RequiresAttachment, &lt.RequiresApproval, &lt.CreatedAt, &lt.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &lt, nil
+}
+
+func (r *Repository) GetLeaveTypesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.LeaveType, error) {
+	query := `SELECT id, company_id, name, paid, counts_against_balance, requires_attachment, requires_approval, created_at, updated_at FROM leave_types WHERE company_id = $1 ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaveTypes []*domain.LeaveType
+	for rows.Next() {
+		var lt domain.LeaveType
+		if err := rows.Scan(&lt.ID, &lt.CompanyID, &lt.Name, &lt.Paid, &lt.CountsAgainstBalance, &lt.RequiresAttachment, &lt.RequiresApproval, &lt.CreatedAt, &lt.UpdatedAt); err != nil {
+			return nil, err
+		}
+		leaveTypes = append(leaveTypes, &lt)
+	}
+	return leaveTypes, rows.Err()
+}
+
+func (r *Repository) UpdateLeaveType(ctx context.Context, lt *domain.LeaveType) error {
+	query := `UPDATE leave_types SET name = $1, paid = $2, counts_against_balance = $3, requires_attachment = $4, requires_approval = $5, updated_at = $6 WHERE id = $7 AND ($8::uuid IS NULL OR company_id = $8)`
+	res, err := r.db.ExecContext(ctx, query, lt.Name, lt.Paid, lt.CountsAgainstBalance, lt.RequiresAttachment, lt.RequiresApproval, lt.UpdatedAt, lt.ID, companyScope(ctx))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteLeaveType(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM leave_types WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrLeaveTypeInUse
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- NotificationRepository ---
+
+const notificationColumns = `id, user_id, type, message, resource_id, key, read_at, created_at`
+
+func scanNotification(row rowScanner) (*domain.Notification, error) {
+	var n domain.Notification
+	if err := row.Scan(&n.ID, &n.UserID, &n.Type, &n.Message, &n.ResourceID, &n.Key, &n.ReadAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *Repository) CreateNotifications(ctx context.Context, notifications []*domain.Notification) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO notifications (` + notificationColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, key) DO NOTHING`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var created int
+	for _, n := range notifications {
+		res, err := stmt.ExecContext(ctx, n.ID, n.UserID, n.Type, n.Message, n.ResourceID, n.Key, n.ReadAt, n.CreatedAt)
+		if err != nil {
+			return 0, err
+		}
+		rows, _ := res.RowsAffected()
+		created += int(rows)
+	}
+	return created, tx.Commit()
+}
+
+func (r *Repository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+	n, err := scanNotification(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *Repository) UpdateNotification(ctx context.Context, n *domain.Notification) error {
+	query := `UPDATE notifications SET read_at = $1 WHERE id = $2`
+	res, err := r.db.ExecContext(ctx, query, n.ReadAt, n.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
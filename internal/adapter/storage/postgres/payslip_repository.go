@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- PayslipRepository ---
+
+const payslipColumns = `id, user_id, company_id, contract_id, period, period_start, period_end, days, contract_type, position, contribution_group,
+	payments_per_year, contribution_base, irpf_rate, gross_pay, total_deductions, net_pay, employer_cost, issued_by, created_at`
+
+// Kinds of payslip lines.
+const (
+	payslipEarning   = "EARNING"
+	payslipDeduction = "DEDUCTION"
+	payslipEmployer  = "EMPLOYER"
+)
+
+func scanPayslip(row rowScanner) (*domain.Payslip, error) {
+	var p domain.Payslip
+	if err := row.Scan(&p.ID, &p.UserID, &p.CompanyID, &p.ContractID, &p.Period, &p.PeriodStart, &p.PeriodEnd, &p.Days, &p.ContractType, &p.Position,
+		&p.ContributionGroup, &p.PaymentsPerYear, &p.ContributionBase, &p.IRPFRate, &p.GrossPay, &p.TotalDeductions, &p.NetPay, &p.EmployerCost,
+		&p.IssuedBy, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *Repository) CreatePayslip(ctx context.Context, p *domain.Payslip, document []byte) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO payslips (` + payslipColumns + `, document)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`
+	_, err = tx.ExecContext(ctx, query, p.ID, p.UserID, p.CompanyID, p.ContractID, p.Period, p.PeriodStart, p.PeriodEnd, p.Days, p.ContractType, p.Position,
+		p.ContributionGroup, p.PaymentsPerYear, p.ContributionBase, p.IRPFRate, p.GrossPay, p.TotalDeductions, p.NetPay, p.EmployerCost,
+		p.IssuedBy, p.CreatedAt, document)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO payslip_lines (payslip_id, kind, line, concept, base, rate, amount) VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for kind, lines := range map[string][]domain.Contribution{
+		payslipEarning:   p.Earnings,
+		payslipDeduction: p.Deductions,
+		payslipEmployer:  p.EmployerContributions,
+	} {
+		for i, l := range lines {
+			if _, err := stmt.ExecContext(ctx, p.ID, kind, i, l.Concept, l.Base, l.Rate, l.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) GetPayslipByID(ctx context.Context, id uuid.UUID) (*domain.Payslip, error) {
+	query := `SELECT ` + payslipColumns + ` FROM payslips WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	p, err := scanPayslip(r.db.QueryRowContext(ctx, query, id, companyScope(ctx)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT kind, concept, base, rate, amount FROM payslip_lines WHERE payslip_id = $1 ORDER BY kind, line`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var l domain.Contribution
+		if err := rows.Scan(&kind, &l.Concept, &l.Base, &l.Rate, &l.Amount); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case payslipEarning:
+			p.Earnings = append(p.Earnings, l)
+		case payslipDeduction:
+			p.Deductions = append(p.Deductions, l)
+		case payslipEmployer:
+			p.EmployerContributions = append(p.EmployerContributions, l)
+		}
+	}
+	return p, rows.Err()
+}
+
+func (r *Repository) GetPayslipsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Payslip, error) {
+	query := `SELECT ` + payslipColumns + ` FROM payslips WHERE user_id = $1 AND ($2::uuid IS NULL OR company_id = $2) ORDER BY period DESC, period_start DESC`
+	return r.queryPayslips(ctx, query, userID, companyScope(ctx))
+}
+
+func (r *Repository) GetPayslipsByCompanyID(ctx context.Context, companyID uuid.UUID, period string) ([]*domain.Payslip, error) {
+	query := `SELECT ` + payslipColumns + ` FROM payslips WHERE company_id = $1 AND period = $2 ORDER BY period_start, id`
+	return r.queryPayslips(ctx, query, companyID, period)
+}
+
+func (r *Repository) queryPayslips(ctx context.Context, query string, args ...any) ([]*domain.Payslip, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payslips []*domain.Payslip
+	for rows.Next() {
+		p, err := scanPayslip(rows)
+		if err != nil {
+			return nil, err
+		}
+		payslips = append(payslips, p)
+	}
+	return payslips, rows.Err()
+}
+
+func (r *Repository) GetPayslipDocument(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var document []byte
+	if err := r.db.QueryRowContext(ctx, `SELECT document FROM payslips WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`, id, companyScope(ctx)).Scan(&document); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return document, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- RefreshTokenRepository ---
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at`
+
+func (r *Repository) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (` + refreshTokenColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.ExecContext(ctx, query, t.ID, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt, t.UsedAt, t.RevokedAt, t.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	query := `SELECT t.id, t.user_id, u.company_id, t.family_id, t.token_hash, t.expires_at, t.used_at, t.revoked_at, t.created_at
+		FROM refresh_tokens t JOIN users u ON u.id = t.user_id WHERE t.token_hash = $1`
+	var t domain.RefreshToken
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(&t.ID, &t.UserID, &t.CompanyID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.RevokedAt, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *Repository) UseRefreshToken(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL`, usedAt, id)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := res.RowsAffected()
+	return rows == 1, nil
+}
+
+func (r *Repository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, revokedAt, familyID)
+	return err
+}
+
+func (r *Repository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, revokedAt, userID)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/fuenr/myteam/internal/domain"
 	"github.com/google/uuid"
@@ -18,11 +19,29 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// companyScope is the company the queries made with ctx are filtered by (see
+// domain.WithCompanyScope). Queries compare it with
+// "($n::uuid IS NULL OR company_id = $n)", so rows of other companies are not
+// found. It is NULL only for contexts marked domain.Unscoped; without any
+// scope it is the nil UUID, which no company has, and nothing is found.
+func companyScope(ctx context.Context) uuid.NullUUID {
+	if id, ok := domain.CompanyScope(ctx); ok {
+		return uuid.NullUUID{UUID: id, Valid: true}
+	}
+	if domain.IsUnscoped(ctx) {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: uuid.Nil, Valid: true}
+}
+
+// userInScope filters rows owned by a user through its company.
+const userInScope = `user_id IN (SELECT id FROM users WHERE company_id = $%d)`
+
 // --- CompanyRepository ---
 
 func (r *Repository) CreateCompany(ctx context.Context, c *domain.Company) error {
-	query := `INSERT INTO companies (id, name, cif, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.ExecContext(ctx, query, c.ID, c.Name, c.CIF, c.CreatedAt, c.UpdatedAt)
+	query := `INSERT INTO companies (id, name, cif, iban, bic, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.ExecContext(ctx, query, c.ID, c.Name, c.CIF, c.IBAN, c.BIC, c.CreatedAt, c.UpdatedAt)
 	if err != nil {
 		if isUniqueViolation(err) {
 			return domain.ErrDuplicate
@@ -33,10 +52,10 @@ func (r *Repository) CreateCompany(ctx context.Context, c *domain.Company) error
 }
 
 func (r *Repository) GetCompanyByID(ctx context.Context, id uuid.UUID) (*domain.Company, error) {
-	query := `SELECT id, name, cif, created_at, updated_at FROM companies WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	query := `SELECT id, name, cif, iban, bic, created_at, updated_at FROM companies WHERE id = $1 AND ($2::uuid IS NULL OR id = $2)`
+	row := r.db.QueryRowContext(ctx, query, id, companyScope(ctx))
 	var c domain.Company
-	if err := row.Scan(&c.ID, &c.Name, &c.CIF, &c.CreatedAt, &c.UpdatedAt); err != nil {
+	if err := row.Scan(&c.ID, &c.Name, &c.CIF, &c.IBAN, &c.BIC, &c.CreatedAt, &c.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
@@ -46,10 +65,10 @@ func (r *Repository) GetCompanyByID(ctx context.Context, id uuid.UUID) (*domain.
 }
 
 func (r *Repository) GetCompanyByCIF(ctx context.Context, cif string) (*domain.Company, error) {
-	query := `SELECT id, name, cif, created_at, updated_at FROM companies WHERE cif = $1`
+	query := `SELECT id, name, cif, iban, bic, created_at, updated_at FROM companies WHERE cif = $1`
 	row := r.db.QueryRowContext(ctx, query, cif)
 	var c domain.Company
-	if err := row.Scan(&c.ID, &c.Name, &c.CIF, &c.CreatedAt, &c.UpdatedAt); err != nil {
+	if err := row.Scan(&c.ID, &c.Name, &c.CIF, &c.IBAN, &c.BIC, &c.CreatedAt, &c.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
@@ -59,8 +78,8 @@ func (r *Repository) GetCompanyByCIF(ctx context.Context, cif string) (*domain.C
 }
 
 func (r *Repository) UpdateCompany(ctx context.Context, c *domain.Company) error {
-	query := `UPDATE companies SET name = $1, cif = $2, updated_at = $3 WHERE id = $4`
-	res, err := r.db.ExecContext(ctx, query, c.Name, c.CIF, c.UpdatedAt, c.ID)
+	query := `UPDATE companies SET name = $1, cif = $2, iban = $3, bic = $4, updated_at = $5 WHERE id = $6 AND ($7::uuid IS NULL OR id = $7)`
+	res, err := r.db.ExecContext(ctx, query, c.Name, c.CIF, c.IBAN, c.BIC, c.UpdatedAt, c.ID, companyScope(ctx))
 	if err != nil {
 		if isUniqueViolation(err) {
 			return domain.ErrDuplicate
@@ -75,8 +94,8 @@ func (r *Repository) UpdateCompany(ctx context.Context, c *domain.Company) error
 }
 
 func (r *Repository) DeleteCompany(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM companies WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM companies WHERE id = $1 AND ($2::uuid IS NULL OR id = $2)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
 	if err != nil {
 		return err
 	}
@@ -88,9 +107,9 @@ func (r *Repository) DeleteCompany(ctx context.Context, id uuid.UUID) error {
 }
 
 func (r *Repository) CountCompanies(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM companies`
+	query := `SELECT COUNT(*) FROM companies WHERE $1::uuid IS NULL OR id = $1`
 	var count int64
-	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, companyScope(ctx)).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -98,9 +117,19 @@ func (r *Repository) CountCompanies(ctx context.Context) (int64, error) {
 
 // --- UserRepository ---
 
+const userColumns = `id, company_id, name, email, password_hash, role, department, manager_id, iban, token_version, deactivated_at, created_at, updated_at`
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var u domain.User
+	if err := row.Scan(&u.ID, &u.CompanyID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.Department, &u.ManagerID, &u.IBAN, &u.TokenVersion, &u.DeactivatedAt, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
 func (r *Repository) CreateUser(ctx context.Context, u *domain.User) error {
-	query := `INSERT INTO users (id, company_id, name, email, password_hash, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := r.db.ExecContext(ctx, query, u.ID, u.CompanyID, u.Name, u.Email, u.PasswordHash, u.Role, u.CreatedAt, u.UpdatedAt)
+	query := `INSERT INTO users (` + userColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+	_, err := r.db.ExecContext(ctx, query, u.ID, u.CompanyID, u.Name, u.Email, u.PasswordHash, u.Role, u.Department, u.ManagerID, u.IBAN, u.TokenVersion, u.DeactivatedAt, u.CreatedAt, u.UpdatedAt)
 	if err != nil {
 		if isUniqueViolation(err) {
 			return domain.ErrDuplicate
@@ -118,7 +147,7 @@ func (r *Repository) BatchCreateUsers(ctx context.Context, users []*domain.User)
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO users (id, company_id, name, email, password_hash, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO users (` + userColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return err
@@ -126,7 +155,7 @@ func (r *Repository) BatchCreateUsers(ctx context.Context, users []*domain.User)
 	defer stmt.Close()
 
 	for _, u := range users {
-		_, err := stmt.ExecContext(ctx, u.ID, u.CompanyID, u.Name, u.Email, u.PasswordHash, u.Role, u.CreatedAt, u.UpdatedAt)
+		_, err := stmt.ExecContext(ctx, u.ID, u.CompanyID, u.Name, u.Email, u.PasswordHash, u.Role, u.Department, u.ManagerID, u.IBAN, u.TokenVersion, u.DeactivatedAt, u.CreatedAt, u.UpdatedAt)
 		if err != nil {
 			if isUniqueViolation(err) {
 				return domain.ErrDuplicate
@@ -139,33 +168,32 @@ func (r *Repository) BatchCreateUsers(ctx context.Context, users []*domain.User)
 }
 
 func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `SELECT id, company_id, name, email, password_hash, role, created_at, updated_at FROM users WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
-	var u domain.User
-	if err := row.Scan(&u.ID, &u.CompanyID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	u, err := scanUser(r.db.QueryRowContext(ctx, query, id, companyScope(ctx)))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
-	return &u, nil
+	return u, nil
 }
 
 func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `SELECT id, company_id, name, email, password_hash, role, created_at, updated_at FROM users WHERE email = $1`
-	row := r.db.QueryRowContext(ctx, query, email)
-	var u domain.User
-	if err := row.Scan(&u.ID, &u.CompanyID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	u, err := scanUser(r.db.QueryRowContext(ctx, query, email))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
-	return &u, nil
+	return u, nil
 }
 
+// GetUsersByCompanyID leaves out deactivated users.
 func (r *Repository) GetUsersByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.User, error) {
-	query := `SELECT id, company_id, name, email, password_hash, role, created_at, updated_at FROM users WHERE company_id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE company_id = $1 AND deactivated_at IS NULL`
 	rows, err := r.db.QueryContext(ctx, query, companyID)
 	if err != nil {
 		return nil, err
@@ -174,18 +202,19 @@ func (r *Repository) GetUsersByCompanyID(ctx context.Context, companyID uuid.UUI
 
 	var users []*domain.User
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.CompanyID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, &u)
+		users = append(users, u)
 	}
 	return users, rows.Err()
 }
 
 func (r *Repository) UpdateUser(ctx context.Context, u *domain.User) error {
-	query := `UPDATE users SET name = $1, email = $2, password_hash = $3, role = $4, updated_at = $5 WHERE id = $6`
-	res, err := r.db.ExecContext(ctx, query, u.Name, u.Email, u.PasswordHash, u.Role, u.UpdatedAt, u.ID)
+	query := `UPDATE users SET name = $1, email = $2, password_hash = $3, role = $4, department = $5, manager_id = $6, iban = $7, token_version = $8, deactivated_at = $9, updated_at = $10
+		WHERE id = $11 AND ($12::uuid IS NULL OR company_id = $12)`
+	res, err := r.db.ExecContext(ctx, query, u.Name, u.Email, u.PasswordHash, u.Role, u.Department, u.ManagerID, u.IBAN, u.TokenVersion, u.DeactivatedAt, u.UpdatedAt, u.ID, companyScope(ctx))
 	if err != nil {
 		if isUniqueViolation(err) {
 			return domain.ErrDuplicate
@@ -200,9 +229,17 @@ func (r *Repository) UpdateUser(ctx context.Context, u *domain.User) error {
 }
 
 func (r *Repository) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM users WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
 	if err != nil {
+		// The contracts of the user cascade, but not their amendments nor
+		// the payslips issued under them
+		if isForeignKeyViolation(err) {
+			switch violatedConstraint(err) {
+			case "contract_amendments_contract_id_fkey", "payslips_contract_id_fkey":
+				return domain.ErrUserHasRecords
+			}
+		}
 		return err
 	}
 	rows, _ := res.RowsAffected()
@@ -221,33 +258,85 @@ func isUniqueViolation(err error) bool {
 	return false
 }
 
+// isForeignKeyViolation checks if the error is a Postgres foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23503"
+	}
+	return false
+}
+
+// violatedConstraint returns the name of the constraint a statement broke.
+func violatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
+
 // --- ContractRepository ---
 
+const contractColumns = `id, user_id, start_date, end_date, type, cause, justification, position, salary, weekly_hours, part_time_percentage, contribution_group, status, created_at, updated_at`
+
+func scanContract(row rowScanner) (*domain.Contract, error) {
+	var c domain.Contract
+	if err := row.Scan(&c.ID, &c.UserID, &c.StartDate, &c.EndDate, &c.Type, &c.Cause, &c.Justification, &c.Position, &c.Salary, &c.WeeklyHours, &c.PartTimePercentage, &c.ContributionGroup, &c.Status, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
 func (r *Repository) CreateContract(ctx context.Context, c *domain.Contract) error {
-	query := `INSERT INTO contracts (id, user_id, start_date, end_date, type, position, salary, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	_, err := r.db.ExecContext(ctx, query, c.ID, c.UserID, c.StartDate, c.EndDate, c.Type, c.Position, c.Salary, c.CreatedAt, c.UpdatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+
+	query := `INSERT INTO contracts (` + contractColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	_, err = tx.ExecContext(ctx, query, c.ID, c.UserID, c.StartDate, c.EndDate, c.Type, c.Cause, c.Justification, c.Position, c.Salary, c.WeeklyHours, c.PartTimePercentage, c.ContributionGroup, c.Status, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	for _, p := range c.ActivationPeriods {
+		query := `INSERT INTO contract_activation_periods (contract_id, start_date, end_date) VALUES ($1, $2, $3)`
+		if _, err := tx.ExecContext(ctx, query, c.ID, p.StartDate, p.EndDate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (r *Repository) GetContractByID(ctx context.Context, id uuid.UUID) (*domain.Contract, error) {
-	query := `SELECT id, user_id, start_date, end_date, type, position, salary, created_at, updated_at FROM contracts WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
-	var c domain.Contract
-	if err := row.Scan(&c.ID, &c.UserID, &c.StartDate, &c.EndDate, &c.Type, &c.Position, &c.Salary, &c.CreatedAt, &c.UpdatedAt); err != nil {
+	query := `SELECT ` + contractColumns + ` FROM contracts WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	c, err := scanContract(r.db.QueryRowContext(ctx, query, id, companyScope(ctx)))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
-	return &c, nil
+	if err := r.loadContractDetails(ctx, []*domain.Contract{c}); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 func (r *Repository) GetContractsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contract, error) {
-	query := `SELECT id, user_id, start_date, end_date, type, position, salary, created_at, updated_at FROM contracts WHERE user_id = $1`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	query := `SELECT ` + contractColumns + ` FROM contracts WHERE user_id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `) ORDER BY start_date`
+	return r.queryContracts(ctx, query, userID, companyScope(ctx))
+}
+
+func (r *Repository) GetUnendedContracts(ctx context.Context) ([]*domain.Contract, error) {
+	query := `SELECT ` + contractColumns + ` FROM contracts WHERE status <> $1 ORDER BY start_date`
+	return r.queryContracts(ctx, query, domain.ContractStatusEnded)
+}
+
+// queryContracts runs a query selecting contractColumns and loads the details of the contracts found.
+func (r *Repository) queryContracts(ctx context.Context, query string, args ...any) ([]*domain.Contract, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -255,18 +344,151 @@ func (r *Repository) GetContractsByUserID(ctx context.Context, userID uuid.UUID)
 
 	var contracts []*domain.Contract
 	for rows.Next() {
-		var c domain.Contract
-		if err := rows.Scan(&c.ID, &c.UserID, &c.StartDate, &c.EndDate, &c.Type, &c.Position, &c.Salary, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		c, err := scanContract(rows)
+		if err != nil {
 			return nil, err
 		}
-		contracts = append(contracts, &c)
+		contracts = append(contracts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadContractDetails(ctx, contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// loadContractDetails fills the activation periods, the amendments and the
+// termination of the given contracts with one query each.
+func (r *Repository) loadContractDetails(ctx context.Context, contracts []*domain.Contract) error {
+	if len(contracts) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.Contract, len(contracts))
+	ids := make([]string, 0, len(contracts))
+	for _, c := range contracts {
+		byID[c.ID] = c
+		ids = append(ids, c.ID.String())
+	}
+
+	query := `SELECT contract_id, start_date, end_date FROM contract_activation_periods WHERE contract_id = ANY($1::uuid[]) ORDER BY start_date`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.ActivationPeriod
+		var contractID uuid.UUID
+		if err := rows.Scan(&contractID, &p.StartDate, &p.EndDate); err != nil {
+			return err
+		}
+		if c, ok := byID[contractID]; ok {
+			c.ActivationPeriods = append(c.ActivationPeriods, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := r.loadContractAmendments(ctx, byID, ids); err != nil {
+		return err
+	}
+	return r.loadContractTerminations(ctx, byID, ids)
+}
+
+func (r *Repository) loadContractAmendments(ctx context.Context, byID map[uuid.UUID]*domain.Contract, ids []string) error {
+	query := `SELECT id, contract_id, effective_date, type, end_date, position, salary, weekly_hours, part_time_percentage, reason, author_id, created_at
+		FROM contract_amendments WHERE contract_id = ANY($1::uuid[]) ORDER BY effective_date, created_at`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.ContractAmendment
+		if err := rows.Scan(&a.ID, &a.ContractID, &a.EffectiveDate, &a.Type, &a.EndDate, &a.Position, &a.Salary, &a.WeeklyHours, &a.PartTimePercentage, &a.Reason, &a.AuthorID, &a.CreatedAt); err != nil {
+			return err
+		}
+		if c, ok := byID[a.ContractID]; ok {
+			c.Amendments = append(c.Amendments, &a)
+		}
 	}
-	return contracts, rows.Err()
+	return rows.Err()
 }
 
-func (r *Repository) UpdateContract(ctx context.Context, c *domain.Contract) error {
-	query := `UPDATE contracts SET start_date = $1, end_date = $2, type = $3, position = $4, salary = $5, updated_at = $6 WHERE id = $7`
-	res, err := r.db.ExecContext(ctx, query, c.StartDate, c.EndDate, c.Type, c.Position, c.Salary, c.UpdatedAt, c.ID)
+func (r *Repository) CreateContractAmendment(ctx context.Context, a *domain.ContractAmendment) error {
+	query := `INSERT INTO contract_amendments (id, contract_id, effective_date, type, end_date, position, salary, weekly_hours, part_time_percentage, reason, author_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err := r.db.ExecContext(ctx, query, a.ID, a.ContractID, a.EffectiveDate, a.Type, a.EndDate, a.Position, a.Salary, a.WeeklyHours, a.PartTimePercentage, a.Reason, a.AuthorID, a.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+const terminationColumns = `contract_id, date, reason, payments_per_year, pending_salary_days, pending_salary, extra_payments,
+	vacation_days, vacation_unit, vacation_pay, seniority_date, severance_days, severance, total, author_id, created_at`
+
+func (r *Repository) loadContractTerminations(ctx context.Context, byID map[uuid.UUID]*domain.Contract, ids []string) error {
+	query := `SELECT ` + terminationColumns + ` FROM contract_terminations WHERE contract_id = ANY($1::uuid[])`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.ContractTermination
+		s := &t.Settlement
+		if err := rows.Scan(&t.ContractID, &t.Date, &t.Reason, &s.PaymentsPerYear, &s.PendingSalaryDays, &s.PendingSalary, &s.ExtraPayments,
+			&s.VacationDays, &s.VacationUnit, &s.VacationPay, &s.SeniorityDate, &s.SeveranceDays, &s.Severance, &s.Total, &t.AuthorID, &t.CreatedAt); err != nil {
+			return err
+		}
+		if c, ok := byID[t.ContractID]; ok {
+			c.Termination = &t
+		}
+	}
+	return rows.Err()
+}
+
+func (r *Repository) CreateContractTermination(ctx context.Context, c *domain.Contract) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t, s := c.Termination, c.Termination.Settlement
+	query := `INSERT INTO contract_terminations (` + terminationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	_, err = tx.ExecContext(ctx, query, t.ContractID, t.Date, t.Reason, s.PaymentsPerYear, s.PendingSalaryDays, s.PendingSalary, s.ExtraPayments,
+		s.VacationDays, s.VacationUnit, s.VacationPay, s.SeniorityDate, s.SeveranceDays, s.Severance, s.Total, t.AuthorID, t.CreatedAt)
+	if err != nil {
+		switch {
+		case isUniqueViolation(err):
+			return domain.ErrContractTerminated
+		case isForeignKeyViolation(err):
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE contracts SET status = $1, updated_at = $2 WHERE id = $3`, c.Status, c.UpdatedAt, c.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) UpdateContractStatus(ctx context.Context, c *domain.Contract) error {
+	query := `UPDATE contracts SET status = $1, updated_at = $2 WHERE id = $3 AND ($4::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 4) + `)`
+	res, err := r.db.ExecContext(ctx, query, c.Status, c.UpdatedAt, c.ID, companyScope(ctx))
 	if err != nil {
 		return err
 	}
@@ -278,9 +500,16 @@ func (r *Repository) UpdateContract(ctx context.Context, c *domain.Contract) err
 }
 
 func (r *Repository) DeleteContract(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM contracts WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM contracts WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			// An amendment may have been added since the service checked
+			if violatedConstraint(err) == "contract_amendments_contract_id_fkey" {
+				return domain.ErrContractHasAmendments
+			}
+			return domain.ErrContractHasPayslips
+		}
 		return err
 	}
 	rows, _ := res.RowsAffected()
@@ -291,27 +520,32 @@ func (r *Repository) DeleteContract(ctx context.Context, id uuid.UUID) error {
 }
 
 func (r *Repository) CountContracts(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM contracts`
+	query := `SELECT COUNT(*) FROM contracts WHERE $1::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 1)
 	var count int64
-	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, companyScope(ctx)).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
 func (r *Repository) SumSalaries(ctx context.Context) (float64, error) {
-	query := `SELECT COALESCE(SUM(salary), 0) FROM contracts`
+	query := `SELECT COALESCE(SUM(COALESCE((
+			SELECT a.salary FROM contract_amendments a
+			WHERE a.contract_id = c.id AND a.salary IS NOT NULL AND a.effective_date <= CURRENT_DATE
+			ORDER BY a.effective_date DESC, a.created_at DESC LIMIT 1
+		), c.salary)), 0) FROM contracts c
+		WHERE $1::uuid IS NULL OR c.` + fmt.Sprintf(userInScope, 1)
 	var total float64
-	if err := r.db.QueryRowContext(ctx, query).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, companyScope(ctx)).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
 func (r *Repository) CountUsers(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM users`
+	query := `SELECT COUNT(*) FROM users WHERE deactivated_at IS NULL AND ($1::uuid IS NULL OR company_id = $1)`
 	var count int64
-	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, companyScope(ctx)).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
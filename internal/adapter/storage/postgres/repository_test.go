@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+)
+
+func TestRepositoryDeleteUser(t *testing.T) {
+	r := testRepository(t)
+	ctx := domain.Unscoped(context.Background())
+
+	plain, amended, paid := createTenant(t, r), createTenant(t, r), createTenant(t, r)
+	salary := 32000.0
+	amendment, err := amended.contract.Amend(amended.contract.StartDate.AddDate(0, 1, 0), domain.ContractChanges{Salary: &salary}, "Revisión salarial", amended.employee.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateContractAmendment(ctx, amendment); err != nil {
+		t.Fatalf("CreateContractAmendment: %v", err)
+	}
+
+	paid.contract.ContributionGroup = 1
+	table, _ := domain.DefaultTaxTables().ForYear(paid.contract.StartDate.Year())
+	payslip, _, err := domain.NewPayslip(paid.company.ID, paid.contract, paid.contract.StartDate, 12, table, paid.employee.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreatePayslip(ctx, payslip, []byte("%PDF")); err != nil {
+		t.Fatalf("CreatePayslip: %v", err)
+	}
+
+	if err := r.DeleteUser(ctx, plain.employee.ID); err != nil {
+		t.Errorf("DeleteUser: %v", err)
+	}
+	if _, err := r.GetContractByID(ctx, plain.contract.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetContractByID after deleting the user err = %v, want ErrNotFound", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		tn   tenant
+	}{
+		{"amended contract", amended},
+		{"issued payslip", paid},
+	} {
+		if err := r.DeleteUser(ctx, tt.tn.employee.ID); !errors.Is(err, domain.ErrUserHasRecords) {
+			t.Errorf("DeleteUser with an %s err = %v, want ErrUserHasRecords", tt.name, err)
+		}
+		if _, err := r.GetContractByID(ctx, tt.tn.contract.ID); err != nil {
+			t.Errorf("the contract with an %s was deleted: %v", tt.name, err)
+		}
+	}
+}
+
+func TestRepositoryDeactivatedUser(t *testing.T) {
+	r := testRepository(t)
+	tn := createTenant(t, r)
+	ctx := domain.WithCompanyScope(context.Background(), tn.company.ID)
+
+	tn.employee.Deactivate(time.Now())
+	if err := r.UpdateUser(ctx, tn.employee); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	u, err := r.GetUserByID(ctx, tn.employee.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if u.IsActive() || u.TokenVersion != 1 {
+		t.Errorf("user active = %v with token version %d, want deactivated with version 1", u.IsActive(), u.TokenVersion)
+	}
+	users, err := r.GetUsersByCompanyID(ctx, tn.company.ID)
+	if err != nil {
+		t.Fatalf("GetUsersByCompanyID: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("GetUsersByCompanyID returned %d users, want the deactivated one left out", len(users))
+	}
+	if count, err := r.CountUsers(ctx); err != nil || count != 0 {
+		t.Errorf("CountUsers = %d, %v; want 0", count, err)
+	}
+}
+
+func TestRepositoryUseAccountToken(t *testing.T) {
+	r := testRepository(t)
+	tn := createTenant(t, r)
+	ctx := domain.Unscoped(context.Background())
+
+	token, secret, err := domain.NewAccountToken(tn.employee.ID, domain.AccountTokenPasswordReset, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateAccountToken(ctx, token); err != nil {
+		t.Fatalf("CreateAccountToken: %v", err)
+	}
+	stored, err := r.GetAccountTokenByHash(ctx, domain.HashAccountToken(secret))
+	if err != nil {
+		t.Fatalf("GetAccountTokenByHash: %v", err)
+	}
+	if stored.CompanyID != tn.company.ID {
+		t.Errorf("token company = %s, want the company of the user %s", stored.CompanyID, tn.company.ID)
+	}
+
+	ctx = domain.WithCompanyScope(context.Background(), tn.company.ID)
+	reset := func(hash string) (bool, error) {
+		u := *tn.employee
+		u.SetPasswordHash(hash)
+		u.UpdatedAt = time.Now()
+		return r.UseAccountToken(ctx, token.ID, &u, time.Now())
+	}
+	if used, err := reset("new-hash"); err != nil || !used {
+		t.Fatalf("UseAccountToken = %v, %v; want true", used, err)
+	}
+	if used, err := reset("other-hash"); err != nil || used {
+		t.Errorf("UseAccountToken of a used token = %v, %v; want false", used, err)
+	}
+	u, err := r.GetUserByID(ctx, tn.employee.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if u.PasswordHash != "new-hash" || u.TokenVersion != 1 {
+		t.Errorf("user password %q with token version %d, want the first reset only", u.PasswordHash, u.TokenVersion)
+	}
+}
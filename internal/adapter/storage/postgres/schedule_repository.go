@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// --- ScheduleRepository ---
+
+func (r *Repository) CreateScheduleTemplate(ctx context.Context, t *domain.ScheduleTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO schedule_templates (id, company_id, name, cycle_days, created_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, query, t.ID, t.CompanyID, t.Name, len(t.Days), t.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	for i, d := range t.Days {
+		if d.IsRest() {
+			continue
+		}
+		query := `INSERT INTO schedule_template_shifts (template_id, day_index, start_time, end_time) VALUES ($1, $2, $3, $4)`
+		if _, err := tx.ExecContext(ctx, query, t.ID, i, d.Start, d.End); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) GetScheduleTemplateByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleTemplate, error) {
+	query := `SELECT id, company_id, name, cycle_days, created_at FROM schedule_templates WHERE id = $1 AND ($2::uuid IS NULL OR company_id = $2)`
+	templates, err := r.queryScheduleTemplates(ctx, query, id, companyScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return templates[0], nil
+}
+
+func (r *Repository) GetScheduleTemplatesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.ScheduleTemplate, error) {
+	query := `SELECT id, company_id, name, cycle_days, created_at FROM schedule_templates WHERE company_id = $1 ORDER BY name`
+	return r.queryScheduleTemplates(ctx, query, companyID)
+}
+
+// queryScheduleTemplates runs a template query and fills the shifts of every
+// template with a second one. Days without shifts are rest days.
+func (r *Repository) queryScheduleTemplates(ctx context.Context, query string, args ...any) ([]*domain.ScheduleTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*domain.ScheduleTemplate
+	byID := make(map[uuid.UUID]*domain.ScheduleTemplate)
+	var ids []string
+	for rows.Next() {
+		var t domain.ScheduleTemplate
+		var cycleDays int
+		if err := rows.Scan(&t.ID, &t.CompanyID, &t.Name, &cycleDays, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.Days = make([]domain.ShiftPattern, cycleDays)
+		templates = append(templates, &t)
+		byID[t.ID] = &t
+		ids = append(ids, t.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	shiftQuery := `SELECT template_id, day_index, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI')
+		FROM schedule_template_shifts WHERE template_id = ANY($1::uuid[])`
+	shiftRows, err := r.db.QueryContext(ctx, shiftQuery, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer shiftRows.Close()
+
+	for shiftRows.Next() {
+		var templateID uuid.UUID
+		var day int
+		var p domain.ShiftPattern
+		if err := shiftRows.Scan(&templateID, &day, &p.Start, &p.End); err != nil {
+			return nil, err
+		}
+		if t, ok := byID[templateID]; ok && day < len(t.Days) {
+			t.Days[day] = p
+		}
+	}
+	return templates, shiftRows.Err()
+}
+
+func (r *Repository) DeleteScheduleTemplate(ctx context.Context, companyID, id uuid.UUID) error {
+	query := `DELETE FROM schedule_templates WHERE id = $1 AND company_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, companyID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrScheduleTemplateInUse
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+const scheduleAssignmentColumns = `a.id, a.user_id, a.template_id, a.start_date, a.end_date, a.created_at`
+
+func scanScheduleAssignment(row rowScanner) (*domain.ScheduleAssignment, error) {
+	var a domain.ScheduleAssignment
+	if err := row.Scan(&a.ID, &a.UserID, &a.TemplateID, &a.StartDate, &a.EndDate, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *Repository) CreateScheduleAssignment(ctx context.Context, a *domain.ScheduleAssignment) error {
+	query := `INSERT INTO schedule_assignments (id, user_id, template_id, start_date, end_date, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.ExecContext(ctx, query, a.ID, a.UserID, a.TemplateID, a.StartDate, a.EndDate, a.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetScheduleAssignmentByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleAssignment, error) {
+	query := `SELECT ` + scheduleAssignmentColumns + ` FROM schedule_assignments a WHERE a.id = $1 AND ($2::uuid IS NULL OR a.` + fmt.Sprintf(userInScope, 2) + `)`
+	a, err := scanScheduleAssignment(r.db.QueryRowContext(ctx, query, id, companyScope(ctx)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *Repository) GetScheduleAssignmentsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduleAssignment, error) {
+	query := `SELECT ` + scheduleAssignmentColumns + ` FROM schedule_assignments a WHERE a.user_id = $1 AND ($2::uuid IS NULL OR a.` + fmt.Sprintf(userInScope, 2) + `) ORDER BY a.start_date`
+	return r.queryScheduleAssignments(ctx, query, userID, companyScope(ctx))
+}
+
+func (r *Repository) GetScheduleAssignmentsByCompanyID(ctx context.Context, companyID uuid.UUID, from, to time.Time) ([]*domain.ScheduleAssignment, error) {
+	query := `SELECT ` + scheduleAssignmentColumns + ` FROM schedule_assignments a
+		JOIN users u ON u.id = a.user_id
+		WHERE u.company_id = $1 AND a.start_date <= $2 AND (a.end_date IS NULL OR a.end_date >= $3)
+		ORDER BY a.start_date`
+	return r.queryScheduleAssignments(ctx, query, companyID, to, from)
+}
+
+func (r *Repository) queryScheduleAssignments(ctx context.Context, query string, args ...any) ([]*domain.ScheduleAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []*domain.ScheduleAssignment
+	for rows.Next() {
+		a, err := scanScheduleAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *Repository) UpdateScheduleAssignment(ctx context.Context, a *domain.ScheduleAssignment) error {
+	query := `UPDATE schedule_assignments SET template_id = $1, start_date = $2, end_date = $3 WHERE id = $4 AND ($5::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 5) + `)`
+	res, err := r.db.ExecContext(ctx, query, a.TemplateID, a.StartDate, a.EndDate, a.ID, companyScope(ctx))
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteScheduleAssignment(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM schedule_assignments WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// testRepository connects to the disposable database of TEST_DATABASE_URL
+// and applies the schema. Without it the tests are skipped.
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := NewDB("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("../../../../migrations/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("applying the schema: %v", err)
+	}
+	return NewRepository(db)
+}
+
+// tenant is a company with an employee who has a contract and a vacation.
+type tenant struct {
+	company  *domain.Company
+	employee *domain.User
+	contract *domain.Contract
+	vacation *domain.Vacation
+}
+
+func createTenant(t *testing.T, r *Repository) tenant {
+	t.Helper()
+	ctx := domain.Unscoped(context.Background())
+	suffix := uuid.NewString()[:8]
+
+	company, err := domain.NewCompany("Company "+suffix, "B"+suffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateCompany(ctx, company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	employee, err := domain.NewUser(company.ID, "Employee", suffix+"@example.com", "hash", domain.RoleEmployee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateUser(ctx, employee); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	start := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	contract, err := domain.NewContract(employee.ID, start, nil, domain.ContractTypeIndefinite, "", "", nil, "Developer", 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateContract(ctx, contract); err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+	vacation, err := domain.NewVacation(employee.ID, start.AddDate(0, 6, 0), start.AddDate(0, 6, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateVacation(ctx, vacation); err != nil {
+		t.Fatalf("CreateVacation: %v", err)
+	}
+	return tenant{company, employee, contract, vacation}
+}
+
+func TestRepositoryCompanyScope(t *testing.T) {
+	r := testRepository(t)
+	a, b := createTenant(t, r), createTenant(t, r)
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"own company", domain.WithCompanyScope(context.Background(), b.company.ID), nil},
+		{"other company", domain.WithCompanyScope(context.Background(), a.company.ID), domain.ErrNotFound},
+		// Contexts nobody scoped find nothing, unless they are marked unscoped
+		{"no scope", context.Background(), domain.ErrNotFound},
+		{"unscoped", domain.Unscoped(context.Background()), nil},
+		{"unscoped then scoped", domain.WithCompanyScope(domain.Unscoped(context.Background()), a.company.ID), domain.ErrNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if _, err := r.GetUserByID(ctx, b.employee.ID); !errors.Is(err, tt.want) {
+				t.Errorf("GetUserByID err = %v, want %v", err, tt.want)
+			}
+			if _, err := r.GetContractByID(ctx, b.contract.ID); !errors.Is(err, tt.want) {
+				t.Errorf("GetContractByID err = %v, want %v", err, tt.want)
+			}
+			if _, err := r.GetVacationByID(ctx, b.vacation.ID); !errors.Is(err, tt.want) {
+				t.Errorf("GetVacationByID err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Writes outside the scope leave the rows alone
+	ctx := domain.WithCompanyScope(context.Background(), a.company.ID)
+	if err := r.DeleteVacation(ctx, b.vacation.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteVacation err = %v, want ErrNotFound", err)
+	}
+	if _, err := r.GetVacationByID(domain.WithCompanyScope(context.Background(), b.company.ID), b.vacation.ID); err != nil {
+		t.Errorf("the vacation of the other company was deleted: %v", err)
+	}
+}
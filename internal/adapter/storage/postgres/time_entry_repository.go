@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// --- TimeEntryRepository ---
+
+const timeEntryColumns = `id, user_id, clock_in, clock_out, created_at, updated_at`
+
+func scanTimeEntry(row rowScanner) (*domain.TimeEntry, error) {
+	e := domain.TimeEntry{Breaks: []*domain.TimeBreak{}}
+	if err := row.Scan(&e.ID, &e.UserID, &e.ClockIn, &e.ClockOut, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *Repository) CreateTimeEntry(ctx context.Context, e *domain.TimeEntry) error {
+	query := `INSERT INTO time_entries (` + timeEntryColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.ExecContext(ctx, query, e.ID, e.UserID, e.ClockIn, e.ClockOut, e.CreatedAt, e.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyClockedIn
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetTimeEntryByID(ctx context.Context, id uuid.UUID) (*domain.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	return r.getTimeEntry(ctx, query, id, companyScope(ctx))
+}
+
+func (r *Repository) GetOpenTimeEntry(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries WHERE user_id = $1 AND clock_out IS NULL AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	return r.getTimeEntry(ctx, query, userID, companyScope(ctx))
+}
+
+func (r *Repository) getTimeEntry(ctx context.Context, query string, args ...any) (*domain.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := r.loadTimeBreaks(ctx, []*domain.TimeEntry{e}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *Repository) GetTimeEntriesByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries
+		WHERE user_id = $1 AND clock_in >= $2 AND clock_in < $3 AND ($4::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 4) + `)
+		ORDER BY clock_in`
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to, companyScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.TimeEntry
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadTimeBreaks(ctx, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *Repository) GetUserIDsWithTimeEntries(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	query := `SELECT DISTINCT user_id FROM time_entries WHERE clock_in >= $1 AND clock_in < $2 AND ($3::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 3) + `)`
+	rows, err := r.db.QueryContext(ctx, query, from, to, companyScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// loadTimeBreaks fills the breaks of the given entries with a single query.
+func (r *Repository) loadTimeBreaks(ctx context.Context, entries []*domain.TimeEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.TimeEntry, len(entries))
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		byID[e.ID] = e
+		ids = append(ids, e.ID.String())
+	}
+
+	query := `SELECT id, time_entry_id, start_time, end_time FROM time_breaks WHERE time_entry_id = ANY($1::uuid[]) ORDER BY start_time`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b domain.TimeBreak
+		var entryID uuid.UUID
+		if err := rows.Scan(&b.ID, &entryID, &b.Start, &b.End); err != nil {
+			return err
+		}
+		if e, ok := byID[entryID]; ok {
+			e.Breaks = append(e.Breaks, &b)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *Repository) UpdateTimeEntry(ctx context.Context, e *domain.TimeEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateTimeEntry(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) EditTimeEntry(ctx context.Context, e *domain.TimeEntry, edit *domain.TimeEntryEdit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateTimeEntry(ctx, tx, e); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO time_entry_edits (id, time_entry_id, editor_id, reason, previous_clock_in, previous_clock_out, clock_in, clock_out, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.ExecContext(ctx, query, edit.ID, edit.TimeEntryID, edit.EditorID, edit.Reason, edit.PreviousClockIn, edit.PreviousClockOut, edit.ClockIn, edit.ClockOut, edit.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateTimeEntry saves the entry and replaces its breaks within tx.
+func updateTimeEntry(ctx context.Context, tx *sql.Tx, e *domain.TimeEntry) error {
+	query := `UPDATE time_entries SET clock_in = $1, clock_out = $2, updated_at = $3 WHERE id = $4 AND ($5::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 5) + `)`
+	res, err := tx.ExecContext(ctx, query, e.ClockIn, e.ClockOut, e.UpdatedAt, e.ID, companyScope(ctx))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyClockedIn
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM time_breaks WHERE time_entry_id = $1`, e.ID); err != nil {
+		return err
+	}
+	for _, b := range e.Breaks {
+		_, err := tx.ExecContext(ctx, `INSERT INTO time_breaks (id, time_entry_id, start_time, end_time) VALUES ($1, $2, $3, $4)`, b.ID, e.ID, b.Start, b.End)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) GetTimeEntryEdits(ctx context.Context, entryID uuid.UUID) ([]*domain.TimeEntryEdit, error) {
+	query := `SELECT id, time_entry_id, editor_id, reason, previous_clock_in, previous_clock_out, clock_in, clock_out, created_at
+		FROM time_entry_edits
+		WHERE time_entry_id = $1 AND ($2::uuid IS NULL OR time_entry_id IN (SELECT id FROM time_entries WHERE ` + fmt.Sprintf(userInScope, 2) + `))
+		ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, entryID, companyScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*domain.TimeEntryEdit
+	for rows.Next() {
+		var e domain.TimeEntryEdit
+		if err := rows.Scan(&e.ID, &e.TimeEntryID, &e.EditorID, &e.Reason, &e.PreviousClockIn, &e.PreviousClockOut, &e.ClockIn, &e.ClockOut, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, &e)
+	}
+	return edits, rows.Err()
+}
+
+func (r *Repository) SaveTimeRegisterAcknowledgement(ctx context.Context, ack *domain.TimeRegisterAcknowledgement) error {
+	query := `INSERT INTO time_register_acknowledgements (user_id, month, acknowledged_at) VALUES ($1, $2, $3)`
+	_, err := r.db.ExecContext(ctx, query, ack.UserID, ack.Month, ack.AcknowledgedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetTimeRegisterAcknowledgement(ctx context.Context, userID uuid.UUID, month string) (*domain.TimeRegisterAcknowledgement, error) {
+	query := `SELECT user_id, month, acknowledged_at FROM time_register_acknowledgements WHERE user_id = $1 AND month = $2`
+	var ack domain.TimeRegisterAcknowledgement
+	if err := r.db.QueryRowContext(ctx, query, userID, month).Scan(&ack.UserID, &ack.Month, &ack.AcknowledgedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &ack, nil
+}
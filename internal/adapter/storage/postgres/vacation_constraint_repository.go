@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- VacationConstraintRepository ---
+
+const blackoutColumns = `id, company_id, department, name, start_date, end_date, created_at`
+
+func (r *Repository) CreateBlackoutPeriod(ctx context.Context, b *domain.BlackoutPeriod) error {
+	query := `INSERT INTO blackout_periods (` + blackoutColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.ExecContext(ctx, query, b.ID, b.CompanyID, b.Department, b.Name, b.StartDate, b.EndDate, b.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetBlackoutPeriodsByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.BlackoutPeriod, error) {
+	query := `SELECT ` + blackoutColumns + ` FROM blackout_periods WHERE company_id = $1 ORDER BY start_date`
+	return r.queryBlackoutPeriods(ctx, query, companyID)
+}
+
+func (r *Repository) GetOverlappingBlackoutPeriods(ctx context.Context, companyID uuid.UUID, start, end time.Time) ([]*domain.BlackoutPeriod, error) {
+	query := `SELECT ` + blackoutColumns + ` FROM blackout_periods
+		WHERE company_id = $1 AND start_date <= $2 AND end_date >= $3
+		ORDER BY start_date`
+	return r.queryBlackoutPeriods(ctx, query, companyID, end, start)
+}
+
+func (r *Repository) queryBlackoutPeriods(ctx context.Context, query string, args ...any) ([]*domain.BlackoutPeriod, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []*domain.BlackoutPeriod
+	for rows.Next() {
+		var b domain.BlackoutPeriod
+		if err := rows.Scan(&b.ID, &b.CompanyID, &b.Department, &b.Name, &b.StartDate, &b.EndDate, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		periods = append(periods, &b)
+	}
+	return periods, rows.Err()
+}
+
+func (r *Repository) DeleteBlackoutPeriod(ctx context.Context, companyID, id uuid.UUID) error {
+	query := `DELETE FROM blackout_periods WHERE id = $1 AND company_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, companyID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) CreateStaffingRule(ctx context.Context, rule *domain.StaffingRule) error {
+	query := `INSERT INTO staffing_rules (id, company_id, department, min_present, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.ExecContext(ctx, query, rule.ID, rule.CompanyID, rule.Department, rule.MinPresent, rule.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicate
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) GetStaffingRulesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.StaffingRule, error) {
+	query := `SELECT id, company_id, department, min_present, created_at FROM staffing_rules WHERE company_id = $1 ORDER BY department`
+	rows, err := r.db.QueryContext(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.StaffingRule
+	for rows.Next() {
+		var rule domain.StaffingRule
+		if err := rows.Scan(&rule.ID, &rule.CompanyID, &rule.Department, &rule.MinPresent, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) DeleteStaffingRule(ctx context.Context, companyID, id uuid.UUID) error {
+	query := `DELETE FROM staffing_rules WHERE id = $1 AND company_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, companyID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// --- VacationPolicyRepository ---
+
+func (r *Repository) GetVacationPolicy(ctx context.Context, companyID uuid.UUID) (*domain.VacationPolicy, error) {
+	query := `SELECT company_id, max_carry_over_days, expiry_month, expiry_day, updated_at FROM vacation_policies WHERE company_id = $1`
+	row := r.db.QueryRowContext(ctx, query, companyID)
+	var p domain.VacationPolicy
+	if err := row.Scan(&p.CompanyID, &p.MaxCarryOverDays, &p.ExpiryMonth, &p.ExpiryDay, &p.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *Repository) SaveVacationPolicy(ctx context.Context, p *domain.VacationPolicy) error {
+	query := `INSERT INTO vacation_policies (company_id, max_carry_over_days, expiry_month, expiry_day, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (company_id) DO UPDATE SET max_carry_over_days = EXCLUDED.max_carry_over_days, expiry_month = EXCLUDED.expiry_month,
+			expiry_day = EXCLUDED.expiry_day, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, query, p.CompanyID, p.MaxCarryOverDays, p.ExpiryMonth, p.ExpiryDay, p.UpdatedAt)
+	return err
+}
+
+const carryOverColumns = `id, user_id, company_id, from_year, entitled, used, remaining, carried_days, forfeited_days, expires_at, status, expired_days, expired_at, closed_by, created_at`
+
+func scanCarryOver(row rowScanner) (*domain.VacationCarryOver, error) {
+	var c domain.VacationCarryOver
+	if err := row.Scan(&c.ID, &c.UserID, &c.CompanyID, &c.FromYear, &c.Entitled, &c.Used, &c.Remaining, &c.CarriedDays, &c.ForfeitedDays,
+		&c.ExpiresAt, &c.Status, &c.ExpiredDays, &c.ExpiredAt, &c.ClosedBy, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *Repository) queryCarryOvers(ctx context.Context, query string, args ...any) ([]*domain.VacationCarryOver, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var carryOvers []*domain.VacationCarryOver
+	for rows.Next() {
+		c, err := scanCarryOver(rows)
+		if err != nil {
+			return nil, err
+		}
+		carryOvers = append(carryOvers, c)
+	}
+	return carryOvers, rows.Err()
+}
+
+func (r *Repository) CreateCarryOvers(ctx context.Context, carryOvers []*domain.VacationCarryOver) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO vacation_carry_overs (` + carryOverColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range carryOvers {
+		_, err := stmt.ExecContext(ctx, c.ID, c.UserID, c.CompanyID, c.FromYear, c.Entitled, c.Used, c.Remaining, c.CarriedDays, c.ForfeitedDays,
+			c.ExpiresAt, c.Status, c.ExpiredDays, c.ExpiredAt, c.ClosedBy, c.CreatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return domain.ErrDuplicate
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetCarryOver(ctx context.Context, userID uuid.UUID, fromYear int) (*domain.VacationCarryOver, error) {
+	query := `SELECT ` + carryOverColumns + ` FROM vacation_carry_overs WHERE user_id = $1 AND from_year = $2`
+	c, err := scanCarryOver(r.db.QueryRowContext(ctx, query, userID, fromYear))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Repository) GetCarryOversByCompanyID(ctx context.Context, companyID uuid.UUID, fromYear int) ([]*domain.VacationCarryOver, error) {
+	query := `SELECT ` + carryOverColumns + ` FROM vacation_carry_overs WHERE company_id = $1 AND from_year = $2 ORDER BY created_at`
+	return r.queryCarryOvers(ctx, query, companyID, fromYear)
+}
+
+func (r *Repository) GetActiveCarryOversExpiringBefore(ctx context.Context, date time.Time) ([]*domain.VacationCarryOver, error) {
+	query := `SELECT ` + carryOverColumns + ` FROM vacation_carry_overs WHERE status = $1 AND expires_at < $2`
+	return r.queryCarryOvers(ctx, query, domain.CarryOverStatusActive, date)
+}
+
+func (r *Repository) UpdateCarryOver(ctx context.Context, c *domain.VacationCarryOver) error {
+	query := `UPDATE vacation_carry_overs SET status = $1, expired_days = $2, expired_at = $3 WHERE id = $4`
+	res, err := r.db.ExecContext(ctx, query, c.Status, c.ExpiredDays, c.ExpiredAt, c.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
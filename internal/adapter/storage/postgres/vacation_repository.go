@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/fuenr/myteam/internal/domain"
 	"github.com/google/uuid"
@@ -10,47 +12,98 @@ import (
 
 // --- VacationRepository ---
 
+const vacationColumns = `id, user_id, leave_type_id, start_date, end_date, period, start_time, end_time, status, approver_id, decided_at, rejection_reason, attachment_url, constraints_overridden_by, constraints_overridden_at, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVacation(row rowScanner) (*domain.Vacation, error) {
+	var v domain.Vacation
+	if err := row.Scan(&v.ID, &v.UserID, &v.LeaveTypeID, &v.StartDate, &v.EndDate, &v.Period, &v.StartTime, &v.EndTime, &v.Status, &v.ApproverID, &v.DecidedAt, &v.RejectionReason, &v.AttachmentURL, &v.ConstraintsOverriddenBy, &v.ConstraintsOverriddenAt, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *Repository) queryVacations(ctx context.Context, query string, args ...any) ([]*domain.Vacation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vacations []*domain.Vacation
+	for rows.Next() {
+		v, err := scanVacation(rows)
+		if err != nil {
+			return nil, err
+		}
+		vacations = append(vacations, v)
+	}
+	return vacations, rows.Err()
+}
+
 func (r *Repository) CreateVacation(ctx context.Context, v *domain.Vacation) error {
-	query := `INSERT INTO vacations (id, user_id, start_date, end_date, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.ExecContext(ctx, query, v.ID, v.UserID, v.StartDate, v.EndDate, v.Status, v.CreatedAt, v.UpdatedAt)
+	query := `INSERT INTO vacations (` + vacationColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+	_, err := r.db.ExecContext(ctx, query, v.ID, v.UserID, v.LeaveTypeID, v.StartDate, v.EndDate, v.Period, v.StartTime, v.EndTime, v.Status, v.ApproverID, v.DecidedAt, v.RejectionReason, v.AttachmentURL, v.ConstraintsOverriddenBy, v.ConstraintsOverriddenAt, v.CreatedAt, v.UpdatedAt)
 	return err
 }
 
 func (r *Repository) GetVacationByID(ctx context.Context, id uuid.UUID) (*domain.Vacation, error) {
-	query := `SELECT id, user_id, start_date, end_date, status, created_at, updated_at FROM vacations WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
-	var v domain.Vacation
-	if err := row.Scan(&v.ID, &v.UserID, &v.StartDate, &v.EndDate, &v.Status, &v.CreatedAt, &v.UpdatedAt); err != nil {
+	query := `SELECT ` + vacationColumns + ` FROM vacations WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	v, err := scanVacation(r.db.QueryRowContext(ctx, query, id, companyScope(ctx)))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
-	return &v, nil
+	return v, nil
 }
 
 func (r *Repository) GetVacationsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Vacation, error) {
-	query := `SELECT id, user_id, start_date, end_date, status, created_at, updated_at FROM vacations WHERE user_id = $1`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	query := `SELECT ` + vacationColumns + ` FROM vacations WHERE user_id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	return r.queryVacations(ctx, query, userID, companyScope(ctx))
+}
+
+func (r *Repository) GetOverlappingVacations(ctx context.Context, userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*domain.Vacation, error) {
+	query := `SELECT ` + vacationColumns + ` FROM vacations
+		WHERE user_id = $1 AND id <> $2 AND status IN ($3, $4) AND start_date <= $5 AND end_date >= $6 AND ($7::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 7) + `)
+		ORDER BY start_date`
+	return r.queryVacations(ctx, query, userID, excludeID, domain.VacationStatusPending, domain.VacationStatusApproved, end, start, companyScope(ctx))
+}
+
+func (r *Repository) GetAbsencesByCompanyID(ctx context.Context, companyID uuid.UUID, from, to time.Time) ([]*domain.TeamAbsence, error) {
+	query := `SELECT v.id, v.user_id, v.leave_type_id, v.start_date, v.end_date, v.period, v.start_time, v.end_time, v.status, v.approver_id, v.decided_at, v.rejection_reason, v.attachment_url, v.constraints_overridden_by, v.constraints_overridden_at, v.created_at, v.updated_at,
+			u.name, COALESCE(lt.name, $6)
+		FROM vacations v JOIN users u ON u.id = v.user_id
+		LEFT JOIN leave_types lt ON lt.id = v.leave_type_id
+		WHERE u.company_id = $1 AND v.status IN ($2, $3) AND v.start_date <= $4 AND v.end_date >= $5
+		ORDER BY v.start_date, u.name`
+	rows, err := r.db.QueryContext(ctx, query, companyID, domain.VacationStatusPending, domain.VacationStatusApproved, to, from, domain.VacationLeaveType.Name)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var vacations []*domain.Vacation
+	var absences []*domain.TeamAbsence
 	for rows.Next() {
 		var v domain.Vacation
-		if err := rows.Scan(&v.ID, &v.UserID, &v.StartDate, &v.EndDate, &v.Status, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		a := domain.TeamAbsence{Vacation: &v}
+		if err := rows.Scan(&v.ID, &v.UserID, &v.LeaveTypeID, &v.StartDate, &v.EndDate, &v.Period, &v.StartTime, &v.EndTime, &v.Status, &v.ApproverID, &v.DecidedAt, &v.RejectionReason, &v.AttachmentURL, &v.ConstraintsOverriddenBy, &v.ConstraintsOverriddenAt, &v.CreatedAt, &v.UpdatedAt,
+			&a.UserName, &a.LeaveTypeName); err != nil {
 			return nil, err
 		}
-		vacations = append(vacations, &v)
+		absences = append(absences, &a)
 	}
-	return vacations, rows.Err()
+	return absences, rows.Err()
 }
 
 func (r *Repository) UpdateVacation(ctx context.Context, v *domain.Vacation) error {
-	query := `UPDATE vacations SET start_date = $1, end_date = $2, status = $3, updated_at = $4 WHERE id = $5`
-	res, err := r.db.ExecContext(ctx, query, v.StartDate, v.EndDate, v.Status, v.UpdatedAt, v.ID)
+	query := `UPDATE vacations SET start_date = $1, end_date = $2, period = $3, start_time = $4, end_time = $5, status = $6, approver_id = $7, decided_at = $8, rejection_reason = $9, constraints_overridden_by = $10, constraints_overridden_at = $11, updated_at = $12 WHERE id = $13 AND ($14::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 14) + `)`
+	res, err := r.db.ExecContext(ctx, query, v.StartDate, v.EndDate, v.Period, v.StartTime, v.EndTime, v.Status, v.ApproverID, v.DecidedAt, v.RejectionReason, v.ConstraintsOverriddenBy, v.ConstraintsOverriddenAt, v.UpdatedAt, v.ID, companyScope(ctx))
 	if err != nil {
 		return err
 	}
@@ -62,8 +115,8 @@ func (r *Repository) UpdateVacation(ctx context.Context, v *domain.Vacation) err
 }
 
 func (r *Repository) DeleteVacation(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM vacations WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM vacations WHERE id = $1 AND ($2::uuid IS NULL OR ` + fmt.Sprintf(userInScope, 2) + `)`
+	res, err := r.db.ExecContext(ctx, query, id, companyScope(ctx))
 	if err != nil {
 		return err
 	}
@@ -9,37 +9,57 @@ import (
 	"github.com/google/uuid"
 )
 
-var secretKey = []byte("SUPER_SECRET_KEY_CHANGE_ME") // In production, use env var
+// keys signs and verifies the tokens; set once at startup with SetKeySet.
+var keys *KeySet
+
+var ErrNoKeys = errors.New("no JWT keys configured")
+
+func SetKeySet(ks *KeySet) {
+	keys = ks
+}
+
+// AccessTokenTTL is short; sessions go on through refresh tokens.
+const AccessTokenTTL = 15 * time.Minute
 
 type Claims struct {
-	UserID uuid.UUID   `json:"user_id"`
-	Role   domain.Role `json:"role"`
+	UserID    uuid.UUID   `json:"user_id"`
+	CompanyID uuid.UUID   `json:"company_id"` // Tenant the requests are scoped to
+	Role      domain.Role `json:"role"`
+	// domain.User.TokenVersion when the token was issued
+	TokenVersion int `json:"ver"`
 	jwt.RegisteredClaims
 }
 
+// GenerateToken signs the claims of the user with the signing key of the
+// key set, whose id goes in the kid header.
 func GenerateToken(user *domain.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	if keys == nil {
+		return "", ErrNoKeys
+	}
+	expirationTime := time.Now().Add(AccessTokenTTL)
 	claims := &Claims{
-		UserID: user.ID,
-		Role:   user.Role,
+		UserID:       user.ID,
+		CompanyID:    user.CompanyID,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
+	token := jwt.NewWithClaims(keys.signing.Method, claims)
+	token.Header["kid"] = keys.signing.ID
+	return token.SignedString(keys.signing.sign)
 }
 
+// ValidateToken accepts tokens signed with any key of the key set.
 func ValidateToken(tokenString string) (*Claims, error) {
+	if keys == nil {
+		return nil, ErrNoKeys
+	}
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.keyFunc)
 
 	if err != nil {
 		return nil, err
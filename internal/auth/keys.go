@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a signing or verification key identified by the kid header of the
+// tokens. Public keys only verify; private keys and secrets also sign.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   interface{} // Nil for public keys
+	verify interface{}
+}
+
+// NewHMACKey returns an HS256 key. The secret should have at least 32 bytes.
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{ID: id, Method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+}
+
+// ParsePEMKey reads an RSA (RS256) or Ed25519 (EdDSA) key: a private key in
+// PKCS#1 or PKCS#8 form, or a public key in PKIX form.
+func ParsePEMKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM block found", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, sign: k, verify: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, verify: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, sign: k, verify: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, verify: k}, nil
+	}
+	return nil, fmt.Errorf("key %s: only RSA and Ed25519 keys are supported", id)
+}
+
+// KeySet signs tokens with one key and verifies them with any of its keys,
+// so a new signing key can be rolled out while the tokens signed with the
+// previous one are still valid.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+func NewKeySet(keys []*Key, signingKeyID string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, k := range keys {
+		if _, ok := ks.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key %s", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	signing, ok := ks.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+	if signing.sign == nil {
+		return nil, fmt.Errorf("signing key %q is a public key", signingKeyID)
+	}
+	ks.signing = signing
+	return ks, nil
+}
+
+// LoadKeySet reads the keys of dir. The file name without extension is the
+// kid: <kid>.pem holds an RSA or Ed25519 key, <kid>.secret an HMAC secret.
+// Other files are ignored.
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*Key
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".pem" && ext != ".secret") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSuffix(e.Name(), ext)
+		if ext == ".secret" {
+			keys = append(keys, NewHMACKey(id, []byte(strings.TrimSpace(string(data)))))
+			continue
+		}
+		key, err := ParsePEMKey(id, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return NewKeySet(keys, signingKeyID)
+}
+
+// EphemeralKeySet returns a random HMAC key for local development. Tokens
+// stop being valid when the process restarts.
+func EphemeralKeySet() (*KeySet, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return NewKeySet([]*Key{NewHMACKey("ephemeral", secret)}, "ephemeral")
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[id]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	// The algorithm comes from the key, never from the token
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.verify, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys other services verify the tokens with.
+// HMAC secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	encode := base64.RawURLEncoding.EncodeToString
+	for _, k := range ks.keys {
+		jwk := JWK{KeyID: k.ID, Algorithm: k.Method.Alg(), Use: "sig"}
+		switch pub := k.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType, jwk.N, jwk.E = "RSA", encode(pub.N.Bytes()), encode(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType, jwk.Curve, jwk.X = "OKP", "Ed25519", encode(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// testKeys are an RSA signing key, an Ed25519 key being rotated out and an
+// HMAC secret, with the PEM encoding of the public RSA key.
+type testKeys struct {
+	rsa       *rsa.PrivateKey
+	ed        ed25519.PrivateKey
+	secret    []byte
+	rsaPubPEM []byte
+	set       *KeySet
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPKCS8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k := &testKeys{
+		rsa:       rsaKey,
+		ed:        edKey,
+		secret:    []byte("0123456789abcdef0123456789abcdef"),
+		rsaPubPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}),
+	}
+	rsaParsed, err := ParsePEMKey("rsa-1", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edParsed, err := ParsePEMKey("ed-1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPKCS8}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.set, err = NewKeySet([]*Key{rsaParsed, edParsed, NewHMACKey("hs-1", k.secret)}, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestValidateToken(t *testing.T) {
+	k := newTestKeys(t)
+	SetKeySet(k.set)
+	t.Cleanup(func() { SetKeySet(nil) })
+
+	user := &domain.User{ID: uuid.New(), CompanyID: uuid.New(), Role: domain.RoleEmployee, TokenVersion: 3}
+	claims := func(expiresIn time.Duration) *Claims {
+		return &Claims{
+			UserID:           user.ID,
+			CompanyID:        user.CompanyID,
+			Role:             user.Role,
+			TokenVersion:     user.TokenVersion,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn))},
+		}
+	}
+	sign := func(method jwt.SigningMethod, kid string, key interface{}, expiresIn time.Duration) string {
+		token := jwt.NewWithClaims(method, claims(expiresIn))
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	issued, err := GenerateToken(user)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"issued", issued, true},
+		{"previous key", sign(jwt.SigningMethodEdDSA, "ed-1", k.ed, time.Minute), true},
+		{"HMAC key", sign(jwt.SigningMethodHS256, "hs-1", k.secret, time.Minute), true},
+		{"expired", sign(jwt.SigningMethodRS256, "rsa-1", k.rsa, -time.Minute), false},
+		{"unknown kid", sign(jwt.SigningMethodRS256, "rsa-2", k.rsa, time.Minute), false},
+		{"no kid", sign(jwt.SigningMethodRS256, "", k.rsa, time.Minute), false},
+		// The public RSA key used as an HMAC secret: the algorithm comes from the key
+		{"HS256 with an RSA kid", sign(jwt.SigningMethodHS256, "rsa-1", k.rsaPubPEM, time.Minute), false},
+		{"RS256 with an HMAC kid", sign(jwt.SigningMethodRS256, "hs-1", k.rsa, time.Minute), false},
+		{"EdDSA with an RSA kid", sign(jwt.SigningMethodEdDSA, "rsa-1", k.ed, time.Minute), false},
+		{"unsigned", sign(jwt.SigningMethodNone, "rsa-1", jwt.UnsafeAllowNoneSignatureType, time.Minute), false},
+		{"tampered", issued[:len(issued)-4] + "AAAA", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateToken(tt.token)
+			if !tt.valid {
+				if err == nil {
+					t.Fatal("ValidateToken accepted the token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if got.UserID != user.ID || got.CompanyID != user.CompanyID || got.TokenVersion != 3 {
+				t.Errorf("claims = %+v, want those of the user", got)
+			}
+		})
+	}
+}
+
+func TestValidateTokenWithoutKeys(t *testing.T) {
+	SetKeySet(nil)
+	if _, err := GenerateToken(&domain.User{}); err != ErrNoKeys {
+		t.Errorf("GenerateToken err = %v, want ErrNoKeys", err)
+	}
+	if _, err := ValidateToken("a.b.c"); err != ErrNoKeys {
+		t.Errorf("ValidateToken err = %v, want ErrNoKeys", err)
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	k := newTestKeys(t)
+	jwks := k.set.JWKS()
+
+	// The HMAC secret is left out
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want 2", len(jwks.Keys))
+	}
+	ed, rsaKey := jwks.Keys[0], jwks.Keys[1]
+	if ed.KeyID != "ed-1" || ed.KeyType != "OKP" || ed.Curve != "Ed25519" || ed.Algorithm != "EdDSA" || ed.Use != "sig" {
+		t.Errorf("Ed25519 key = %+v", ed)
+	}
+	if x, _ := base64.RawURLEncoding.DecodeString(ed.X); !ed25519.PublicKey(x).Equal(k.ed.Public()) {
+		t.Error("the Ed25519 key does not match")
+	}
+	if rsaKey.KeyID != "rsa-1" || rsaKey.KeyType != "RSA" || rsaKey.Algorithm != "RS256" {
+		t.Errorf("RSA key = %+v", rsaKey)
+	}
+	n, _ := base64.RawURLEncoding.DecodeString(rsaKey.N)
+	e, _ := base64.RawURLEncoding.DecodeString(rsaKey.E)
+	if new(big.Int).SetBytes(n).Cmp(k.rsa.N) != 0 || int(new(big.Int).SetBytes(e).Int64()) != k.rsa.E {
+		t.Error("the RSA key does not match")
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	k := newTestKeys(t)
+	public, err := ParsePEMKey("rsa-pub", k.rsaPubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmac := NewHMACKey("hs-1", k.secret)
+
+	tests := []struct {
+		name    string
+		keys    []*Key
+		signing string
+		valid   bool
+	}{
+		{"public key verifying", []*Key{hmac, public}, "hs-1", true},
+		{"duplicate kid", []*Key{hmac, NewHMACKey("hs-1", k.secret)}, "hs-1", false},
+		{"unknown signing key", []*Key{hmac}, "hs-2", false},
+		{"public signing key", []*Key{hmac, public}, "rsa-pub", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeySet(tt.keys, tt.signing); (err == nil) != tt.valid {
+				t.Errorf("NewKeySet err = %v, want valid = %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestParsePEMKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not PEM", []byte("secret")},
+		{"certificate", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}})},
+		{"corrupt key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})},
+		{"ECDSA key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePEMKey("k", tt.data); err == nil {
+				t.Error("ParsePEMKey accepted the key")
+			}
+		})
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	k := newTestKeys(t)
+	dir := t.TempDir()
+	files := map[string]string{
+		"current.secret": "  0123456789abcdef0123456789abcdef\n",
+		"previous.pem":   string(k.rsaPubPEM),
+		"README":         "ignored",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ks, err := LoadKeySet(dir, "current")
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	if len(ks.keys) != 2 || ks.signing.ID != "current" || string(ks.signing.sign.([]byte)) != string(k.secret) {
+		t.Errorf("key set = %+v, want the trimmed secret signing and the public key", ks.keys)
+	}
+	if _, err := LoadKeySet(dir, "previous"); err == nil {
+		t.Error("LoadKeySet signs with a public key")
+	}
+}
@@ -12,6 +12,23 @@ type Config struct {
 	DBPort     string
 	DBName     string
 	ServerPort string
+	PublicURL  string // Base URL used to build links handed out to clients
+	// JSON file with yearly payroll tax tables that replace or add to the built-in ones
+	TaxTablesFile string
+	// Token keys: a directory of <kid>.pem / <kid>.secret files and the kid
+	// that signs, or a single HMAC secret. Without both, a random key is used.
+	JWTKeysDir      string
+	JWTSigningKeyID string
+	JWTSecret       string
+	// Base URL of the web app, for the links in emails (default PublicURL)
+	AppURL string
+	// Outgoing mail. Without SMTPHost, emails are written to MailDir (or logged).
+	SMTPHost string
+	SMTPPort string
+	SMTPUser string
+	SMTPPass string
+	MailFrom string
+	MailDir  string
 }
 
 func LoadConfig() (*Config, error) {
@@ -23,7 +40,20 @@ func LoadConfig() (*Config, error) {
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBName:     getEnv("DB_NAME", "myteam"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+		// Optional
+		TaxTablesFile:   getEnv("TAX_TABLES_FILE", ""),
+		JWTKeysDir:      getEnv("JWT_KEYS_DIR", ""),
+		JWTSigningKeyID: getEnv("JWT_SIGNING_KEY_ID", ""),
+		JWTSecret:       getEnv("JWT_SECRET", ""),
+		SMTPHost:        getEnv("SMTP_HOST", ""),
+		SMTPPort:        getEnv("SMTP_PORT", "587"),
+		SMTPUser:        getEnv("SMTP_USER", ""),
+		SMTPPass:        getEnv("SMTP_PASS", ""),
+		MailFrom:        getEnv("MAIL_FROM", "MyTeam <no-reply@localhost>"),
+		MailDir:         getEnv("MAIL_DIR", ""),
 	}
+	cfg.PublicURL = getEnv("PUBLIC_URL", "http://localhost:"+cfg.ServerPort)
+	cfg.AppURL = getEnv("APP_URL", cfg.PublicURL)
 	return cfg, nil
 }
 
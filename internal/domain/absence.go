@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamAbsence is an absence together with the employee it belongs to, as
+// shown in the team calendar.
+type TeamAbsence struct {
+	*Vacation
+	UserName      string `json:"user_name"`
+	LeaveTypeName string `json:"leave_type_name"`
+}
+
+// AbsenceLabel is how colleagues see the absences that are not vacations. The
+// leave type (e.g. sick leave) is health data.
+const AbsenceLabel = "Ausencia"
+
+// Redacted returns the absence as colleagues see it: vacations keep their
+// name, other leave types become AbsenceLabel, and the attachment and
+// decision details are dropped.
+func (a *TeamAbsence) Redacted() *TeamAbsence {
+	v := *a.Vacation
+	v.AttachmentURL, v.RejectionReason = nil, nil
+	redacted := &TeamAbsence{Vacation: &v, UserName: a.UserName, LeaveTypeName: a.LeaveTypeName}
+	if v.LeaveTypeID != nil {
+		v.LeaveTypeID = nil
+		redacted.LeaveTypeName = AbsenceLabel
+	}
+	return redacted
+}
+
+// RedactAbsences applies Redacted to every absence.
+func RedactAbsences(absences []*TeamAbsence) []*TeamAbsence {
+	redacted := make([]*TeamAbsence, len(absences))
+	for i, a := range absences {
+		redacted[i] = a.Redacted()
+	}
+	return redacted
+}
+
+// CalendarFeed grants read access to the company absences through a secret
+// URL that calendar clients can subscribe to. Only the token hash is stored.
+type CalendarFeed struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CompanyID uuid.UUID `json:"company_id"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
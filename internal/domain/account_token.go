@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountTokenPurpose tells what an emailed account token allows.
+type AccountTokenPurpose string
+
+const (
+	// AccountTokenInvitation lets an invited user choose their password.
+	AccountTokenInvitation AccountTokenPurpose = "INVITATION"
+	// AccountTokenPasswordReset lets a user who forgot their password set a new one.
+	AccountTokenPasswordReset AccountTokenPurpose = "PASSWORD_RESET"
+)
+
+// TTL is how long a token of the purpose can be used after it is sent.
+func (p AccountTokenPurpose) TTL() time.Duration {
+	if p == AccountTokenInvitation {
+		return 7 * 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// AccountToken is a single-use token sent by email to set the password of a
+// user. Only its hash is stored; issuing a new one replaces the unused
+// tokens of the same user and purpose.
+type AccountToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CompanyID uuid.UUID // Of the user, read along with the token to scope the request presenting it
+	Purpose   AccountTokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewAccountToken returns the token to store and the secret to email.
+func NewAccountToken(userID uuid.UUID, purpose AccountTokenPurpose, now time.Time) (*AccountToken, string, error) {
+	token, hash, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	return &AccountToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: now.Add(purpose.TTL()),
+		CreatedAt: now,
+	}, token, nil
+}
+
+func HashAccountToken(token string) string {
+	return hashSecret(token)
+}
+
+// IsActive tells whether the token can still set a password.
+func (t *AccountToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "strings"
+
+// ibanLengths are the IBAN lengths of the SEPA countries.
+var ibanLengths = map[string]int{
+	"AD": 24, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "EE": 20,
+	"ES": 24, "FI": 18, "FR": 27, "GB": 22, "GI": 23, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IS": 26,
+	"IT": 27, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MT": 31, "NL": 18, "NO": 15, "PL": 28,
+	"PT": 25, "RO": 24, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "VA": 22,
+}
+
+// BankAccount is the account salaries are paid into, or paid from for a
+// company. BIC is optional within SEPA.
+type BankAccount struct {
+	IBAN string `json:"iban"`
+	BIC  string `json:"bic,omitempty"`
+}
+
+// NewBankAccount normalizes the IBAN (no spaces, upper case) and checks its
+// country, length and ISO 7064 mod 97 check digits.
+func NewBankAccount(iban, bic string) (*BankAccount, error) {
+	iban = strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+
+	v := &ValidationError{}
+	if !validIBAN(iban) {
+		v.Add("iban", "is not a valid SEPA IBAN")
+	}
+	if bic != "" && !validBIC(bic) {
+		v.Add("bic", "must have 8 or 11 characters")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return &BankAccount{IBAN: iban, BIC: bic}, nil
+}
+
+func validIBAN(iban string) bool {
+	if len(iban) < 4 || ibanLengths[iban[:2]] != len(iban) {
+		return false
+	}
+	// Move the country and check digits to the end, read letters as 10..35
+	// and compute the remainder piecewise so it fits in an int.
+	rem := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+func validBIC(bic string) bool {
+	if len(bic) != 8 && len(bic) != 11 {
+		return false
+	}
+	for i, r := range bic {
+		letter := r >= 'A' && r <= 'Z'
+		if !letter && (i < 6 || r < '0' || r > '9') { // Bank and country codes are letters
+			return false
+		}
+	}
+	return true
+}
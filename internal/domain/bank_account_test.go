@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewBankAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		iban, bic string
+		wantIBAN  string
+		wantField string // Empty when the account is valid
+	}{
+		{"Spanish IBAN", "ES9121000418450200051332", "", "ES9121000418450200051332", ""},
+		{"spaces and lower case", "es91 2100 0418 4502 0005 1332", "caixesbbxxx", "ES9121000418450200051332", ""},
+		{"German IBAN", "DE89370400440532013000", "", "DE89370400440532013000", ""},
+		{"bad check digits", "ES9221000418450200051332", "", "", "iban"},
+		{"swapped digits", "ES9121000418450200051323", "", "", "iban"},
+		{"too short", "ES912100041845020005133", "", "", "iban"},
+		{"outside SEPA", "US64SVBKUS6S3300958879", "", "", "iban"},
+		{"symbol", "ES91-2100-0418-4502-0005-1332", "", "", "iban"},
+		{"empty", "", "", "", "iban"},
+		{"8-character BIC", "ES9121000418450200051332", "CAIXESBB", "ES9121000418450200051332", ""},
+		{"BIC of 9 characters", "ES9121000418450200051332", "CAIXESBBX", "", "bic"},
+		{"digit in the bank code", "ES9121000418450200051332", "CA1XESBB", "", "bic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := NewBankAccount(tt.iban, tt.bic)
+			if tt.wantField != "" {
+				var v *ValidationError
+				if !errors.As(err, &v) || !hasField(v, tt.wantField) {
+					t.Fatalf("err = %v, want an error on %s", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewBankAccount: %v", err)
+			}
+			if account.IBAN != tt.wantIBAN {
+				t.Errorf("IBAN = %s, want %s", account.IBAN, tt.wantIBAN)
+			}
+		})
+	}
+}
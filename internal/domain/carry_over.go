@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VacationPolicy holds the company rules for unused vacation days.
+// Spanish practice allows rolling them into the first months of next year.
+type VacationPolicy struct {
+	CompanyID        uuid.UUID `json:"company_id"`
+	MaxCarryOverDays float64   `json:"max_carry_over_days"` // 0 disables carry-over
+	ExpiryMonth      int       `json:"expiry_month"`        // Last day carried days can be used,
+	ExpiryDay        int       `json:"expiry_day"`          // within the year after they were earned
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DefaultVacationPolicy applies to companies that never configured one.
+func DefaultVacationPolicy(companyID uuid.UUID) *VacationPolicy {
+	return &VacationPolicy{
+		CompanyID:   companyID,
+		ExpiryMonth: int(time.March),
+		ExpiryDay:   31,
+	}
+}
+
+func NewVacationPolicy(companyID uuid.UUID, maxCarryOverDays float64, expiryMonth, expiryDay int) (*VacationPolicy, error) {
+	p := &VacationPolicy{
+		CompanyID:        companyID,
+		MaxCarryOverDays: maxCarryOverDays,
+		ExpiryMonth:      expiryMonth,
+		ExpiryDay:        expiryDay,
+		UpdatedAt:        time.Now(),
+	}
+	if maxCarryOverDays < 0 || expiryMonth < 1 || expiryMonth > 12 || expiryDay < 1 {
+		return nil, ErrInvalidInput
+	}
+	// Reject dates such as 02-30 that would silently roll over
+	if p.ExpiresAt(2001).Day() != expiryDay {
+		return nil, ErrInvalidInput
+	}
+	return p, nil
+}
+
+// ExpiresAt returns the last day, in the given year, to use carried days.
+func (p *VacationPolicy) ExpiresAt(year int) time.Time {
+	return time.Date(year, time.Month(p.ExpiryMonth), p.ExpiryDay, 0, 0, 0, 0, time.UTC)
+}
+
+// CarryOver returns how many of the remaining days may be carried.
+func (p *VacationPolicy) CarryOver(remaining float64) float64 {
+	return math.Max(0, math.Min(remaining, p.MaxCarryOverDays))
+}
+
+type CarryOverStatus string
+
+const (
+	CarryOverStatusActive  CarryOverStatus = "ACTIVE"
+	CarryOverStatusExpired CarryOverStatus = "EXPIRED"
+)
+
+// VacationCarryOver is the immutable record of a user's year close: the
+// balance snapshot, what was carried into the next year and what was lost.
+// Only the expiry fields change afterwards.
+type VacationCarryOver struct {
+	ID            uuid.UUID       `json:"id"`
+	UserID        uuid.UUID       `json:"user_id"`
+	CompanyID     uuid.UUID       `json:"company_id"`
+	FromYear      int             `json:"from_year"`
+	Entitled      float64         `json:"entitled"`
+	Used          float64         `json:"used"` // Approved and pending days of FromYear
+	Remaining     float64         `json:"remaining"`
+	CarriedDays   float64         `json:"carried_days"`
+	ForfeitedDays float64         `json:"forfeited_days"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+	Status        CarryOverStatus `json:"status"`
+	ExpiredDays   float64         `json:"expired_days"`
+	ExpiredAt     *time.Time      `json:"expired_at,omitempty"`
+	ClosedBy      *uuid.UUID      `json:"closed_by,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// NewVacationCarryOver closes the year of a balance under the given policy.
+func NewVacationCarryOver(companyID uuid.UUID, balance *VacationBalance, policy *VacationPolicy, closedBy uuid.UUID) *VacationCarryOver {
+	remaining := math.Max(0, balance.Remaining)
+	carried := policy.CarryOver(remaining)
+	return &VacationCarryOver{
+		ID:            uuid.New(),
+		UserID:        balance.UserID,
+		CompanyID:     companyID,
+		FromYear:      balance.Year,
+		Entitled:      balance.Entitled,
+		Used:          roundDays(balance.Approved + balance.Pending),
+		Remaining:     balance.Remaining,
+		CarriedDays:   roundDays(carried),
+		ForfeitedDays: roundDays(remaining - carried),
+		ExpiresAt:     policy.ExpiresAt(balance.Year + 1),
+		Status:        CarryOverStatusActive,
+		ClosedBy:      &closedBy,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// IsExpired reports whether the expiry day is over.
+func (c *VacationCarryOver) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt.AddDate(0, 0, 1))
+}
+
+// UnusedDays returns the carried days left after usedBeforeExpiry, since
+// vacations taken early in the year consume carried days first.
+func (c *VacationCarryOver) UnusedDays(usedBeforeExpiry float64) float64 {
+	return roundDays(math.Max(0, c.CarriedDays-usedBeforeExpiry))
+}
+
+// Expire records how many carried days were lost.
+func (c *VacationCarryOver) Expire(expiredDays float64, now time.Time) {
+	c.Status = CarryOverStatusExpired
+	c.ExpiredDays = expiredDays
+	c.ExpiredAt = &now
+}
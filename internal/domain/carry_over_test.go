@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewVacationPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		max        float64
+		month, day int
+		want       error
+	}{
+		{"end of March", 5, 3, 31, nil},
+		{"disabled", 0, 6, 30, nil},
+		{"negative days", -1, 3, 31, ErrInvalidInput},
+		{"month 13", 5, 13, 1, ErrInvalidInput},
+		{"day 0", 5, 3, 0, ErrInvalidInput},
+		{"30 February", 5, 2, 30, ErrInvalidInput},
+		// Not every year has one
+		{"29 February", 5, 2, 29, ErrInvalidInput},
+		{"31 April", 5, 4, 31, ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVacationPolicy(uuid.New(), tt.max, tt.month, tt.day); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewVacationCarryOver(t *testing.T) {
+	policy := &VacationPolicy{MaxCarryOverDays: 5, ExpiryMonth: int(time.March), ExpiryDay: 31}
+	tests := []struct {
+		name      string
+		policy    *VacationPolicy
+		remaining float64
+		carried   float64
+		forfeited float64
+	}{
+		{"above the maximum", policy, 8, 5, 3},
+		{"below the maximum", policy, 3.5, 3.5, 0},
+		{"overdrawn", policy, -2, 0, 0},
+		{"carry-over disabled", &VacationPolicy{ExpiryMonth: int(time.March), ExpiryDay: 31}, 4, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance := &VacationBalance{UserID: uuid.New(), Year: 2024, Entitled: 22, Remaining: tt.remaining}
+			c := NewVacationCarryOver(uuid.New(), balance, tt.policy, uuid.New())
+			if c.CarriedDays != tt.carried || c.ForfeitedDays != tt.forfeited {
+				t.Errorf("carried = %.2f, forfeited = %.2f; want %.2f and %.2f", c.CarriedDays, c.ForfeitedDays, tt.carried, tt.forfeited)
+			}
+			if want := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC); !c.ExpiresAt.Equal(want) {
+				t.Errorf("expires at = %s, want %s", c.ExpiresAt, want)
+			}
+			if c.Status != CarryOverStatusActive {
+				t.Errorf("status = %s, want ACTIVE", c.Status)
+			}
+		})
+	}
+}
+
+func TestVacationCarryOverIsExpired(t *testing.T) {
+	c := &VacationCarryOver{ExpiresAt: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, time.March, 31, 23, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := c.IsExpired(tt.now); got != tt.want {
+			t.Errorf("IsExpired(%s) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestVacationBalanceApplyCarryOver(t *testing.T) {
+	expiresAt := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	before, after := expiresAt.AddDate(0, 0, -10), expiresAt.AddDate(0, 0, 1)
+
+	// 22 days of 2025 and 5 carried from 2024
+	tests := []struct {
+		name              string
+		status            CarryOverStatus
+		expiredDays       float64
+		approved          float64
+		usedBeforeExpiry  float64
+		now               time.Time
+		remaining         float64
+		available         float64
+		expired           float64
+		entitledRemaining float64 // For days after the expiry
+	}{
+		{"before the expiry", CarryOverStatusActive, 0, 3, 3, before, 24, 2, 0, 22},
+		{"after the expiry", CarryOverStatusActive, 0, 3, 3, after, 22, 0, 2, 22},
+		{"expired by the daily job", CarryOverStatusExpired, 2, 3, 3, after, 22, 0, 2, 22},
+		// The approved days fall after the expiry: the carried days are lost
+		{"used later in the year", CarryOverStatusActive, 0, 3, 0, after, 19, 0, 5, 19},
+		{"more used than carried", CarryOverStatusActive, 0, 8, 8, before, 19, 0, 0, 19},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &VacationBalance{Year: 2025, Entitled: 22, Remaining: 22}
+			b.Consume(VacationStatusApproved, tt.approved)
+			c := &VacationCarryOver{CarriedDays: 5, ExpiresAt: expiresAt, Status: tt.status, ExpiredDays: tt.expiredDays}
+			b.ApplyCarryOver(c, tt.usedBeforeExpiry, tt.now)
+
+			if b.Remaining != tt.remaining || b.CarryOverAvailable != tt.available || b.CarryOverExpired != tt.expired {
+				t.Errorf("remaining = %.2f, available = %.2f, expired = %.2f; want %.2f, %.2f and %.2f",
+					b.Remaining, b.CarryOverAvailable, b.CarryOverExpired, tt.remaining, tt.available, tt.expired)
+			}
+			if got := b.EntitledRemaining(); got != tt.entitledRemaining {
+				t.Errorf("entitled remaining = %.2f, want %.2f", got, tt.entitledRemaining)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ type Company struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
 	CIF       string    `json:"cif"`
+	IBAN      string    `json:"-"` // Account salaries are paid from
+	BIC       string    `json:"-"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -27,47 +27,107 @@ type Contract struct {
 	StartDate time.Time    `json:"start_date"`
 	EndDate   *time.Time   `json:"end_date,omitempty"` // Pointer to allow null
 	Type      ContractType `json:"type"`
-	Position  string       `json:"position"`
-	Salary    float64      `json:"salary"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	// Legal ground of temporary contracts and modality of training ones
+	Cause         ContractCause `json:"cause,omitempty"`
+	Justification string        `json:"justification,omitempty"`
+	// Seasons worked under fixed-discontinuous contracts
+	ActivationPeriods []ActivationPeriod `json:"activation_periods,omitempty"`
+	Position          string             `json:"position"`
+	Salary            float64            `json:"salary"`
+	// Ordinary working time; PartTimePercentage is 100 for full-time contracts
+	WeeklyHours        float64 `json:"weekly_hours"`
+	PartTimePercentage float64 `json:"part_time_percentage"`
+	// Social Security contribution group (1 to 11), 0 until it is set
+	ContributionGroup int `json:"contribution_group,omitempty"`
+	// Kept up to date daily by a background job
+	Status    ContractStatus `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	// Changes of the terms above, which are the ones signed originally
+	Amendments []*ContractAmendment `json:"-"`
+	// Set once the contract is terminated; brings the end date forward
+	Termination *ContractTermination `json:"termination,omitempty"`
 }
 
-func NewContract(userID uuid.UUID, startDate time.Time, endDate *time.Time, contractType ContractType, position string, salary float64) (*Contract, error) {
+// NewContract validates the contract against the rules of its type and
+// returns a ValidationError listing every invalid field. Indefinite and
+// fixed-discontinuous contracts have no end date.
+func NewContract(userID uuid.UUID, startDate time.Time, endDate *time.Time, contractType ContractType, cause ContractCause, justification string, activationPeriods []ActivationPeriod, position string, salary float64) (*Contract, error) {
+	if contractType == ContractTypeIndefinite || contractType == ContractTypeFixedDiscontinuous {
+		endDate = nil
+	}
+	if contractType != ContractTypeTemporary && contractType != ContractTypeTraining {
+		cause, justification = "", ""
+	}
+	if contractType != ContractTypeFixedDiscontinuous {
+		activationPeriods = nil
+	}
+
+	c := &Contract{
+		ID:                uuid.New(),
+		UserID:            userID,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Type:              contractType,
+		Cause:             cause,
+		Justification:     justification,
+		ActivationPeriods: activationPeriods,
+		Position:          position,
+		Salary:            salary,
+		// Full-time until SetWorkingHours says otherwise
+		WeeklyHours:        FullTimeWeeklyHours,
+		PartTimePercentage: 100,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	v := &ValidationError{}
 	if position == "" {
-		return nil, errors.New("position is required")
+		v.Add("position", "is required")
 	}
 	if salary < 0 {
-		return nil, errors.New("salary cannot be negative")
+		v.Add("salary", "cannot be negative")
+	}
+	c.validateTypeRules(0, v)
+	if err := v.Err(); err != nil {
+		return nil, err
 	}
+	c.Status = c.StatusOn(c.CreatedAt)
+	return c, nil
+}
 
-	// Logic: If Indefinite, EndDate must be null (or we force it to null)
-	if contractType == ContractTypeIndefinite {
-		endDate = nil
-	} else {
-		// Logic: Others must have EndDate? The prompt said:
-		// "Contrato indefinido: No tiene fecha fin"
-		// "Contrato temporal: Tiene fecha fin" (implies required)
-		// Let's enforce it if strictly required, or let service handle it.
-		// For now, I'll leave it loose here or strictly enforce if the requirement implies strictness.
-		// "Tiene fecha fin" sounds strict.
-		if endDate == nil {
-			return nil, errors.New("end date is required for this contract type")
-		}
-		if endDate.Before(startDate) {
-			return nil, errors.New("end date cannot be before start date")
-		}
+// SetWorkingHours sets the contracted weekly hours. Either value may be zero
+// to derive it from the other one; both zero means full-time.
+func (c *Contract) SetWorkingHours(weeklyHours, partTimePercentage float64) error {
+	switch {
+	case weeklyHours == 0 && partTimePercentage == 0:
+		weeklyHours, partTimePercentage = FullTimeWeeklyHours, 100
+	case weeklyHours == 0:
+		weeklyHours = roundHours(FullTimeWeeklyHours * partTimePercentage / 100)
+	case partTimePercentage == 0:
+		partTimePercentage = roundHours(weeklyHours / FullTimeWeeklyHours * 100)
+	}
+	v := &ValidationError{}
+	if weeklyHours <= 0 || weeklyHours > FullTimeWeeklyHours {
+		v.Add("weekly_hours", "must be greater than 0 and at most 40")
+	}
+	if partTimePercentage <= 0 || partTimePercentage > 100 {
+		v.Add("part_time_percentage", "must be greater than 0 and at most 100")
 	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	c.WeeklyHours = weeklyHours
+	c.PartTimePercentage = partTimePercentage
+	return nil
+}
 
-	return &Contract{
-		ID:        uuid.New(),
-		UserID:    userID,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Type:      contractType,
-		Position:  position,
-		Salary:    salary,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}, nil
+// SetContributionGroup sets the Social Security contribution group; 0 leaves
+// it unset.
+func (c *Contract) SetContributionGroup(group int) error {
+	if group < 0 || group > ContributionGroups {
+		return &ValidationError{Fields: []FieldError{{Field: "contribution_group", Message: "must be between 1 and 11"}}}
+	}
+	c.ContributionGroup = group
+	return nil
 }
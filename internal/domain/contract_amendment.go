@@ -0,0 +1,287 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrContractHasAmendments prevents deleting a contract, and with it the
+// history of its terms.
+var ErrContractHasAmendments = errors.New("contract has amendments")
+
+// ContractChanges are the terms of a contract an amendment can change. Nil
+// fields are left as they were; converting a contract into an indefinite one
+// removes its end date.
+type ContractChanges struct {
+	Type               *ContractType `json:"type,omitempty"`
+	EndDate            *time.Time    `json:"end_date,omitempty"`
+	Position           *string       `json:"position,omitempty"`
+	Salary             *float64      `json:"salary,omitempty"`
+	WeeklyHours        *float64      `json:"weekly_hours,omitempty"`
+	PartTimePercentage *float64      `json:"part_time_percentage,omitempty"`
+}
+
+// ContractAmendment changes the terms of a contract from EffectiveDate on,
+// e.g. a promotion or a pay rise. Amendments are never updated or deleted so
+// the history of the contract can be rebuilt at any date.
+type ContractAmendment struct {
+	ID            uuid.UUID `json:"id"`
+	ContractID    uuid.UUID `json:"contract_id"`
+	EffectiveDate time.Time `json:"effective_date"`
+	ContractChanges
+	Reason    string     `json:"reason"`
+	AuthorID  *uuid.UUID `json:"author_id,omitempty"` // Nil once the author is deleted
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (a *ContractAmendment) applyTo(c *Contract) {
+	if a.Type != nil {
+		c.Type = *a.Type
+		if c.Type == ContractTypeIndefinite || c.Type == ContractTypeFixedDiscontinuous {
+			c.EndDate = nil
+		}
+	}
+	if a.EndDate != nil {
+		c.EndDate = a.EndDate
+	}
+	if a.Position != nil {
+		c.Position = *a.Position
+	}
+	if a.Salary != nil {
+		c.Salary = *a.Salary
+	}
+	if a.WeeklyHours != nil {
+		c.WeeklyHours = *a.WeeklyHours
+	}
+	if a.PartTimePercentage != nil {
+		c.PartTimePercentage = *a.PartTimePercentage
+	}
+}
+
+// Amend records a change of the terms in force from effectiveDate on. Only
+// the fields that actually differ from those terms are kept; an amendment
+// that changes nothing is invalid.
+func (c *Contract) Amend(effectiveDate time.Time, changes ContractChanges, reason string, authorID uuid.UUID, now time.Time) (*ContractAmendment, error) {
+	v := &ValidationError{}
+	if reason == "" {
+		v.Add("reason", "is required")
+	}
+	before := c.AsOf(effectiveDate)
+	switch {
+	case effectiveDate.Before(c.StartDate):
+		v.Add("effective_date", "cannot be before the start of the contract")
+	case before.EndDate != nil && effectiveDate.After(*before.EndDate):
+		v.Add("effective_date", "the contract is over")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	after := *before
+	if changes.Type != nil {
+		after.Type = *changes.Type
+	}
+	if changes.EndDate != nil {
+		after.EndDate = changes.EndDate
+	}
+	if after.Type == ContractTypeIndefinite || after.Type == ContractTypeFixedDiscontinuous {
+		after.EndDate = nil
+	}
+	if changes.Position != nil {
+		after.Position = *changes.Position
+	}
+	if changes.Salary != nil {
+		after.Salary = *changes.Salary
+	}
+	if changes.WeeklyHours != nil || changes.PartTimePercentage != nil {
+		var weeklyHours, partTimePercentage float64
+		if changes.WeeklyHours != nil {
+			weeklyHours = *changes.WeeklyHours
+		}
+		if changes.PartTimePercentage != nil {
+			partTimePercentage = *changes.PartTimePercentage
+		}
+		if err := after.SetWorkingHours(weeklyHours, partTimePercentage); err != nil {
+			return nil, err
+		}
+	}
+	if after.Position == "" {
+		v.Add("position", "is required")
+	}
+	if after.Salary < 0 {
+		v.Add("salary", "cannot be negative")
+	}
+	if after.EndDate != nil && after.EndDate.Before(effectiveDate) {
+		v.Add("end_date", "cannot be before the effective date")
+	}
+	// Changing the type or the end date must respect the rules of the type.
+	// Durations are measured from the start of the contract.
+	if after.Type != before.Type || !sameDate(after.EndDate, before.EndDate) {
+		extensions := c.extensions()
+		if after.EndDate != nil && before.EndDate != nil && after.EndDate.After(*before.EndDate) {
+			extensions++
+		}
+		after.validateTypeRules(extensions, v)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	a := &ContractAmendment{
+		ID:            uuid.New(),
+		ContractID:    c.ID,
+		EffectiveDate: effectiveDate,
+		Reason:        reason,
+		AuthorID:      &authorID,
+		CreatedAt:     now,
+	}
+	changed := false
+	if after.Type != before.Type {
+		a.Type, changed = &after.Type, true
+	}
+	if after.EndDate != nil && !sameDate(after.EndDate, before.EndDate) {
+		a.EndDate, changed = after.EndDate, true
+	}
+	if after.Position != before.Position {
+		a.Position, changed = &after.Position, true
+	}
+	if after.Salary != before.Salary {
+		a.Salary, changed = &after.Salary, true
+	}
+	if after.WeeklyHours != before.WeeklyHours || after.PartTimePercentage != before.PartTimePercentage {
+		a.WeeklyHours, a.PartTimePercentage, changed = &after.WeeklyHours, &after.PartTimePercentage, true
+	}
+	if !changed {
+		v.Add("changes", "the amendment does not change any term")
+		return nil, v
+	}
+
+	c.Amendments = append(c.Amendments, a)
+	return a, nil
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// sortedAmendments returns the amendments in the order they take effect.
+func (c *Contract) sortedAmendments() []*ContractAmendment {
+	sorted := append([]*ContractAmendment(nil), c.Amendments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].EffectiveDate.Equal(sorted[j].EffectiveDate) {
+			return sorted[i].EffectiveDate.Before(sorted[j].EffectiveDate)
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+// AsOf returns a copy of the contract with the terms in force on date.
+func (c *Contract) AsOf(date time.Time) *Contract {
+	terms := *c
+	for _, a := range c.sortedAmendments() {
+		if a.EffectiveDate.After(date) {
+			break
+		}
+		a.applyTo(&terms)
+	}
+	if c.Termination != nil {
+		c.Termination.applyTo(&terms)
+	}
+	return &terms
+}
+
+// periods splits the contract into one copy per version of its terms. Each
+// copy starts on the day the version takes effect and ends the day before
+// the next one, or on its own end date.
+func (c *Contract) periods() []*Contract {
+	timeline := c.Timeline()
+	periods := make([]*Contract, 0, len(timeline))
+	for i, v := range timeline {
+		p := c.AsOf(v.EffectiveDate)
+		p.StartDate = v.EffectiveDate
+		if i+1 < len(timeline) {
+			next := timeline[i+1].EffectiveDate.AddDate(0, 0, -1)
+			if p.EndDate == nil || next.Before(*p.EndDate) {
+				p.EndDate = &next
+			}
+		}
+		periods = append(periods, p)
+	}
+	return periods
+}
+
+// ContractVersion is the set of terms of a contract in force from
+// EffectiveDate until the next version.
+type ContractVersion struct {
+	EffectiveDate      time.Time          `json:"effective_date"`
+	Type               ContractType       `json:"type"`
+	EndDate            *time.Time         `json:"end_date,omitempty"`
+	Position           string             `json:"position"`
+	Salary             float64            `json:"salary"`
+	WeeklyHours        float64            `json:"weekly_hours"`
+	PartTimePercentage float64            `json:"part_time_percentage"`
+	Amendment          *ContractAmendment `json:"amendment,omitempty"` // Nil for the terms signed originally
+}
+
+// Timeline returns the terms signed originally followed by one version per
+// amendment, in the order they take effect.
+func (c *Contract) Timeline() []*ContractVersion {
+	version := func(terms *Contract, effectiveDate time.Time, a *ContractAmendment) *ContractVersion {
+		return &ContractVersion{
+			EffectiveDate:      effectiveDate,
+			Type:               terms.Type,
+			EndDate:            terms.EndDate,
+			Position:           terms.Position,
+			Salary:             terms.Salary,
+			WeeklyHours:        terms.WeeklyHours,
+			PartTimePercentage: terms.PartTimePercentage,
+			Amendment:          a,
+		}
+	}
+
+	terms := *c
+	timeline := []*ContractVersion{version(&terms, c.StartDate, nil)}
+	for _, a := range c.sortedAmendments() {
+		a.applyTo(&terms)
+		timeline = append(timeline, version(&terms, a.EffectiveDate, a))
+	}
+	if c.Termination != nil {
+		for _, v := range timeline {
+			if v.EndDate == nil || c.Termination.Date.Before(*v.EndDate) {
+				v.EndDate = &c.Termination.Date
+			}
+		}
+	}
+	return timeline
+}
+
+// ContractHistory is a contract with the terms in force at a date and every
+// version of its terms since it was signed.
+type ContractHistory struct {
+	*Contract
+	Timeline []*ContractVersion `json:"timeline"`
+}
+
+func (c *Contract) History(date time.Time) *ContractHistory {
+	return &ContractHistory{Contract: c.AsOf(date), Timeline: c.Timeline()}
+}
+
+// CurrentContract returns the contract in force on date with the terms of
+// that day, or nil if the user has no contract on that date.
+func CurrentContract(contracts []*Contract, date time.Time) *Contract {
+	for _, c := range contracts {
+		for _, p := range c.periods() {
+			if _, _, ok := p.overlap(date, date); ok {
+				return c.AsOf(date)
+			}
+		}
+	}
+	return nil
+}
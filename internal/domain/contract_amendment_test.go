@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestContractAmend(t *testing.T) {
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	ptr := func(d time.Time) *time.Time { return &d }
+	num := func(f float64) *float64 { return &f }
+	indefinite := ContractTypeIndefinite
+	// Extended once already, to the end of May
+	extended := &ContractAmendment{ID: uuid.New(), EffectiveDate: date(time.February, 1), ContractChanges: ContractChanges{EndDate: ptr(date(time.May, 31))}}
+
+	tests := []struct {
+		name      string
+		existing  []*ContractAmendment
+		effective time.Time
+		changes   ContractChanges
+		reason    string
+		wantField string // Empty when the amendment is valid
+	}{
+		{"pay rise", nil, date(time.February, 1), ContractChanges{Salary: num(22000)}, "Revisión salarial", ""},
+		{"extension", nil, date(time.March, 1), ContractChanges{EndDate: ptr(date(time.June, 30))}, "Prórroga", ""},
+		{"second extension", []*ContractAmendment{extended}, date(time.March, 1), ContractChanges{EndDate: ptr(date(time.June, 30))}, "Prórroga", "end_date"},
+		{"beyond six months", nil, date(time.March, 1), ContractChanges{EndDate: ptr(date(time.July, 31))}, "Prórroga", "end_date"},
+		{"converted into indefinite", nil, date(time.March, 1), ContractChanges{Type: &indefinite}, "Conversión", ""},
+		{"part time", nil, date(time.February, 1), ContractChanges{WeeklyHours: num(20)}, "Reducción de jornada", ""},
+		{"too many hours", nil, date(time.February, 1), ContractChanges{WeeklyHours: num(45)}, "Ampliación de jornada", "weekly_hours"},
+		{"before the start", nil, date(time.January, 1).AddDate(0, 0, -1), ContractChanges{Salary: num(22000)}, "Revisión salarial", "effective_date"},
+		{"after the end", nil, date(time.April, 1), ContractChanges{Salary: num(22000)}, "Revisión salarial", "effective_date"},
+		{"no reason", nil, date(time.February, 1), ContractChanges{Salary: num(22000)}, "", "reason"},
+		{"same salary", nil, date(time.February, 1), ContractChanges{Salary: num(20000)}, "Revisión salarial", "changes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Contract{
+				ID:                 uuid.New(),
+				StartDate:          date(time.January, 1),
+				EndDate:            ptr(date(time.March, 31)),
+				Type:               ContractTypeTemporary,
+				Cause:              CauseProductionCircumstances,
+				Justification:      "Campaña de rebajas",
+				Position:           "Dependiente",
+				Salary:             20000,
+				WeeklyHours:        40,
+				PartTimePercentage: 100,
+				Amendments:         tt.existing,
+			}
+			a, err := c.Amend(tt.effective, tt.changes, tt.reason, uuid.New(), date(time.January, 20))
+			if tt.wantField != "" {
+				var v *ValidationError
+				if !errors.As(err, &v) || !hasField(v, tt.wantField) {
+					t.Fatalf("Amend err = %v, want an error on %s", err, tt.wantField)
+				}
+				if len(c.Amendments) != len(tt.existing) {
+					t.Error("an invalid amendment was added to the contract")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Amend: %v", err)
+			}
+			if a.Position != nil {
+				t.Errorf("position = %q, want it left out", *a.Position)
+			}
+
+			terms := c.AsOf(tt.effective)
+			switch tt.name {
+			case "pay rise":
+				if terms.Salary != 22000 || c.AsOf(date(time.January, 31)).Salary != 20000 {
+					t.Errorf("salary = %.2f from the effective date, %.2f before", terms.Salary, c.AsOf(date(time.January, 31)).Salary)
+				}
+			case "converted into indefinite":
+				if terms.Type != ContractTypeIndefinite || terms.EndDate != nil || a.EndDate != nil {
+					t.Errorf("terms = %s ending %v, want an indefinite contract without end date", terms.Type, terms.EndDate)
+				}
+			case "part time":
+				if a.WeeklyHours == nil || *a.WeeklyHours != 20 || a.PartTimePercentage == nil || *a.PartTimePercentage != 50 {
+					t.Errorf("amendment = %+v, want 20 h and 50%%", a.ContractChanges)
+				}
+			}
+		})
+	}
+}
+
+func hasField(v *ValidationError, field string) bool {
+	for _, f := range v.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestContractAsOf(t *testing.T) {
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	text := func(s string) *string { return &s }
+	num := func(f float64) *float64 { return &f }
+	amendment := func(effective time.Time, created int, changes ContractChanges) *ContractAmendment {
+		return &ContractAmendment{ID: uuid.New(), EffectiveDate: effective, ContractChanges: changes, CreatedAt: date(time.January, created)}
+	}
+
+	// Recorded out of order; two take effect on the same day
+	c := &Contract{
+		ID:        uuid.New(),
+		StartDate: date(time.January, 1),
+		Type:      ContractTypeIndefinite,
+		Position:  "Dependiente",
+		Salary:    20000,
+		Amendments: []*ContractAmendment{
+			amendment(date(time.March, 1), 10, ContractChanges{Salary: num(22000)}),
+			amendment(date(time.February, 1), 20, ContractChanges{Position: text("Encargado")}),
+			amendment(date(time.March, 1), 5, ContractChanges{Salary: num(21000)}),
+		},
+	}
+
+	tests := []struct {
+		date     time.Time
+		position string
+		salary   float64
+	}{
+		{date(time.January, 15), "Dependiente", 20000},
+		{date(time.February, 1), "Encargado", 20000},
+		// The amendment recorded last wins
+		{date(time.March, 1), "Encargado", 22000},
+		{date(time.December, 31), "Encargado", 22000},
+	}
+	for _, tt := range tests {
+		terms := c.AsOf(tt.date)
+		if terms.Position != tt.position || terms.Salary != tt.salary {
+			t.Errorf("AsOf(%s) = %s, %.2f; want %s, %.2f", tt.date.Format(time.DateOnly), terms.Position, terms.Salary, tt.position, tt.salary)
+		}
+	}
+
+	timeline := c.Timeline()
+	want := []time.Time{date(time.January, 1), date(time.February, 1), date(time.March, 1), date(time.March, 1)}
+	if len(timeline) != len(want) {
+		t.Fatalf("timeline has %d versions, want %d", len(timeline), len(want))
+	}
+	for i, v := range timeline {
+		if !v.EffectiveDate.Equal(want[i]) {
+			t.Errorf("version %d takes effect on %s, want %s", i, v.EffectiveDate.Format(time.DateOnly), want[i].Format(time.DateOnly))
+		}
+	}
+	if timeline[0].Amendment != nil {
+		t.Error("the original terms have an amendment")
+	}
+
+	if got := CurrentContract([]*Contract{c}, date(time.February, 10)); got == nil || got.Position != "Encargado" {
+		t.Errorf("CurrentContract = %+v, want the terms of February", got)
+	}
+}
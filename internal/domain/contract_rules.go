@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContractCause is the legal ground of a temporary contract (art. 15 ET) or
+// the modality of a training contract (art. 11 ET), as worded after the 2021
+// labour reform (RDL 32/2021).
+type ContractCause string
+
+const (
+	CauseProductionCircumstances ContractCause = "PRODUCTION_CIRCUMSTANCES" // Circunstancias de la producción
+	CauseOccasionalPredictable   ContractCause = "OCCASIONAL_PREDICTABLE"   // Situaciones ocasionales previsibles
+	CauseSubstitution            ContractCause = "SUBSTITUTION"             // Sustitución de persona trabajadora
+	CauseWorkStudy               ContractCause = "WORK_STUDY"               // Formación en alternancia
+	CauseProfessionalPractice    ContractCause = "PROFESSIONAL_PRACTICE"    // Práctica profesional
+)
+
+// ActivationPeriod is a season in which a fixed-discontinuous employee is
+// called to work.
+type ActivationPeriod struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// duration is a legal length of a contract in months and days.
+type duration struct {
+	months, days int
+}
+
+func (d duration) isZero() bool {
+	return d.months == 0 && d.days == 0
+}
+
+// lastDay returns the last day of a contract of this length starting on start.
+func (d duration) lastDay(start time.Time) time.Time {
+	return start.AddDate(0, d.months, d.days-1)
+}
+
+func (d duration) String() string {
+	if d.months > 0 {
+		return fmt.Sprintf("%d months", d.months)
+	}
+	return fmt.Sprintf("%d days", d.days)
+}
+
+// causeRule limits the length of a contract concluded for a cause.
+type causeRule struct {
+	min, max        duration // Zero means no limit
+	maxExtensions   int      // -1 allows any number of extensions up to max
+	endDateOptional bool
+}
+
+var contractCauses = map[ContractType]map[ContractCause]causeRule{
+	ContractTypeTemporary: {
+		// Up to 6 months, extendable once within that limit (art. 15.2 ET)
+		CauseProductionCircumstances: {max: duration{months: 6}, maxExtensions: 1},
+		// Up to 90 days in the calendar year
+		CauseOccasionalPredictable: {max: duration{days: 90}, maxExtensions: 1},
+		// Lasts until the substituted employee returns, which may be unknown
+		CauseSubstitution: {maxExtensions: -1, endDateOptional: true},
+	},
+	ContractTypeTraining: {
+		// Between 3 months and 2 years (art. 11.2 ET)
+		CauseWorkStudy: {min: duration{months: 3}, max: duration{months: 24}, maxExtensions: -1},
+		// Between 6 months and 1 year (art. 11.3 ET)
+		CauseProfessionalPractice: {min: duration{months: 6}, max: duration{months: 12}, maxExtensions: -1},
+	},
+}
+
+// validateTypeRules checks the terms of c against the rules of its type.
+// extensions is how many times the end date has been postponed.
+func (c *Contract) validateTypeRules(extensions int, v *ValidationError) {
+	switch c.Type {
+	case ContractTypeIndefinite:
+		return
+	case ContractTypeFixedDiscontinuous:
+		c.validateActivationPeriods(v)
+		return
+	case ContractTypeTemporary, ContractTypeTraining:
+	default:
+		v.Add("type", "unknown contract type")
+		return
+	}
+
+	rule, ok := contractCauses[c.Type][c.Cause]
+	if !ok {
+		causes := make([]string, 0, len(contractCauses[c.Type]))
+		for cause := range contractCauses[c.Type] {
+			causes = append(causes, string(cause))
+		}
+		sort.Strings(causes)
+		v.Add("cause", "must be one of "+strings.Join(causes, ", "))
+		return
+	}
+	if c.Type == ContractTypeTemporary && strings.TrimSpace(c.Justification) == "" {
+		v.Add("justification", "temporary contracts must state the circumstances that justify them")
+	}
+
+	if c.EndDate == nil {
+		if !rule.endDateOptional {
+			v.Add("end_date", "is required for this contract type")
+		}
+		return
+	}
+	switch {
+	case c.EndDate.Before(c.StartDate):
+		v.Add("end_date", "cannot be before the start date")
+	case !rule.max.isZero() && c.EndDate.After(rule.max.lastDay(c.StartDate)):
+		v.Add("end_date", "exceeds the maximum duration of "+rule.max.String())
+	case !rule.min.isZero() && c.EndDate.Before(rule.min.lastDay(c.StartDate)):
+		v.Add("end_date", "is shorter than the minimum duration of "+rule.min.String())
+	}
+	if rule.maxExtensions >= 0 && extensions > rule.maxExtensions {
+		v.Add("end_date", fmt.Sprintf("the contract can be extended at most %d time(s)", rule.maxExtensions))
+	}
+}
+
+// validateActivationPeriods requires at least one period, each within the
+// contract and none overlapping another.
+func (c *Contract) validateActivationPeriods(v *ValidationError) {
+	if len(c.ActivationPeriods) == 0 {
+		v.Add("activation_periods", "fixed-discontinuous contracts need at least one activation period")
+		return
+	}
+	for i, p := range c.ActivationPeriods {
+		field := fmt.Sprintf("activation_periods[%d]", i)
+		if p.EndDate.Before(p.StartDate) {
+			v.Add(field, "end date cannot be before the start date")
+			continue
+		}
+		if p.StartDate.Before(c.StartDate) {
+			v.Add(field, "starts before the contract")
+		}
+		for j, other := range c.ActivationPeriods[:i] {
+			if !p.StartDate.After(other.EndDate) && !p.EndDate.Before(other.StartDate) {
+				v.Add(field, fmt.Sprintf("overlaps activation_periods[%d]", j))
+			}
+		}
+	}
+}
+
+// extensions counts the amendments that postponed the end date.
+func (c *Contract) extensions() int {
+	var n int
+	end := c.EndDate
+	for _, a := range c.sortedAmendments() {
+		if a.EndDate == nil {
+			continue
+		}
+		if end != nil && a.EndDate.After(*end) {
+			n++
+		}
+		end = a.EndDate
+	}
+	return n
+}
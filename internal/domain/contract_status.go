@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ContractStatus string
+
+const (
+	ContractStatusUpcoming ContractStatus = "UPCOMING" // Signed, starts in the future
+	ContractStatusActive   ContractStatus = "ACTIVE"
+	ContractStatusEnded    ContractStatus = "ENDED"
+)
+
+func (s ContractStatus) IsValid() bool {
+	switch s {
+	case ContractStatusUpcoming, ContractStatusActive, ContractStatusEnded:
+		return true
+	}
+	return false
+}
+
+// ContractExpiryNoticeDays are the days before the end of a contract on
+// which the company admins are reminded of it, largest first.
+var ContractExpiryNoticeDays = []int{30, 15}
+
+// StatusOn returns the status of the contract on date, using the end date
+// in force that day so extensions and conversions are taken into account.
+func (c *Contract) StatusOn(date time.Time) ContractStatus {
+	day := dateOf(date)
+	if day.Before(dateOf(c.StartDate)) {
+		return ContractStatusUpcoming
+	}
+	if end := c.AsOf(day).EndDate; end != nil && day.After(dateOf(*end)) {
+		return ContractStatusEnded
+	}
+	return ContractStatusActive
+}
+
+// ExpiryNotice returns the notice period the contract is in on date, e.g.
+// 15 when it ends within 15 days, or 0 if it has no end date or is not
+// about to end.
+func (c *Contract) ExpiryNotice(date time.Time) int {
+	end := c.AsOf(date).EndDate
+	if end == nil {
+		return 0
+	}
+	daysLeft := int(dateOf(*end).Sub(dateOf(date)).Hours() / 24)
+	if daysLeft < 0 {
+		return 0
+	}
+	notice := 0
+	for _, days := range ContractExpiryNoticeDays {
+		if daysLeft <= days {
+			notice = days
+		}
+	}
+	return notice
+}
+
+// NewContractExpiringNotification tells an admin that the contract of
+// employee ends within notice days. It is sent once per notice period and
+// end date, so extending the contract triggers new reminders.
+func NewContractExpiringNotification(admin *User, employee *User, c *Contract, notice int, now time.Time) *Notification {
+	terms := c.AsOf(now)
+	message := fmt.Sprintf("The contract of %s (%s, %s) ends on %s", employee.Name, terms.Type, terms.Position, terms.EndDate.Format("2006-01-02"))
+	key := fmt.Sprintf("contract-expiring:%s:%d:%s", c.ID, notice, terms.EndDate.Format("2006-01-02"))
+	return NewNotification(admin.ID, NotificationContractExpiring, message, &c.ID, key, now)
+}
+
+// dateOf drops the time of day so dates compare as calendar days.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// DayPeriod tells which part of the day an absence covers.
+type DayPeriod string
+
+const (
+	PeriodFullDay   DayPeriod = "FULL_DAY"
+	PeriodMorning   DayPeriod = "MORNING"
+	PeriodAfternoon DayPeriod = "AFTERNOON"
+	PeriodHours     DayPeriod = "HOURS" // Between StartTime and EndTime
+)
+
+// StandardWorkdayHours converts hourly absences into fractional days.
+const StandardWorkdayHours = 8.0
+
+var ErrInvalidPeriod = errors.New("partial-day absences must cover a single day with valid times")
+
+func (p DayPeriod) IsValid() bool {
+	switch p {
+	case PeriodFullDay, PeriodMorning, PeriodAfternoon, PeriodHours:
+		return true
+	}
+	return false
+}
+
+// SetPeriod makes the absence cover the whole day, half of it or a range of
+// hours ("15:04" format). Partial days must start and end on the same date.
+func (v *Vacation) SetPeriod(period DayPeriod, startTime, endTime *string) error {
+	if period == "" {
+		period = PeriodFullDay
+	}
+	if !period.IsValid() {
+		return ErrInvalidPeriod
+	}
+	if period != PeriodFullDay && !v.StartDate.Equal(v.EndDate) {
+		return ErrInvalidPeriod
+	}
+
+	v.Period = period
+	v.StartTime, v.EndTime = nil, nil
+	if period != PeriodHours {
+		return nil
+	}
+
+	if startTime == nil || endTime == nil {
+		return ErrInvalidPeriod
+	}
+	from, err := time.Parse("15:04", *startTime)
+	if err != nil {
+		return ErrInvalidPeriod
+	}
+	to, err := time.Parse("15:04", *endTime)
+	if err != nil || !to.After(from) {
+		return ErrInvalidPeriod
+	}
+	// Store them zero-padded ("9:00" becomes "09:00") so they sort as times
+	start, end := from.Format("15:04"), to.Format("15:04")
+	v.StartTime, v.EndTime = &start, &end
+	return nil
+}
+
+// Hours returns the length of an hourly absence.
+func (v *Vacation) Hours() float64 {
+	if v.Period != PeriodHours || v.StartTime == nil || v.EndTime == nil {
+		return 0
+	}
+	from, to := v.timeRange()
+	return to.Sub(from).Hours()
+}
+
+// timeRange parses the hours of an hourly absence.
+func (v *Vacation) timeRange() (from, to time.Time) {
+	if v.StartTime != nil {
+		from, _ = time.Parse("15:04", *v.StartTime)
+	}
+	if v.EndTime != nil {
+		to, _ = time.Parse("15:04", *v.EndTime)
+	}
+	return from, to
+}
+
+// DaysInYear returns how many days of the given year the absence consumes.
+func (v *Vacation) DaysInYear(year int, unit DayUnit, holidays HolidaySet) float64 {
+	from, to := YearBounds(year)
+	return v.DaysBetween(from, to, unit, holidays)
+}
+
+// DaysBetween returns how many days of [from, to] the absence consumes.
+// Half days count 0.5 and hourly absences a fraction of StandardWorkdayHours,
+// as long as the day itself would count.
+func (v *Vacation) DaysBetween(from, to time.Time, unit DayUnit, holidays HolidaySet) float64 {
+	start, end := v.StartDate, v.EndDate
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	days := CountDays(start, end, unit, holidays)
+
+	switch v.Period {
+	case PeriodMorning, PeriodAfternoon:
+		return days * 0.5
+	case PeriodHours:
+		return roundDays(days * v.Hours() / StandardWorkdayHours)
+	}
+	return days
+}
+
+// Overlaps reports whether two absences with intersecting dates really
+// collide: a morning and an afternoon, or two disjoint hour ranges, on the
+// same day do not.
+func (v *Vacation) Overlaps(other *Vacation) bool {
+	if v.StartDate.After(other.EndDate) || other.StartDate.After(v.EndDate) {
+		return false
+	}
+	if v.Period == PeriodFullDay || other.Period == PeriodFullDay {
+		return true
+	}
+
+	switch {
+	case v.Period == PeriodHours && other.Period == PeriodHours:
+		vFrom, vTo := v.timeRange()
+		otherFrom, otherTo := other.timeRange()
+		return vFrom.Before(otherTo) && otherFrom.Before(vTo)
+	case v.Period == PeriodHours || other.Period == PeriodHours:
+		return true
+	}
+	return v.Period == other.Period
+}
+
+// PeriodLabel describes the part of the day covered, for listings.
+func (v *Vacation) PeriodLabel() string {
+	switch v.Period {
+	case PeriodMorning:
+		return "mañana"
+	case PeriodAfternoon:
+		return "tarde"
+	case PeriodHours:
+		if v.StartTime != nil && v.EndTime != nil {
+			return *v.StartTime + "-" + *v.EndTime
+		}
+	}
+	return ""
+}
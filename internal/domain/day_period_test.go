@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVacationOverlaps(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.May, d, 0, 0, 0, 0, time.UTC)
+	}
+	absence := func(start, end int) *Vacation {
+		return &Vacation{StartDate: day(start), EndDate: day(end), Period: PeriodFullDay}
+	}
+	existing := absence(6, 10)
+
+	tests := []struct {
+		name  string
+		other *Vacation
+		want  bool
+	}{
+		{"same dates", absence(6, 10), true},
+		{"ends on the first day", absence(1, 6), true},
+		{"starts on the last day", absence(10, 14), true},
+		{"inside", absence(7, 8), true},
+		{"around", absence(1, 31), true},
+		{"the day before", absence(1, 5), false},
+		{"the day after", absence(11, 17), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := existing.Overlaps(tt.other); got != tt.want {
+				t.Errorf("Overlaps = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.Overlaps(existing); got != tt.want {
+				t.Errorf("reversed Overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVacationSetPeriod(t *testing.T) {
+	day := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name               string
+		end                time.Time
+		period             DayPeriod
+		startTime, endTime *string
+		want               error
+		wantStart, wantEnd string
+	}{
+		{"default", day, "", nil, nil, nil, "", ""},
+		{"morning", day, PeriodMorning, nil, nil, nil, "", ""},
+		{"several days", day.AddDate(0, 0, 1), PeriodMorning, nil, nil, ErrInvalidPeriod, "", ""},
+		{"unknown period", day, "EVENING", nil, nil, ErrInvalidPeriod, "", ""},
+		{"hours", day, PeriodHours, str("9:00"), str("11:30"), nil, "09:00", "11:30"},
+		{"hours without times", day, PeriodHours, str("09:00"), nil, ErrInvalidPeriod, "", ""},
+		{"end before start", day, PeriodHours, str("11:00"), str("09:00"), ErrInvalidPeriod, "", ""},
+		{"no length", day, PeriodHours, str("11:00"), str("11:00"), ErrInvalidPeriod, "", ""},
+		{"bad time", day, PeriodHours, str("9h"), str("11:00"), ErrInvalidPeriod, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Vacation{StartDate: day, EndDate: tt.end, Period: PeriodFullDay}
+			if err := v.SetPeriod(tt.period, tt.startTime, tt.endTime); !errors.Is(err, tt.want) {
+				t.Fatalf("SetPeriod err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			if tt.period == "" && v.Period != PeriodFullDay {
+				t.Errorf("period = %s, want FULL_DAY", v.Period)
+			}
+			var start, end string
+			if v.StartTime != nil && v.EndTime != nil {
+				start, end = *v.StartTime, *v.EndTime
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("times = %q-%q, want %q-%q", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestVacationDaysBetween(t *testing.T) {
+	// Monday 6 May 2024; Saturday 11 May
+	monday := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+	saturday := monday.AddDate(0, 0, 5)
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		vacation *Vacation
+		unit     DayUnit
+		want     float64
+	}{
+		{"full week", &Vacation{StartDate: monday, EndDate: monday.AddDate(0, 0, 6), Period: PeriodFullDay}, DayUnitWorking, 5},
+		{"morning", &Vacation{StartDate: monday, EndDate: monday, Period: PeriodMorning}, DayUnitWorking, 0.5},
+		{"afternoon on a Saturday", &Vacation{StartDate: saturday, EndDate: saturday, Period: PeriodAfternoon}, DayUnitWorking, 0},
+		{"afternoon on a Saturday in natural days", &Vacation{StartDate: saturday, EndDate: saturday, Period: PeriodAfternoon}, DayUnitNatural, 0.5},
+		{"two hours", &Vacation{StartDate: monday, EndDate: monday, Period: PeriodHours, StartTime: str("09:00"), EndTime: str("11:00")}, DayUnitWorking, 0.25},
+		{"ninety minutes", &Vacation{StartDate: monday, EndDate: monday, Period: PeriodHours, StartTime: str("12:00"), EndTime: str("13:30")}, DayUnitWorking, 0.19},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vacation.DaysBetween(monday, monday.AddDate(0, 0, 30), tt.unit, nil); got != tt.want {
+				t.Errorf("DaysBetween = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVacationOverlapsPartialDays(t *testing.T) {
+	day := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	absence := func(period DayPeriod, from, to string) *Vacation {
+		v := &Vacation{StartDate: day, EndDate: day, Period: period}
+		if period == PeriodHours {
+			v.StartTime, v.EndTime = str(from), str(to)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		a, b *Vacation
+		want bool
+	}{
+		{"morning and afternoon", absence(PeriodMorning, "", ""), absence(PeriodAfternoon, "", ""), false},
+		{"two mornings", absence(PeriodMorning, "", ""), absence(PeriodMorning, "", ""), true},
+		{"full day and morning", absence(PeriodFullDay, "", ""), absence(PeriodMorning, "", ""), true},
+		{"disjoint hours", absence(PeriodHours, "09:00", "11:00"), absence(PeriodHours, "11:00", "13:00"), false},
+		{"intersecting hours", absence(PeriodHours, "09:00", "11:00"), absence(PeriodHours, "10:30", "12:00"), true},
+		// Which half of the day an hour range falls in is not known
+		{"hours and afternoon", absence(PeriodHours, "09:00", "10:00"), absence(PeriodAfternoon, "", ""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Overlaps(tt.b); got != tt.want {
+				t.Errorf("Overlaps = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Overlaps(tt.a); got != tt.want {
+				t.Errorf("reversed Overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package domain
 
 type CreateUserRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Role     Role   `json:"role"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	Role       Role   `json:"role"`
+	Department string `json:"department,omitempty"`
 }
 
 type LoginRequest struct {
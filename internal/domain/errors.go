@@ -8,4 +8,5 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInternal      = errors.New("internal error")
 	ErrInvalidInput  = errors.New("invalid input")
+	ErrForbidden     = errors.New("forbidden")
 )
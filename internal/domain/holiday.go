@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HolidayScope string
+
+const (
+	HolidayScopeNational HolidayScope = "NATIONAL"
+	HolidayScopeRegional HolidayScope = "REGIONAL" // Comunidad autónoma
+	HolidayScopeLocal    HolidayScope = "LOCAL"
+)
+
+func (s HolidayScope) IsValid() bool {
+	switch s {
+	case HolidayScopeNational, HolidayScopeRegional, HolidayScopeLocal:
+		return true
+	}
+	return false
+}
+
+// HolidayCalendar groups the public holidays of one company work centre.
+type HolidayCalendar struct {
+	ID        uuid.UUID `json:"id"`
+	CompanyID uuid.UUID `json:"company_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewHolidayCalendar(companyID uuid.UUID, name string) (*HolidayCalendar, error) {
+	if name == "" {
+		return nil, ErrInvalidInput
+	}
+	return &HolidayCalendar{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+type Holiday struct {
+	ID         uuid.UUID    `json:"id"`
+	CalendarID uuid.UUID    `json:"calendar_id"`
+	Date       time.Time    `json:"date"`
+	Name       string       `json:"name"`
+	Scope      HolidayScope `json:"scope"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func NewHoliday(calendarID uuid.UUID, date time.Time, name string, scope HolidayScope) (*Holiday, error) {
+	if name == "" || !scope.IsValid() {
+		return nil, ErrInvalidInput
+	}
+	return &Holiday{
+		ID:         uuid.New(),
+		CalendarID: calendarID,
+		Date:       time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Name:       name,
+		Scope:      scope,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// HolidaySet is a lookup of holiday dates. A nil set contains no holidays.
+type HolidaySet map[string]struct{}
+
+func NewHolidaySet(holidays []*Holiday) HolidaySet {
+	set := make(HolidaySet, len(holidays))
+	for _, h := range holidays {
+		set[h.Date.Format("2006-01-02")] = struct{}{}
+	}
+	return set
+}
+
+func (s HolidaySet) Contains(d time.Time) bool {
+	_, ok := s[d.Format("2006-01-02")]
+	return ok
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewHoliday(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		date    time.Time
+		holiday string
+		scope   HolidayScope
+		want    error
+	}{
+		{"national", time.Date(2025, time.October, 12, 0, 0, 0, 0, time.UTC), "Fiesta Nacional", HolidayScopeNational, nil},
+		// Kept as the local date, not shifted to the previous UTC day
+		{"local midnight", time.Date(2025, time.May, 15, 0, 0, 0, 0, madrid), "San Isidro", HolidayScopeLocal, nil},
+		{"no name", time.Date(2025, time.May, 2, 0, 0, 0, 0, time.UTC), "", HolidayScopeRegional, ErrInvalidInput},
+		{"unknown scope", time.Date(2025, time.May, 2, 0, 0, 0, 0, time.UTC), "Comunidad de Madrid", "PROVINCIAL", ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHoliday(uuid.New(), tt.date, tt.holiday, tt.scope)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			want := time.Date(tt.date.Year(), tt.date.Month(), tt.date.Day(), 0, 0, 0, 0, time.UTC)
+			if !h.Date.Equal(want) {
+				t.Errorf("date = %s, want %s", h.Date, want)
+			}
+		})
+	}
+}
+
+func TestHolidaySetContains(t *testing.T) {
+	christmas := time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC)
+	set := NewHolidaySet([]*Holiday{{Date: christmas}})
+
+	tests := []struct {
+		name string
+		set  HolidaySet
+		day  time.Time
+		want bool
+	}{
+		{"holiday", set, christmas, true},
+		{"time of the day", set, christmas.Add(15 * time.Hour), true},
+		{"next day", set, christmas.AddDate(0, 0, 1), false},
+		{"nil set", nil, christmas, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.Contains(tt.day); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.day, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentRequired = errors.New("this leave type requires an attachment")
+	ErrLeaveTypeInUse     = errors.New("leave type is used by existing absences")
+)
+
+// LeaveType configures how a kind of absence (sick leave, parental leave...)
+// is handled. Companies define their own; vacations use VacationLeaveType.
+type LeaveType struct {
+	ID                   uuid.UUID `json:"id"`
+	CompanyID            uuid.UUID `json:"company_id"`
+	Name                 string    `json:"name"`
+	Paid                 bool      `json:"paid"`
+	CountsAgainstBalance bool      `json:"counts_against_balance"`
+	RequiresAttachment   bool      `json:"requires_attachment"`
+	RequiresApproval     bool      `json:"requires_approval"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// VacationLeaveType is the built-in type of absences without a leave type,
+// i.e. everything requested through the vacation endpoints.
+var VacationLeaveType = &LeaveType{
+	Name:                 "Vacaciones",
+	Paid:                 true,
+	CountsAgainstBalance: true,
+	RequiresApproval:     true,
+}
+
+func NewLeaveType(companyID uuid.UUID, name string, paid, countsAgainstBalance, requiresAttachment, requiresApproval bool) (*LeaveType, error) {
+	if name == "" {
+		return nil, ErrInvalidInput
+	}
+	return &LeaveType{
+		ID:                   uuid.New(),
+		CompanyID:            companyID,
+		Name:                 name,
+		Paid:                 paid,
+		CountsAgainstBalance: countsAgainstBalance,
+		RequiresAttachment:   requiresAttachment,
+		RequiresApproval:     requiresApproval,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}, nil
+}
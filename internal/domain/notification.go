@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationContractExpiring NotificationType = "CONTRACT_EXPIRING"
+)
+
+// Notification is an in-app message for a user, e.g. a reminder sent by a
+// background job.
+type Notification struct {
+	ID         uuid.UUID        `json:"id"`
+	UserID     uuid.UUID        `json:"user_id"` // Recipient
+	Type       NotificationType `json:"type"`
+	Message    string           `json:"message"`
+	ResourceID *uuid.UUID       `json:"resource_id,omitempty"` // The contract, vacation... it is about
+	// Key identifies the event notified so jobs can run again without
+	// notifying the same user twice.
+	Key       string     `json:"-"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewNotification(userID uuid.UUID, notificationType NotificationType, message string, resourceID *uuid.UUID, key string, now time.Time) *Notification {
+	return &Notification{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Type:       notificationType,
+		Message:    message,
+		ResourceID: resourceID,
+		Key:        key,
+		CreatedAt:  now,
+	}
+}
+
+func (n *Notification) MarkRead(now time.Time) {
+	if n.ReadAt == nil {
+		n.ReadAt = &now
+	}
+}
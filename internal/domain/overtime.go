@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// FullTimeWeeklyHours is the legal maximum of ordinary working time (art. 34 ET).
+	FullTimeWeeklyHours = 40.0
+	// AnnualOvertimeCap is the legal maximum of overtime per year (art. 35.2 ET).
+	AnnualOvertimeCap = 80.0
+)
+
+// HoursPeriod compares contracted and worked hours over a week or a month.
+type HoursPeriod struct {
+	Period          string    `json:"period"` // 2024-W10 or 2024-03
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	ContractedHours float64   `json:"contracted_hours"`
+	WorkedHours     float64   `json:"worked_hours"`
+	OvertimeHours   float64   `json:"overtime_hours"`
+}
+
+// OvertimeReport is the yearly overtime of an employee. Overtime is computed
+// per week; each month adds up the overtime of the weeks ending in it.
+type OvertimeReport struct {
+	UserID             uuid.UUID      `json:"user_id"`
+	Year               int            `json:"year"`
+	Weeks              []*HoursPeriod `json:"weeks"`
+	Months             []*HoursPeriod `json:"months"`
+	ContractedHours    float64        `json:"contracted_hours"`
+	WorkedHours        float64        `json:"worked_hours"`
+	OvertimeHours      float64        `json:"overtime_hours"`
+	AnnualCap          float64        `json:"annual_cap"`
+	RemainingBeforeCap float64        `json:"remaining_before_cap"`
+	ExceedsCap         bool           `json:"exceeds_cap"`
+
+	contracted map[string]float64 // Hours due per YYYY-MM-DD date
+}
+
+// NewOvertimeReport compares, day by day up to now, the hours worked according
+// to the time register with the hours of the contract in force. Contracted
+// hours are spread over Monday to Friday and reduced by holidays and by the
+// approved absences.
+func NewOvertimeReport(userID uuid.UUID, year int, contracts []*Contract, entries []*TimeEntry, absences []*Vacation, holidays HolidaySet, now time.Time) *OvertimeReport {
+	report := &OvertimeReport{
+		UserID:     userID,
+		Year:       year,
+		Weeks:      []*HoursPeriod{},
+		Months:     []*HoursPeriod{},
+		AnnualCap:  AnnualOvertimeCap,
+		contracted: make(map[string]float64),
+	}
+
+	worked := make(map[string]float64)
+	for _, e := range entries {
+		worked[RegisterDate(e.ClockIn)] += e.Worked(now).Hours()
+	}
+
+	from, to := YearBounds(year)
+	local := now.In(RegisterLocation)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if today.Before(to) {
+		to = today
+	}
+
+	var week, month *HoursPeriod
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		isoYear, isoWeek := d.ISOWeek()
+		if label := fmt.Sprintf("%d-W%02d", isoYear, isoWeek); week == nil || week.Period != label {
+			week = &HoursPeriod{Period: label, StartDate: d}
+			report.Weeks = append(report.Weeks, week)
+		}
+		if label := d.Format("2006-01"); month == nil || month.Period != label {
+			month = &HoursPeriod{Period: label, StartDate: d}
+			report.Months = append(report.Months, month)
+		}
+
+		date := d.Format("2006-01-02")
+		contracted := contractedHours(d, contracts, absences, holidays)
+		done := worked[date]
+		report.contracted[date] = contracted
+		for _, p := range []*HoursPeriod{week, month} {
+			p.EndDate = d
+			p.ContractedHours = roundHours(p.ContractedHours + contracted)
+			p.WorkedHours = roundHours(p.WorkedHours + done)
+		}
+		report.ContractedHours = roundHours(report.ContractedHours + contracted)
+		report.WorkedHours = roundHours(report.WorkedHours + done)
+
+		// The week is complete on Sunday or at the end of the range
+		if d.Weekday() == time.Sunday || d.Equal(to) {
+			week.OvertimeHours = roundHours(math.Max(0, week.WorkedHours-week.ContractedHours))
+			month.OvertimeHours = roundHours(month.OvertimeHours + week.OvertimeHours)
+			report.OvertimeHours = roundHours(report.OvertimeHours + week.OvertimeHours)
+		}
+	}
+
+	report.RemainingBeforeCap = roundHours(math.Max(0, AnnualOvertimeCap-report.OvertimeHours))
+	report.ExceedsCap = report.OvertimeHours > AnnualOvertimeCap
+	return report
+}
+
+// ContractedOn returns the ordinary hours due on a YYYY-MM-DD date of the
+// report, or 0 for a date outside it.
+func (r *OvertimeReport) ContractedOn(date string) float64 {
+	return r.contracted[date]
+}
+
+// contractedHours returns the ordinary hours due on a day.
+func contractedHours(d time.Time, contracts []*Contract, absences []*Vacation, holidays HolidaySet) float64 {
+	if isWeekend(d) || holidays.Contains(d) {
+		return 0
+	}
+	var hours float64
+	if c := CurrentContract(contracts, d); c != nil {
+		hours = c.WeeklyHours / 5
+	}
+	var absent float64
+	for _, a := range absences {
+		absent += a.DaysBetween(d, d, DayUnitWorking, holidays)
+	}
+	return hours * math.Max(0, 1-absent)
+}
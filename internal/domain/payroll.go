@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContributionGroups is the number of Social Security contribution groups
+// (grupos de cotización), from 1 (engineers and graduates) to 11.
+const ContributionGroups = 11
+
+// Contribution is a line of the Social Security contributions, or any
+// other amount computed as a rate of a base.
+type Contribution struct {
+	Concept string  `json:"concept"`
+	Base    float64 `json:"base,omitempty"`
+	Rate    float64 `json:"rate,omitempty"`
+	Amount  float64 `json:"amount"`
+}
+
+// PayrollSimulation estimates what an employee takes home from the yearly
+// gross salary of a contract. Salaries paid in 14 payments include two extra
+// payments, which bear IRPF but no contributions: these are paid monthly on
+// the prorated base.
+type PayrollSimulation struct {
+	ContractID        uuid.UUID `json:"contract_id"`
+	Year              int       `json:"year"`
+	TaxTableYear      int       `json:"tax_table_year"`
+	PaymentsPerYear   int       `json:"payments_per_year"`
+	ContributionGroup int       `json:"contribution_group"`
+	AnnualGross       float64   `json:"annual_gross"`
+	MonthlyGross      float64   `json:"monthly_gross"` // Each of the payments
+	// Monthly Social Security contributions
+	ContributionBase   float64        `json:"contribution_base"`
+	Contributions      []Contribution `json:"contributions"`
+	TotalContributions float64        `json:"total_contributions"`
+	// IRPF withheld from every payment
+	IRPFRate        float64 `json:"irpf_rate"`
+	IRPFWithholding float64 `json:"irpf_withholding"`
+	MonthlyNet      float64 `json:"monthly_net"`
+	ExtraPaymentNet float64 `json:"extra_payment_net,omitempty"`
+	AnnualNet       float64 `json:"annual_net"`
+}
+
+// NewPayrollSimulation simulates the payroll of contract c with the terms in
+// force on date. The IRPF rate follows the general procedure for a single
+// employee with no dependants; personal circumstances lower it.
+func NewPayrollSimulation(c *Contract, date time.Time, group, paymentsPerYear int, table *TaxTable) (*PayrollSimulation, error) {
+	v := &ValidationError{}
+	if paymentsPerYear != 12 && paymentsPerYear != 14 {
+		v.Add("payments", "must be 12 or 14")
+	}
+	if group < 1 || group > ContributionGroups {
+		v.Add("contribution_group", "must be between 1 and 11")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	terms := c.AsOf(date)
+	s := &PayrollSimulation{
+		ContractID:        c.ID,
+		Year:              date.Year(),
+		TaxTableYear:      table.Year,
+		PaymentsPerYear:   paymentsPerYear,
+		ContributionGroup: group,
+		AnnualGross:       roundEuros(terms.Salary),
+		MonthlyGross:      roundEuros(terms.Salary / float64(paymentsPerYear)),
+	}
+
+	s.ContributionBase = table.contributionBase(terms, group, 1)
+	s.Contributions, s.TotalContributions = table.employeeContributions(terms.Type, s.ContributionBase)
+
+	s.IRPFRate = irpfRate(terms.Salary, s.TotalContributions*12, table)
+	s.IRPFWithholding = roundEuros(s.MonthlyGross * s.IRPFRate / 100)
+	s.MonthlyNet = roundEuros(s.MonthlyGross - s.TotalContributions - s.IRPFWithholding)
+	s.AnnualNet = s.MonthlyNet * 12
+	if paymentsPerYear == 14 {
+		s.ExtraPaymentNet = roundEuros(s.MonthlyGross - s.IRPFWithholding)
+		s.AnnualNet += s.ExtraPaymentNet * 2
+	}
+	s.AnnualNet = roundEuros(s.AnnualNet)
+	return s, nil
+}
+
+// irpfRate estimates the withholding rate (percentage, two decimals) of a
+// yearly gross salary: the scale is applied to the net work income after
+// reductions, minus the tax on the personal allowance (art. 85 RIRPF).
+func irpfRate(annualGross, annualContributions float64, table *TaxTable) float64 {
+	if annualGross <= table.WithholdingExemptUpTo {
+		return 0
+	}
+	netIncome := annualGross - annualContributions
+	base := netIncome - table.OtherDeductibleExpenses - table.workIncomeReduction(netIncome)
+	if base <= 0 {
+		return 0
+	}
+	tax := table.tax(base) - table.tax(math.Min(base, table.PersonalAllowance))
+	if table.WithholdingCapRate > 0 {
+		tax = math.Min(tax, (annualGross-table.WithholdingExemptUpTo)*table.WithholdingCapRate/100)
+	}
+	if tax <= 0 {
+		return 0
+	}
+	return math.Round(tax/annualGross*10000) / 100
+}
+
+// contributionBase returns the monthly contribution base of the salary, with
+// the extra payments prorated, within the bases of the group. share is the
+// part of the month worked; part-time minimum bases are proportional to the
+// working time.
+func (t *TaxTable) contributionBase(terms *Contract, group int, share float64) float64 {
+	minBase := t.MinContributionBases[group] * terms.PartTimePercentage / 100 * share
+	return roundEuros(math.Min(math.Max(terms.Salary/12*share, minBase), t.MaxContributionBase*share))
+}
+
+func isFixedTerm(contractType ContractType) bool {
+	return contractType == ContractTypeTemporary || contractType == ContractTypeTraining
+}
+
+// employeeContributions returns the contributions deducted from the salary and their total.
+func (t *TaxTable) employeeContributions(contractType ContractType, base float64) ([]Contribution, float64) {
+	unemploymentRate := t.UnemploymentRate
+	if isFixedTerm(contractType) {
+		unemploymentRate = t.TemporaryUnemploymentRate
+	}
+	return applyRates(base, []Contribution{
+		{Concept: "Contingencias comunes", Rate: t.CommonContingenciesRate},
+		{Concept: "Desempleo", Rate: unemploymentRate},
+		{Concept: "Formación profesional", Rate: t.TrainingRate},
+		{Concept: "Mecanismo de equidad intergeneracional", Rate: t.IntergenerationalEquityRate},
+	})
+}
+
+// employerContributions returns the contributions paid by the company on top of the salary and their total.
+func (t *TaxTable) employerContributions(contractType ContractType, base float64) ([]Contribution, float64) {
+	unemploymentRate := t.EmployerUnemploymentRate
+	if isFixedTerm(contractType) {
+		unemploymentRate = t.EmployerTemporaryUnemploymentRate
+	}
+	return applyRates(base, []Contribution{
+		{Concept: "Contingencias comunes", Rate: t.EmployerCommonContingenciesRate},
+		{Concept: "Accidentes de trabajo y enfermedades profesionales", Rate: t.EmployerWorkAccidentRate},
+		{Concept: "Desempleo", Rate: unemploymentRate},
+		{Concept: "Formación profesional", Rate: t.EmployerTrainingRate},
+		{Concept: "FOGASA", Rate: t.EmployerFOGASARate},
+		{Concept: "Mecanismo de equidad intergeneracional", Rate: t.EmployerIntergenerationalEquityRate},
+	})
+}
+
+// applyRates fills the amount of each line from its rate.
+func applyRates(base float64, lines []Contribution) ([]Contribution, float64) {
+	var total float64
+	for i := range lines {
+		lines[i].Base = base
+		lines[i].Amount = roundEuros(base * lines[i].Rate / 100)
+		total += lines[i].Amount
+	}
+	return lines, roundEuros(total)
+}
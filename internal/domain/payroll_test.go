@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// simulationContract is a full-time contract from the start of year.
+func simulationContract(contractType ContractType, year int, salary float64, group int) *Contract {
+	return &Contract{
+		ID:                 uuid.New(),
+		UserID:             uuid.New(),
+		StartDate:          time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Type:               contractType,
+		Salary:             salary,
+		WeeklyHours:        FullTimeWeeklyHours,
+		PartTimePercentage: 100,
+		ContributionGroup:  group,
+	}
+}
+
+func TestNewPayrollSimulation(t *testing.T) {
+	tables := DefaultTaxTables()
+
+	// Employee contributions are 4.70% common contingencies, 1.55% (1.60%
+	// fixed-term) unemployment, 0.10% training and the intergenerational
+	// equity mechanism: 0.12% in 2024 and 0.13% in 2025.
+	tests := []struct {
+		name          string
+		year          int
+		contractType  ContractType
+		salary        float64
+		group         int
+		payments      int
+		partTime      float64
+		base          float64
+		contributions float64
+		irpfRate      float64
+		monthlyGross  float64
+		monthlyNet    float64
+		extraNet      float64
+		annualNet     float64
+	}{
+		{
+			// 2500 x 6.47%; IRPF on 30000 - 1941 - 2000 = 26059 minus the
+			// tax on the 5550 personal allowance: 4928.70 / 30000
+			name: "2024 12 payments", year: 2024, contractType: ContractTypeIndefinite, salary: 30000, group: 1, payments: 12,
+			base: 2500, contributions: 161.75, irpfRate: 16.43, monthlyGross: 2500, monthlyNet: 1927.50, annualNet: 23130,
+		},
+		{
+			// Same base and rate: the extra payments are prorated in the base
+			// and bear IRPF but no contributions
+			name: "2024 14 payments", year: 2024, contractType: ContractTypeIndefinite, salary: 30000, group: 1, payments: 14,
+			base: 2500, contributions: 161.75, irpfRate: 16.43, monthlyGross: 2142.86, monthlyNet: 1629.04, extraNet: 1790.79, annualNet: 23130.06,
+		},
+		{
+			name: "2025 12 payments", year: 2025, contractType: ContractTypeIndefinite, salary: 30000, group: 1, payments: 12,
+			base: 2500, contributions: 162, irpfRate: 16.43, monthlyGross: 2500, monthlyNet: 1927.25, annualNet: 23127,
+		},
+		{
+			name: "2024 fixed-term unemployment rate", year: 2024, contractType: ContractTypeTemporary, salary: 30000, group: 1, payments: 12,
+			base: 2500, contributions: 163, irpfRate: 16.41, monthlyGross: 2500, monthlyNet: 1926.75, annualNet: 23121,
+		},
+		{
+			// 1250 a month is below the 1323 minimum base of group 7, and no
+			// withholding applies up to 15876 a year
+			name: "2024 minimum base and withholding exemption", year: 2024, contractType: ContractTypeIndefinite, salary: 15000, group: 7, payments: 12,
+			base: 1323, contributions: 85.60, irpfRate: 0, monthlyGross: 1250, monthlyNet: 1164.40, annualNet: 13972.80,
+		},
+		{
+			// The scale gives about 274.70 but the tax cannot exceed 43% of
+			// 16500 - 15876: 268.32 / 16500
+			name: "2024 withholding cap", year: 2024, contractType: ContractTypeIndefinite, salary: 16500, group: 7, payments: 12,
+			base: 1375, contributions: 88.97, irpfRate: 1.63, monthlyGross: 1375, monthlyNet: 1263.62, annualNet: 15163.44,
+		},
+		{
+			// Half-time minimum base: 1323 x 50%
+			name: "2024 part-time minimum base", year: 2024, contractType: ContractTypeIndefinite, salary: 6000, group: 7, payments: 12, partTime: 50,
+			base: 661.50, contributions: 42.79, irpfRate: 0, monthlyGross: 500, monthlyNet: 457.21, annualNet: 5486.52,
+		},
+		{
+			name: "2024 maximum base", year: 2024, contractType: ContractTypeIndefinite, salary: 80000, group: 1, payments: 12,
+			base: 4720.50, contributions: 305.41, irpfRate: 29.12, monthlyGross: 6666.67, monthlyNet: 4419.93, annualNet: 53039.16,
+		},
+		{
+			name: "2025 maximum base 14 payments", year: 2025, contractType: ContractTypeIndefinite, salary: 80000, group: 1, payments: 14,
+			base: 4909.50, contributions: 318.14, irpfRate: 29.04, monthlyGross: 5714.29, monthlyNet: 3736.72, extraNet: 4054.86, annualNet: 52950.36,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := simulationContract(tt.contractType, tt.year, tt.salary, tt.group)
+			if tt.partTime != 0 {
+				c.PartTimePercentage = tt.partTime
+				c.WeeklyHours = FullTimeWeeklyHours * tt.partTime / 100
+			}
+			table, _ := tables.ForYear(tt.year)
+
+			s, err := NewPayrollSimulation(c, time.Date(tt.year, time.June, 1, 0, 0, 0, 0, time.UTC), tt.group, tt.payments, table)
+			if err != nil {
+				t.Fatalf("NewPayrollSimulation: %v", err)
+			}
+
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"contribution base", s.ContributionBase, tt.base},
+				{"total contributions", s.TotalContributions, tt.contributions},
+				{"IRPF rate", s.IRPFRate, tt.irpfRate},
+				{"monthly gross", s.MonthlyGross, tt.monthlyGross},
+				{"monthly net", s.MonthlyNet, tt.monthlyNet},
+				{"extra payment net", s.ExtraPaymentNet, tt.extraNet},
+				{"annual net", s.AnnualNet, tt.annualNet},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %.2f, want %.2f", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPayrollSimulationValidation(t *testing.T) {
+	table, _ := DefaultTaxTables().ForYear(2024)
+	c := simulationContract(ContractTypeIndefinite, 2024, 30000, 1)
+
+	tests := []struct {
+		name     string
+		group    int
+		payments int
+		fields   []string
+	}{
+		{"payments", 1, 13, []string{"payments"}},
+		{"group", 12, 12, []string{"contribution_group"}},
+		{"both", 0, 0, []string{"payments", "contribution_group"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPayrollSimulation(c, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), tt.group, tt.payments, table)
+			v, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("err = %v, want a *ValidationError", err)
+			}
+			if len(v.Fields) != len(tt.fields) {
+				t.Fatalf("fields = %v, want %v", v.Fields, tt.fields)
+			}
+			for i, f := range tt.fields {
+				if v.Fields[i].Field != f {
+					t.Errorf("field %d = %s, want %s", i, v.Fields[i].Field, f)
+				}
+			}
+		})
+	}
+}
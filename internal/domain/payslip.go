@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrContractHasPayslips prevents deleting the contract of archived payslips.
+var ErrContractHasPayslips = errors.New("contract has issued payslips")
+
+// Payslip is the monthly salary statement (nómina) of a contract. Payslips
+// are issued once per contract and month and never change afterwards; the
+// rendered document is archived with them.
+type Payslip struct {
+	ID                uuid.UUID    `json:"id"`
+	UserID            uuid.UUID    `json:"user_id"`
+	CompanyID         uuid.UUID    `json:"company_id"`
+	ContractID        uuid.UUID    `json:"contract_id"`
+	Period            string       `json:"period"` // 2024-03
+	PeriodStart       time.Time    `json:"period_start"`
+	PeriodEnd         time.Time    `json:"period_end"`
+	Days              int          `json:"days"` // 30 for a whole month
+	ContractType      ContractType `json:"contract_type"`
+	Position          string       `json:"position"`
+	ContributionGroup int          `json:"contribution_group"`
+	PaymentsPerYear   int          `json:"payments_per_year"`
+	// Earnings have no rate; deductions are the employee contributions and
+	// IRPF, employer contributions are the company cost on top of the gross.
+	Earnings              []Contribution `json:"earnings"`
+	Deductions            []Contribution `json:"deductions"`
+	EmployerContributions []Contribution `json:"employer_contributions"`
+	ContributionBase      float64        `json:"contribution_base"`
+	IRPFRate              float64        `json:"irpf_rate"`
+	GrossPay              float64        `json:"gross_pay"`
+	TotalDeductions       float64        `json:"total_deductions"`
+	NetPay                float64        `json:"net_pay"`
+	EmployerCost          float64        `json:"employer_cost"`
+	IssuedBy              *uuid.UUID     `json:"issued_by,omitempty"` // Nil once the author is deleted
+	CreatedAt             time.Time      `json:"created_at"`
+}
+
+// NewPayslip computes the payslip of contract c for month, or returns false
+// if the contract does not cover any day of it. The terms in force at the
+// end of the month apply to the whole month. With 14 payments, the extra
+// payments are paid in June and December in proportion to the days worked
+// in each half of the year.
+func NewPayslip(companyID uuid.UUID, c *Contract, month time.Time, paymentsPerYear int, table *TaxTable, issuedBy uuid.UUID, now time.Time) (*Payslip, bool, error) {
+	monthStart, next := MonthBounds(month)
+	monthEnd := next.AddDate(0, 0, -1)
+	terms := c.AsOf(monthEnd)
+	from, to, ok := terms.overlap(monthStart, monthEnd)
+	if !ok {
+		return nil, false, nil
+	}
+
+	v := &ValidationError{}
+	if paymentsPerYear != 12 && paymentsPerYear != 14 {
+		v.Add("payments", "must be 12 or 14")
+	}
+	if terms.ContributionGroup < 1 {
+		v.Add("contribution_group", "the contract has no contribution group")
+	}
+	if err := v.Err(); err != nil {
+		return nil, false, err
+	}
+
+	// Commercial month: every day is 1/30 of the monthly salary
+	days := 30
+	if !from.Equal(monthStart) || !to.Equal(monthEnd) {
+		days = int(to.Sub(from).Hours()/24) + 1
+	}
+	share := float64(days) / 30
+
+	p := &Payslip{
+		ID:                uuid.New(),
+		UserID:            c.UserID,
+		CompanyID:         companyID,
+		ContractID:        c.ID,
+		Period:            monthStart.Format("2006-01"),
+		PeriodStart:       from,
+		PeriodEnd:         to,
+		Days:              days,
+		ContractType:      terms.Type,
+		Position:          terms.Position,
+		ContributionGroup: terms.ContributionGroup,
+		PaymentsPerYear:   paymentsPerYear,
+		IssuedBy:          &issuedBy,
+		CreatedAt:         now,
+	}
+
+	payment := terms.Salary / float64(paymentsPerYear)
+	p.Earnings = append(p.Earnings, Contribution{Concept: "Salario base", Amount: roundEuros(payment * share)})
+	if paymentsPerYear == 14 && (monthStart.Month() == time.June || monthStart.Month() == time.December) {
+		halfStart := time.Date(monthStart.Year(), monthStart.Month()-5, 1, 0, 0, 0, 0, monthStart.Location())
+		if worked, ok := halfYearShare(terms, halfStart, monthEnd); ok {
+			p.Earnings = append(p.Earnings, Contribution{Concept: "Paga extraordinaria", Amount: roundEuros(payment * worked)})
+		}
+	}
+	for _, e := range p.Earnings {
+		p.GrossPay += e.Amount
+	}
+	p.GrossPay = roundEuros(p.GrossPay)
+
+	p.ContributionBase = table.contributionBase(terms, terms.ContributionGroup, share)
+	deductions, contributions := table.employeeContributions(terms.Type, p.ContributionBase)
+	p.IRPFRate = irpfRate(terms.Salary, contributions/share*12, table)
+	irpf := Contribution{Concept: "IRPF", Base: p.GrossPay, Rate: p.IRPFRate, Amount: roundEuros(p.GrossPay * p.IRPFRate / 100)}
+	p.Deductions = append(deductions, irpf)
+	p.TotalDeductions = roundEuros(contributions + irpf.Amount)
+	p.NetPay = roundEuros(p.GrossPay - p.TotalDeductions)
+
+	var employerContributions float64
+	p.EmployerContributions, employerContributions = table.employerContributions(terms.Type, p.ContributionBase)
+	p.EmployerCost = roundEuros(p.GrossPay + employerContributions)
+	return p, true, nil
+}
+
+// halfYearShare returns the part of the half year [from, to] covered by the contract.
+func halfYearShare(terms *Contract, from, to time.Time) (float64, bool) {
+	start, end, ok := terms.overlap(from, to)
+	if !ok {
+		return 0, false
+	}
+	return (end.Sub(start).Hours()/24 + 1) / (to.Sub(from).Hours()/24 + 1), true
+}
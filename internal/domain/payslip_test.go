@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// payslipContract is a full-time contract with the given terms.
+func payslipContract(contractType ContractType, start time.Time, end *time.Time, salary float64, group int) *Contract {
+	return &Contract{
+		ID:                 uuid.New(),
+		UserID:             uuid.New(),
+		StartDate:          start,
+		EndDate:            end,
+		Type:               contractType,
+		Salary:             salary,
+		WeeklyHours:        FullTimeWeeklyHours,
+		PartTimePercentage: 100,
+		ContributionGroup:  group,
+	}
+}
+
+func payslipDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewPayslip(t *testing.T) {
+	table, _ := DefaultTaxTables().ForYear(2024)
+
+	tests := []struct {
+		name     string
+		contract *Contract
+		month    time.Time
+		payments int
+		days     int
+		earnings []float64
+		base     float64
+		irpfRate float64
+		netPay   float64
+		cost     float64
+	}{
+		{
+			// February has 29 days in 2024 but a whole month is 30 days
+			name:     "whole month",
+			contract: payslipContract(ContractTypeIndefinite, payslipDate(2023, time.January, 1), nil, 30000, 1),
+			month:    payslipDate(2024, time.February, 1), payments: 12,
+			days: 30, earnings: []float64{2500}, base: 2500, irpfRate: 16.43,
+			netPay: 1927.50, // 2500 - 161.75 - 410.75
+			cost:   3299.50, // 31.98% employer contributions
+		},
+		{
+			// From the 16th: 16 of 30 days, and the contributions and IRPF
+			// rate of the whole year
+			name:     "started mid-month",
+			contract: payslipContract(ContractTypeIndefinite, payslipDate(2024, time.March, 16), nil, 30000, 1),
+			month:    payslipDate(2024, time.March, 1), payments: 12,
+			days: 16, earnings: []float64{1333.33}, base: 1333.33, irpfRate: 16.43,
+			netPay: 1027.99, // 1333.33 - 86.27 - 219.07
+			cost:   1759.73,
+		},
+		{
+			// The June extra payment accrues from January: 91 of 182 days
+			name:     "June extra payment pro rata",
+			contract: payslipContract(ContractTypeIndefinite, payslipDate(2024, time.April, 1), nil, 30000, 1),
+			month:    payslipDate(2024, time.June, 1), payments: 14,
+			days: 30, earnings: []float64{2142.86, 1071.43}, base: 2500, irpfRate: 16.43,
+		},
+		{
+			name: "part-time minimum base",
+			contract: func() *Contract {
+				c := payslipContract(ContractTypeIndefinite, payslipDate(2024, time.January, 1), nil, 6000, 7)
+				c.PartTimePercentage = 50
+				return c
+			}(),
+			month: payslipDate(2024, time.March, 1), payments: 12,
+			days: 30, earnings: []float64{500}, base: 661.50, irpfRate: 0,
+			netPay: 457.21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok, err := NewPayslip(uuid.New(), tt.contract, tt.month, tt.payments, table, uuid.New(), time.Now())
+			if err != nil || !ok {
+				t.Fatalf("NewPayslip: ok = %v, err = %v", ok, err)
+			}
+			if p.Days != tt.days {
+				t.Errorf("days = %d, want %d", p.Days, tt.days)
+			}
+			if len(p.Earnings) != len(tt.earnings) {
+				t.Fatalf("earnings = %v, want %v", p.Earnings, tt.earnings)
+			}
+			for i, want := range tt.earnings {
+				if p.Earnings[i].Amount != want {
+					t.Errorf("earning %s = %.2f, want %.2f", p.Earnings[i].Concept, p.Earnings[i].Amount, want)
+				}
+			}
+			if p.ContributionBase != tt.base {
+				t.Errorf("contribution base = %.2f, want %.2f", p.ContributionBase, tt.base)
+			}
+			if p.IRPFRate != tt.irpfRate {
+				t.Errorf("IRPF rate = %.2f, want %.2f", p.IRPFRate, tt.irpfRate)
+			}
+			if tt.netPay != 0 && p.NetPay != tt.netPay {
+				t.Errorf("net pay = %.2f, want %.2f", p.NetPay, tt.netPay)
+			}
+			if tt.cost != 0 && p.EmployerCost != tt.cost {
+				t.Errorf("employer cost = %.2f, want %.2f", p.EmployerCost, tt.cost)
+			}
+		})
+	}
+}
+
+func TestNewPayslipOutsideContract(t *testing.T) {
+	table, _ := DefaultTaxTables().ForYear(2024)
+	end := payslipDate(2024, time.February, 29)
+	c := payslipContract(ContractTypeTemporary, payslipDate(2024, time.January, 1), &end, 30000, 1)
+
+	if _, ok, err := NewPayslip(uuid.New(), c, payslipDate(2024, time.March, 1), 12, table, uuid.New(), time.Now()); ok || err != nil {
+		t.Errorf("NewPayslip after the end: ok = %v, err = %v; want no payslip", ok, err)
+	}
+}
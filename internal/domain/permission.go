@@ -0,0 +1,56 @@
+package domain
+
+// Permission is an action a role may perform, named "resource:action".
+type Permission string
+
+const (
+	PermUserManage     Permission = "user:manage"
+	PermContractRead   Permission = "contract:read"
+	PermContractManage Permission = "contract:manage"
+	PermPayrollRead    Permission = "payroll:read"
+	PermPayrollIssue   Permission = "payroll:issue"
+	PermPayrollExport  Permission = "payroll:export"
+	// Bank account, calendars, leave types and vacation rules of the company
+	PermCompanyManage  Permission = "company:manage"
+	PermScheduleManage Permission = "schedule:manage"
+	// Clock in for others, correct time entries and export the register
+	PermTimeManage Permission = "time:manage"
+	// See the vacations and absences of direct reports
+	PermVacationRead Permission = "vacation:read"
+	// See the vacations and absences of anyone in the company
+	PermVacationReadAll Permission = "vacation:read:all"
+	// Request, change and cancel vacations for others and override the constraints
+	PermVacationManage Permission = "vacation:manage"
+	// Decide on the vacation requests of direct reports
+	PermVacationApprove Permission = "vacation:approve"
+	// Decide on any vacation request of the company
+	PermVacationApproveAll Permission = "vacation:approve:all"
+	// See the leave type of the absences of colleagues, not just "Ausencia"
+	PermAbsenceReadDetails Permission = "absence:read:details"
+)
+
+// rolePermissions grants the permissions of each role. Admins have them all.
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermUserManage, PermContractRead, PermContractManage, PermPayrollRead, PermPayrollIssue, PermPayrollExport,
+		PermCompanyManage, PermScheduleManage, PermTimeManage,
+		PermVacationRead, PermVacationReadAll, PermVacationManage, PermVacationApprove, PermVacationApproveAll,
+		PermAbsenceReadDetails,
+	},
+	RoleManager: {PermVacationRead, PermVacationApprove},
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions lists the permissions the role grants.
+func (r Role) Permissions() []Permission {
+	return append([]Permission{}, rolePermissions[r]...)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken is returned for unknown, expired, revoked or reused tokens.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// RefreshTokenTTL is how long a session lasts without being refreshed.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshToken lets a client get a new access token. Each refresh token is
+// used once and replaced by a new one of the same family (the session);
+// presenting a used token again means it was stolen, and the whole family
+// is revoked. Only the hash of the token is stored.
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CompanyID uuid.UUID // Of the user, read along with the token to scope the request presenting it
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// NewRefreshToken returns the token to store and the secret handed to the
+// client. A nil familyID starts a new session.
+func NewRefreshToken(userID uuid.UUID, familyID uuid.UUID, now time.Time) (*RefreshToken, string, error) {
+	token, hash, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+	return &RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+		CreatedAt: now,
+	}, token, nil
+}
+
+func HashRefreshToken(token string) string {
+	return hashSecret(token)
+}
+
+// newSecret returns a random URL-safe token and its hash, the only part
+// that is stored.
+func newSecret() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	return token, hashSecret(token), nil
+}
+
+func hashSecret(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive tells whether the token can still be exchanged. Used tokens are
+// not active either.
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinRestBetweenShifts is the minimum rest between the end of a workday and
+// the start of the next one (art. 34.3 ET).
+const MinRestBetweenShifts = 12 * time.Hour
+
+var (
+	ErrInsufficientRest      = errors.New("schedule does not leave the minimum rest between shifts")
+	ErrScheduleOverlap       = errors.New("schedule assignment overlaps an existing one")
+	ErrScheduleTemplateInUse = errors.New("schedule template is assigned to users")
+)
+
+// ShiftPattern is one day of a schedule template. Rest days have no times.
+// A shift ending at or before its start time is a night shift that ends on
+// the next day.
+type ShiftPattern struct {
+	Start string `json:"start,omitempty"` // HH:MM
+	End   string `json:"end,omitempty"`   // HH:MM
+}
+
+func (p ShiftPattern) IsRest() bool {
+	return p.Start == "" && p.End == ""
+}
+
+func (p ShiftPattern) validate() error {
+	if p.IsRest() {
+		return nil
+	}
+	start, err := time.Parse("15:04", p.Start)
+	if err != nil {
+		return ErrInvalidInput
+	}
+	end, err := time.Parse("15:04", p.End)
+	if err != nil || end.Equal(start) {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// on returns the shift of the pattern starting on date.
+func (p ShiftPattern) on(date time.Time) *Shift {
+	start, _ := time.Parse("15:04", p.Start)
+	end, _ := time.Parse("15:04", p.End)
+	s := &Shift{
+		Date:  date,
+		Start: date.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute),
+		End:   date.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute),
+	}
+	if !s.End.After(s.Start) {
+		s.End = s.End.AddDate(0, 0, 1)
+		s.Night = true
+	}
+	s.Hours = roundHours(s.End.Sub(s.Start).Hours())
+	return s
+}
+
+// Shift is a planned working period. Date is the day it starts on.
+type Shift struct {
+	Date  time.Time `json:"date"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Hours float64   `json:"hours"`
+	Night bool      `json:"night,omitempty"` // Ends on the next day
+}
+
+// ScheduleTemplate is a weekly work pattern, or a rotation of several weeks.
+// Days starts on a Monday and holds one pattern per day of the cycle.
+type ScheduleTemplate struct {
+	ID        uuid.UUID      `json:"id"`
+	CompanyID uuid.UUID      `json:"company_id"`
+	Name      string         `json:"name"`
+	Days      []ShiftPattern `json:"days"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func NewScheduleTemplate(companyID uuid.UUID, name string, days []ShiftPattern) (*ScheduleTemplate, error) {
+	if name == "" || len(days) == 0 || len(days)%7 != 0 {
+		return nil, ErrInvalidInput
+	}
+	for _, d := range days {
+		if err := d.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	t := &ScheduleTemplate{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		Name:      name,
+		Days:      days,
+		CreatedAt: time.Now(),
+	}
+
+	// Lay the cycle out twice so the rest between its last and first day is
+	// checked too. Any Monday works as the start of the cycle.
+	cycleStart := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	var shifts []*Shift
+	for i := 0; i < 2*len(days); i++ {
+		if s := t.ShiftOn(cycleStart.AddDate(0, 0, i), cycleStart); s != nil {
+			shifts = append(shifts, s)
+		}
+	}
+	if len(CheckRest(shifts)) > 0 {
+		return nil, ErrInsufficientRest
+	}
+	return t, nil
+}
+
+// ShiftOn returns the shift planned on date for a cycle started on
+// cycleStart, or nil on rest days.
+func (t *ScheduleTemplate) ShiftOn(date, cycleStart time.Time) *Shift {
+	n := len(t.Days)
+	i := int(math.Round(date.Sub(cycleStart).Hours()/24)) % n
+	if i < 0 {
+		i += n
+	}
+	if t.Days[i].IsRest() {
+		return nil
+	}
+	return t.Days[i].on(date)
+}
+
+// ScheduleAssignment puts a user on a template from StartDate until EndDate,
+// or indefinitely when EndDate is nil. The cycle of the template starts on
+// the Monday of the week of StartDate.
+type ScheduleAssignment struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	TemplateID uuid.UUID  `json:"template_id"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    *time.Time `json:"end_date,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func NewScheduleAssignment(userID, templateID uuid.UUID, startDate time.Time, endDate *time.Time) (*ScheduleAssignment, error) {
+	if endDate != nil && endDate.Before(startDate) {
+		return nil, ErrInvalidInput
+	}
+	return &ScheduleAssignment{
+		ID:         uuid.New(),
+		UserID:     userID,
+		TemplateID: templateID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Covers reports whether the assignment is in force on date.
+func (a *ScheduleAssignment) Covers(date time.Time) bool {
+	return !date.Before(a.StartDate) && (a.EndDate == nil || !date.After(*a.EndDate))
+}
+
+func (a *ScheduleAssignment) Overlaps(other *ScheduleAssignment) bool {
+	startsBeforeOtherEnds := other.EndDate == nil || !a.StartDate.After(*other.EndDate)
+	endsAfterOtherStarts := a.EndDate == nil || !a.EndDate.Before(other.StartDate)
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
+
+func (a *ScheduleAssignment) cycleStart() time.Time {
+	offset := (int(a.StartDate.Weekday()) + 6) % 7 // Days since Monday
+	return a.StartDate.AddDate(0, 0, -offset)
+}
+
+// PlanShifts returns the shifts of a user starting between from and to (both
+// inclusive) according to their assignments.
+func PlanShifts(assignments []*ScheduleAssignment, templates map[uuid.UUID]*ScheduleTemplate, from, to time.Time) []*Shift {
+	var shifts []*Shift
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		for _, a := range assignments {
+			t, ok := templates[a.TemplateID]
+			if !ok || !a.Covers(d) {
+				continue
+			}
+			if s := t.ShiftOn(d, a.cycleStart()); s != nil {
+				shifts = append(shifts, s)
+			}
+			break
+		}
+	}
+	return shifts
+}
+
+// RestViolation is a pair of consecutive shifts too close to each other.
+type RestViolation struct {
+	ShiftEnd  time.Time `json:"shift_end"`
+	NextStart time.Time `json:"next_start"`
+	RestHours float64   `json:"rest_hours"`
+}
+
+// CheckRest returns where the shifts leave less than MinRestBetweenShifts
+// between them.
+func CheckRest(shifts []*Shift) []RestViolation {
+	sorted := append([]*Shift(nil), shifts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var violations []RestViolation
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1], sorted[i]
+		if rest := next.Start.Sub(prev.End); rest < MinRestBetweenShifts {
+			violations = append(violations, RestViolation{
+				ShiftEnd:  prev.End,
+				NextStart: next.Start,
+				RestHours: roundHours(rest.Hours()),
+			})
+		}
+	}
+	return violations
+}
+
+// RestViolationError lists the shifts of a schedule that break the minimum
+// rest. It matches ErrInsufficientRest with errors.Is.
+type RestViolationError struct {
+	Violations []RestViolation `json:"violations"`
+}
+
+func (e *RestViolationError) Error() string {
+	return fmt.Sprintf("%s: %d violation(s)", ErrInsufficientRest, len(e.Violations))
+}
+
+func (e *RestViolationError) Is(target error) bool {
+	return target == ErrInsufficientRest
+}
+
+// RosterDay is the plan of an employee for one day. Approved full-day
+// absences leave a gap instead of the shift; partial ones are shown next to it.
+type RosterDay struct {
+	Date    time.Time    `json:"date"`
+	Shift   *Shift       `json:"shift,omitempty"`
+	Absence *TeamAbsence `json:"absence,omitempty"`
+}
+
+type RosterEntry struct {
+	UserID     uuid.UUID    `json:"user_id"`
+	UserName   string       `json:"user_name"`
+	Department string       `json:"department,omitempty"`
+	Days       []*RosterDay `json:"days"`
+}
+
+// Roster is the weekly shift plan of a company.
+type Roster struct {
+	CompanyID uuid.UUID      `json:"company_id"`
+	Week      string         `json:"week"` // 2024-W10
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	Users     []*RosterEntry `json:"users"`
+}
+
+// NewRoster plans the week starting on the Monday weekStart for every user.
+// assignments, templates and absences may belong to any user of the company.
+func NewRoster(companyID uuid.UUID, weekStart time.Time, users []*User, assignments []*ScheduleAssignment, templates []*ScheduleTemplate, absences []*TeamAbsence) *Roster {
+	year, week := weekStart.ISOWeek()
+	roster := &Roster{
+		CompanyID: companyID,
+		Week:      fmt.Sprintf("%d-W%02d", year, week),
+		StartDate: weekStart,
+		EndDate:   weekStart.AddDate(0, 0, 6),
+		Users:     []*RosterEntry{},
+	}
+
+	byTemplate := make(map[uuid.UUID]*ScheduleTemplate, len(templates))
+	for _, t := range templates {
+		byTemplate[t.ID] = t
+	}
+	byUser := make(map[uuid.UUID][]*ScheduleAssignment)
+	for _, a := range assignments {
+		byUser[a.UserID] = append(byUser[a.UserID], a)
+	}
+
+	for _, u := range users {
+		entry := &RosterEntry{UserID: u.ID, UserName: u.Name, Department: u.Department}
+		shifts := PlanShifts(byUser[u.ID], byTemplate, roster.StartDate, roster.EndDate)
+		for d := roster.StartDate; !d.After(roster.EndDate); d = d.AddDate(0, 0, 1) {
+			day := &RosterDay{Date: d}
+			for _, s := range shifts {
+				if s.Date.Equal(d) {
+					day.Shift = s
+				}
+			}
+			for _, a := range absences {
+				if a.UserID == u.ID && a.Status == VacationStatusApproved && !d.Before(a.StartDate) && !d.After(a.EndDate) {
+					day.Absence = a
+					if a.Period == PeriodFullDay {
+						day.Shift = nil
+					}
+				}
+			}
+			entry.Days = append(entry.Days, day)
+		}
+		roster.Users = append(roster.Users, entry)
+	}
+	return roster
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckRest(t *testing.T) {
+	// Monday 3 March 2025
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+	shift := func(start, end time.Time) *Shift {
+		return &Shift{Start: start, End: end}
+	}
+
+	tests := []struct {
+		name   string
+		shifts []*Shift
+		want   []float64 // Rest hours of the violations
+	}{
+		{"exactly twelve hours", []*Shift{shift(at(3, 8, 0), at(3, 20, 0)), shift(at(4, 8, 0), at(4, 16, 0))}, nil},
+		{"eleven hours 59 minutes", []*Shift{shift(at(3, 8, 0), at(3, 20, 1)), shift(at(4, 8, 0), at(4, 16, 0))}, []float64{11.98}},
+		{"night shift then morning", []*Shift{shift(at(3, 22, 0), at(4, 6, 0)), shift(at(4, 14, 0), at(4, 22, 0))}, []float64{8}},
+		{"unsorted", []*Shift{shift(at(4, 6, 0), at(4, 14, 0)), shift(at(3, 14, 0), at(3, 22, 0))}, []float64{8}},
+		{"one shift", []*Shift{shift(at(3, 8, 0), at(3, 16, 0))}, nil},
+		{"two violations", []*Shift{shift(at(3, 14, 0), at(3, 22, 0)), shift(at(4, 6, 0), at(4, 14, 0)), shift(at(4, 22, 0), at(5, 6, 0))}, []float64{8, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckRest(tt.shifts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CheckRest = %+v, want %d violation(s)", got, len(tt.want))
+			}
+			for i, v := range got {
+				if v.RestHours != tt.want[i] {
+					t.Errorf("violation %d rest = %.2f h, want %.2f h", i, v.RestHours, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewScheduleTemplate(t *testing.T) {
+	week := func(days ...ShiftPattern) []ShiftPattern {
+		for len(days) < 7 {
+			days = append(days, ShiftPattern{})
+		}
+		return days
+	}
+	morning := ShiftPattern{Start: "08:00", End: "16:00"}
+	afternoon := ShiftPattern{Start: "14:00", End: "22:00"}
+	night := ShiftPattern{Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name string
+		days []ShiftPattern
+		want error
+	}{
+		{"office week", week(morning, morning, morning, morning, morning), nil},
+		{"afternoon then morning", week(afternoon, morning), ErrInsufficientRest},
+		{"night then a rest day", week(night, ShiftPattern{}, morning), nil},
+		{"night then morning", week(night, morning), ErrInsufficientRest},
+		// Sunday night is followed by the Monday of the next cycle
+		{"across the cycle", append(week(morning)[:6], night), ErrInsufficientRest},
+		{"two-week rotation", append(week(morning, morning, morning, morning, morning), week(afternoon, afternoon, afternoon, afternoon, afternoon)...), nil},
+		{"six days", week(morning)[:6], ErrInvalidInput},
+		{"bad time", week(ShiftPattern{Start: "8h", End: "16:00"}), ErrInvalidInput},
+		{"no length", week(ShiftPattern{Start: "08:00", End: "08:00"}), ErrInvalidInput},
+		{"start only", week(ShiftPattern{Start: "08:00"}), ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewScheduleTemplate(uuid.New(), "Turnos", tt.days); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanShifts(t *testing.T) {
+	// Monday 3 March 2025
+	day := func(d int) time.Time {
+		return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	days := make([]ShiftPattern, 14)
+	for i := 0; i < 5; i++ {
+		days[i] = ShiftPattern{Start: "08:00", End: "16:00"}
+		days[7+i] = ShiftPattern{Start: "22:00", End: "06:00"}
+	}
+	template := &ScheduleTemplate{ID: uuid.New(), Days: days}
+	templates := map[uuid.UUID]*ScheduleTemplate{template.ID: template}
+	until := day(16)
+	// Starting on a Wednesday, the cycle still starts on Monday 3
+	assignment := &ScheduleAssignment{TemplateID: template.ID, StartDate: day(5), EndDate: &until}
+
+	shifts := PlanShifts([]*ScheduleAssignment{assignment}, templates, day(3), day(23))
+	var got []int
+	for _, s := range shifts {
+		got = append(got, s.Date.Day())
+	}
+	want := []int{5, 6, 7, 10, 11, 12, 13, 14}
+	if !slices.Equal(got, want) {
+		t.Fatalf("shifts on %v, want %v", got, want)
+	}
+	if last := shifts[len(shifts)-1]; !last.Night || !last.End.Equal(day(15).Add(6*time.Hour)) || last.Hours != 8 {
+		t.Errorf("night shift = %+v, want 8 h ending at 06:00 on the 15th", last)
+	}
+}
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// TaxBracket taxes the part of the base up to UpTo at Rate (percentage).
+// The last bracket of a scale has no limit (UpTo 0).
+type TaxBracket struct {
+	UpTo float64 `json:"up_to"`
+	Rate float64 `json:"rate"`
+}
+
+// TaxTable holds the yearly figures used to simulate a payroll: Social
+// Security contribution rates and bases and the IRPF withholding scale.
+// Amounts are in euros; bases are monthly and rates are percentages.
+type TaxTable struct {
+	Year int `json:"year"`
+
+	// Employee share of the Social Security contributions
+	CommonContingenciesRate     float64 `json:"common_contingencies_rate"`
+	UnemploymentRate            float64 `json:"unemployment_rate"`
+	TemporaryUnemploymentRate   float64 `json:"temporary_unemployment_rate"` // Fixed-term contracts
+	TrainingRate                float64 `json:"training_rate"`               // Formación profesional
+	IntergenerationalEquityRate float64 `json:"intergenerational_equity_rate"`
+	// Company share of the Social Security contributions. The work accident
+	// rate (AT/EP) depends on the activity of the company.
+	EmployerCommonContingenciesRate     float64 `json:"employer_common_contingencies_rate"`
+	EmployerWorkAccidentRate            float64 `json:"employer_work_accident_rate"`
+	EmployerUnemploymentRate            float64 `json:"employer_unemployment_rate"`
+	EmployerTemporaryUnemploymentRate   float64 `json:"employer_temporary_unemployment_rate"`
+	EmployerTrainingRate                float64 `json:"employer_training_rate"`
+	EmployerFOGASARate                  float64 `json:"employer_fogasa_rate"`
+	EmployerIntergenerationalEquityRate float64 `json:"employer_intergenerational_equity_rate"`
+	// MinContributionBases is indexed by contribution group (1 to 11)
+	MinContributionBases map[int]float64 `json:"min_contribution_bases"`
+	MaxContributionBase  float64         `json:"max_contribution_base"`
+
+	// IRPF: general scale (state and regional share) and allowances
+	IRPFScale               []TaxBracket `json:"irpf_scale"`
+	PersonalAllowance       float64      `json:"personal_allowance"`        // Mínimo personal
+	OtherDeductibleExpenses float64      `json:"other_deductible_expenses"` // Otros gastos deducibles
+	// The work income reduction is WorkIncomeReduction for net work income
+	// up to WorkIncomeReductionFrom, falling linearly to zero at
+	// WorkIncomeReductionTo.
+	WorkIncomeReduction     float64 `json:"work_income_reduction"`
+	WorkIncomeReductionFrom float64 `json:"work_income_reduction_from"`
+	WorkIncomeReductionTo   float64 `json:"work_income_reduction_to"`
+	// No withholding applies to yearly gross salaries up to this amount, and
+	// above it the tax cannot exceed WithholdingCapRate of the excess
+	WithholdingExemptUpTo float64 `json:"withholding_exempt_up_to"`
+	WithholdingCapRate    float64 `json:"withholding_cap_rate"`
+}
+
+// IsValid checks the table can be used by the payroll calculator.
+func (t *TaxTable) IsValid() bool {
+	if t.Year < 2000 || t.MaxContributionBase <= 0 || len(t.IRPFScale) == 0 {
+		return false
+	}
+	for group := 1; group <= ContributionGroups; group++ {
+		if t.MinContributionBases[group] <= 0 || t.MinContributionBases[group] > t.MaxContributionBase {
+			return false
+		}
+	}
+	for i, b := range t.IRPFScale {
+		last := i == len(t.IRPFScale)-1
+		if b.Rate < 0 || b.Rate > 100 || (!last && b.UpTo <= 0) || (i > 0 && !last && b.UpTo <= t.IRPFScale[i-1].UpTo) {
+			return false
+		}
+	}
+	return true
+}
+
+// tax applies the IRPF scale to base.
+func (t *TaxTable) tax(base float64) float64 {
+	var tax, from float64
+	for _, b := range t.IRPFScale {
+		if b.UpTo == 0 || base <= b.UpTo {
+			return tax + (base-from)*b.Rate/100
+		}
+		tax += (b.UpTo - from) * b.Rate / 100
+		from = b.UpTo
+	}
+	return tax
+}
+
+// workIncomeReduction returns the reduction of net work income (art. 20 LIRPF).
+func (t *TaxTable) workIncomeReduction(netIncome float64) float64 {
+	switch {
+	case netIncome <= t.WorkIncomeReductionFrom:
+		return t.WorkIncomeReduction
+	case netIncome >= t.WorkIncomeReductionTo:
+		return 0
+	}
+	share := (t.WorkIncomeReductionTo - netIncome) / (t.WorkIncomeReductionTo - t.WorkIncomeReductionFrom)
+	return t.WorkIncomeReduction * share
+}
+
+// TaxTables are the tables of several years, by year.
+type TaxTables map[int]*TaxTable
+
+// ForYear returns the table of year or, if it was not published yet, the
+// latest one before it.
+func (tables TaxTables) ForYear(year int) (*TaxTable, bool) {
+	years := make([]int, 0, len(tables))
+	for y := range tables {
+		if y <= year {
+			years = append(years, y)
+		}
+	}
+	if len(years) == 0 {
+		return nil, false
+	}
+	sort.Ints(years)
+	return tables[years[len(years)-1]], true
+}
+
+// generalIRPFScale is the state scale plus the default regional one (arts.
+// 63 and 74 LIRPF).
+var generalIRPFScale = []TaxBracket{
+	{UpTo: 12450, Rate: 19},
+	{UpTo: 20200, Rate: 24},
+	{UpTo: 35200, Rate: 30},
+	{UpTo: 60000, Rate: 37},
+	{UpTo: 300000, Rate: 45},
+	{Rate: 47},
+}
+
+// minContributionBases builds the minimum bases of the year: groups 1 to 3
+// have their own, the rest share the one derived from the minimum wage.
+func minContributionBases(group1, group2, group3, others float64) map[int]float64 {
+	bases := map[int]float64{1: group1, 2: group2, 3: group3}
+	for group := 4; group <= ContributionGroups; group++ {
+		bases[group] = others
+	}
+	return bases
+}
+
+// DefaultTaxTables returns the built-in tables, which can be replaced or
+// completed by configuration when new figures are published.
+func DefaultTaxTables() TaxTables {
+	table := func(year int, mei, employerMEI, maxBase float64, minBases map[int]float64) *TaxTable {
+		return &TaxTable{
+			Year:                                year,
+			CommonContingenciesRate:             4.70,
+			UnemploymentRate:                    1.55,
+			TemporaryUnemploymentRate:           1.60,
+			TrainingRate:                        0.10,
+			IntergenerationalEquityRate:         mei,
+			EmployerCommonContingenciesRate:     23.60,
+			EmployerWorkAccidentRate:            1.50,
+			EmployerUnemploymentRate:            5.50,
+			EmployerTemporaryUnemploymentRate:   6.70,
+			EmployerTrainingRate:                0.60,
+			EmployerFOGASARate:                  0.20,
+			EmployerIntergenerationalEquityRate: employerMEI,
+			MinContributionBases:                minBases,
+			MaxContributionBase:                 maxBase,
+			IRPFScale:                           generalIRPFScale,
+			PersonalAllowance:                   5550,
+			OtherDeductibleExpenses:             2000,
+			WorkIncomeReduction:                 7302,
+			WorkIncomeReductionFrom:             14852,
+			WorkIncomeReductionTo:               19747.50,
+			WithholdingExemptUpTo:               15876,
+			WithholdingCapRate:                  43,
+		}
+	}
+	return TaxTables{
+		2024: table(2024, 0.12, 0.58, 4720.50, minContributionBases(1847.40, 1531.50, 1332.90, 1323.00)),
+		2025: table(2025, 0.13, 0.67, 4909.50, minContributionBases(1929.00, 1599.60, 1391.70, 1381.20)),
+	}
+}
+
+func roundEuros(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
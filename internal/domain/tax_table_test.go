@@ -0,0 +1,101 @@
+package domain
+
+import "testing"
+
+func TestDefaultTaxTablesAreValid(t *testing.T) {
+	for year, table := range DefaultTaxTables() {
+		if table.Year != year {
+			t.Errorf("table under %d has year %d", year, table.Year)
+		}
+		if !table.IsValid() {
+			t.Errorf("table %d is not valid", year)
+		}
+	}
+}
+
+func TestTaxTablesForYear(t *testing.T) {
+	tables := DefaultTaxTables()
+	tests := []struct {
+		year int
+		want int
+		ok   bool
+	}{
+		{2023, 0, false},
+		{2024, 2024, true},
+		{2025, 2025, true},
+		{2027, 2025, true}, // Not published yet: latest one
+	}
+	for _, tt := range tests {
+		table, ok := tables.ForYear(tt.year)
+		if ok != tt.ok {
+			t.Fatalf("ForYear(%d) ok = %v, want %v", tt.year, ok, tt.ok)
+		}
+		if ok && table.Year != tt.want {
+			t.Errorf("ForYear(%d) = %d, want %d", tt.year, table.Year, tt.want)
+		}
+	}
+}
+
+func TestTaxTableTax(t *testing.T) {
+	table, _ := DefaultTaxTables().ForYear(2024)
+	tests := []struct {
+		base float64
+		want float64
+	}{
+		{0, 0},
+		{5550, 1054.50},   // Personal allowance at 19%
+		{12450, 2365.50},  // End of the first bracket
+		{20000, 4177.50},  // 2365.50 + 7550 x 24%
+		{26059, 5983.20},  // 2365.50 + 1860 + 5859 x 30%
+		{70000, 22401.50}, // 45% above 60000
+	}
+	for _, tt := range tests {
+		if got := roundEuros(table.tax(tt.base)); got != tt.want {
+			t.Errorf("tax(%.2f) = %.2f, want %.2f", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestTaxTableWorkIncomeReduction(t *testing.T) {
+	table, _ := DefaultTaxTables().ForYear(2025)
+	tests := []struct {
+		netIncome float64
+		want      float64
+	}{
+		{10000, 7302},
+		{14852, 7302},
+		{17299.75, 3651}, // Halfway: half the reduction
+		{19747.50, 0},
+		{30000, 0},
+	}
+	for _, tt := range tests {
+		if got := roundEuros(table.workIncomeReduction(tt.netIncome)); got != tt.want {
+			t.Errorf("workIncomeReduction(%.2f) = %.2f, want %.2f", tt.netIncome, got, tt.want)
+		}
+	}
+}
+
+func TestTaxTableIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*TaxTable)
+	}{
+		{"no scale", func(t *TaxTable) { t.IRPFScale = nil }},
+		{"no maximum base", func(t *TaxTable) { t.MaxContributionBase = 0 }},
+		{"missing group", func(t *TaxTable) { delete(t.MinContributionBases, 11) }},
+		{"minimum above maximum", func(t *TaxTable) { t.MinContributionBases[1] = t.MaxContributionBase + 1 }},
+		{"unsorted scale", func(t *TaxTable) {
+			t.IRPFScale = []TaxBracket{{UpTo: 20000, Rate: 19}, {UpTo: 10000, Rate: 24}, {Rate: 30}}
+		}},
+		{"rate above 100", func(t *TaxTable) { t.IRPFScale = []TaxBracket{{Rate: 101}} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := DefaultTaxTables()[2024]
+			tt.modify(table)
+			if table.IsValid() {
+				t.Error("IsValid() = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type (
+	companyScopeKey struct{}
+	unscopedKey     struct{}
+)
+
+// WithCompanyScope restricts the lookups made with ctx to the data of a
+// company: anything else is not found. Requests are scoped to the company of
+// the caller, or of the record an anonymous request resolves, such as the
+// user of a reset token. A context with no scope finds nothing.
+func WithCompanyScope(ctx context.Context, companyID uuid.UUID) context.Context {
+	return context.WithValue(ctx, companyScopeKey{}, companyID)
+}
+
+// CompanyScope returns the company ctx is restricted to, if any.
+func CompanyScope(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(companyScopeKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// Unscoped lets the lookups made with ctx reach the data of every company.
+// Only the background jobs, which serve no caller, use it; a company scope
+// set afterwards still applies.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether ctx was marked with Unscoped.
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrContractTerminated is returned when terminating a contract twice.
+var ErrContractTerminated = errors.New("contract already terminated")
+
+type TerminationReason string
+
+const (
+	TerminationResignation           TerminationReason = "RESIGNATION"
+	TerminationDisciplinaryDismissal TerminationReason = "DISCIPLINARY_DISMISSAL" // Fair dismissal, no severance
+	TerminationObjectiveDismissal    TerminationReason = "OBJECTIVE_DISMISSAL"
+	TerminationUnfairDismissal       TerminationReason = "UNFAIR_DISMISSAL"
+	TerminationEndOfTemporary        TerminationReason = "END_OF_TEMPORARY"
+)
+
+func (r TerminationReason) IsValid() bool {
+	_, _, ok := r.severanceTerms()
+	return ok
+}
+
+// severanceTerms returns the days of salary per year of service and the cap
+// in days of salary (0 for none): art. 53, 56 and 49.1.c ET.
+func (r TerminationReason) severanceTerms() (float64, float64, bool) {
+	switch r {
+	case TerminationResignation, TerminationDisciplinaryDismissal:
+		return 0, 0, true
+	case TerminationObjectiveDismissal:
+		return 20, 360, true // 12 monthly payments
+	case TerminationUnfairDismissal:
+		return 33, 720, true // 24 monthly payments
+	case TerminationEndOfTemporary:
+		return 12, 0, true
+	}
+	return 0, 0, false
+}
+
+// SeniorityGapDays is the longest break between two contracts of the same
+// employee that still counts as a single employment for severance.
+const SeniorityGapDays = 20
+
+// ContractTermination ends a contract on Date, the last day worked, whatever
+// its end date was. Terminations are never updated or deleted.
+type ContractTermination struct {
+	ContractID uuid.UUID         `json:"contract_id"`
+	Date       time.Time         `json:"date"`
+	Reason     TerminationReason `json:"reason"`
+	Settlement Settlement        `json:"settlement"`
+	AuthorID   *uuid.UUID        `json:"author_id,omitempty"` // Nil once the author is deleted
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Settlement is the final settlement (finiquito) of a contract. Amounts are
+// gross, before contributions and IRPF.
+type Settlement struct {
+	PaymentsPerYear int `json:"payments_per_year"`
+	// Days of the last month not paid in a payslip yet
+	PendingSalaryDays int     `json:"pending_salary_days"`
+	PendingSalary     float64 `json:"pending_salary"`
+	// Part of the next extra payment accrued, with 14 payments
+	ExtraPayments float64 `json:"extra_payments"`
+	// Vacation accrued and not taken in the year, in the unit of the balance.
+	// Negative when the employee took more days than accrued.
+	VacationDays  float64   `json:"vacation_days"`
+	VacationUnit  DayUnit   `json:"vacation_unit"`
+	VacationPay   float64   `json:"vacation_pay"`
+	SeniorityDate time.Time `json:"seniority_date"`
+	SeveranceDays float64   `json:"severance_days"`
+	Severance     float64   `json:"severance"`
+	Total         float64   `json:"total"`
+}
+
+// Terminate ends the contract on date. It fails if the contract is already
+// terminated or over by then, and END_OF_TEMPORARY only applies to
+// temporary contracts. The settlement is computed afterwards with Settle,
+// since the vacation accrued depends on the new end date.
+func (c *Contract) Terminate(date time.Time, reason TerminationReason, authorID uuid.UUID, now time.Time) (*ContractTermination, error) {
+	if c.Termination != nil {
+		return nil, ErrContractTerminated
+	}
+	v := &ValidationError{}
+	if !reason.IsValid() {
+		v.Add("reason", "must be RESIGNATION, DISCIPLINARY_DISMISSAL, OBJECTIVE_DISMISSAL, UNFAIR_DISMISSAL or END_OF_TEMPORARY")
+	}
+	terms := c.AsOf(date)
+	switch {
+	case date.Before(c.StartDate):
+		v.Add("date", "cannot be before the start of the contract")
+	case terms.EndDate != nil && date.After(*terms.EndDate):
+		v.Add("date", "the contract is over")
+	}
+	if reason == TerminationEndOfTemporary && terms.Type != ContractTypeTemporary {
+		v.Add("reason", "only temporary contracts end by expiry with severance")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	t := &ContractTermination{
+		ContractID: c.ID,
+		Date:       dateOf(date),
+		Reason:     reason,
+		AuthorID:   &authorID,
+		CreatedAt:  now,
+	}
+	c.Termination = t
+	return t, nil
+}
+
+// applyTo brings the end date of the terms forward to the termination date.
+func (t *ContractTermination) applyTo(terms *Contract) {
+	if terms.EndDate == nil || t.Date.Before(*terms.EndDate) {
+		date := t.Date
+		terms.EndDate = &date
+	}
+}
+
+// Settle computes the settlement of the terminated contract c from the terms
+// in force on the last day:
+//   - the days of the last month not covered by one of its payslips,
+//   - with 14 payments, the extra payment accrued since the last one, unless
+//     the payslip of June or December already paid it,
+//   - the vacation balance of the year (entitled, carried over and not
+//     taken), 22 working days being worth 30 natural days,
+//   - the severance of the reason, per year of service since the seniority
+//     date and pro rata per day.
+//
+// history holds every contract of the employee: earlier contracts ending at
+// most SeniorityGapDays before the next one add to the seniority.
+func (t *ContractTermination) Settle(c *Contract, history []*Contract, payslips []*Payslip, paymentsPerYear int, balance *VacationBalance) error {
+	if paymentsPerYear != 12 && paymentsPerYear != 14 {
+		return &ValidationError{Fields: []FieldError{{Field: "payments", Message: "must be 12 or 14"}}}
+	}
+	terms := c.AsOf(t.Date)
+	s := Settlement{PaymentsPerYear: paymentsPerYear, VacationUnit: balance.Unit}
+	payment := terms.Salary / float64(paymentsPerYear)
+	daily := terms.Salary / 365
+
+	monthStart, next := MonthBounds(t.Date)
+	paid := false
+	for _, p := range payslips {
+		if p.ContractID == c.ID && p.Period == monthStart.Format("2006-01") {
+			paid = true
+		}
+	}
+	if from, to, ok := terms.overlap(monthStart, t.Date); ok && !paid {
+		// Commercial month, as in the payslips
+		s.PendingSalaryDays = 30
+		if !from.Equal(monthStart) || !to.Equal(next.AddDate(0, 0, -1)) {
+			s.PendingSalaryDays = min(int(to.Sub(from).Hours()/24)+1, 30)
+		}
+		s.PendingSalary = roundEuros(payment * float64(s.PendingSalaryDays) / 30)
+	}
+
+	if paymentsPerYear == 14 {
+		halfStart := time.Date(t.Date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		if t.Date.Month() > time.June {
+			halfStart = halfStart.AddDate(0, 6, 0)
+		}
+		halfEnd := halfStart.AddDate(0, 6, -1)
+		extraPaid := paid && (monthStart.Month() == time.June || monthStart.Month() == time.December)
+		if from, to, ok := terms.overlap(halfStart, t.Date); ok && !extraPaid {
+			worked := to.Sub(from).Hours()/24 + 1
+			s.ExtraPayments = roundEuros(payment * worked / (halfEnd.Sub(halfStart).Hours()/24 + 1))
+		}
+	}
+
+	s.VacationDays = roundDays(balance.Remaining + balance.Pending)
+	naturalDays := s.VacationDays
+	if balance.Unit == DayUnitWorking {
+		naturalDays = s.VacationDays * 30 / 22
+	}
+	s.VacationPay = roundEuros(naturalDays * daily)
+
+	s.SeniorityDate = seniorityDate(c, history)
+	perYear, maxDays, _ := t.Reason.severanceTerms()
+	years := (t.Date.Sub(s.SeniorityDate).Hours()/24 + 1) / 365
+	s.SeveranceDays = roundDays(perYear * years)
+	if maxDays > 0 {
+		s.SeveranceDays = math.Min(s.SeveranceDays, maxDays)
+	}
+	s.Severance = roundEuros(s.SeveranceDays * daily)
+
+	s.Total = roundEuros(s.PendingSalary + s.ExtraPayments + s.VacationPay + s.Severance)
+	t.Settlement = s
+	return nil
+}
+
+// seniorityDate walks back from c through the contracts of the employee that
+// follow each other without a break longer than SeniorityGapDays.
+func seniorityDate(c *Contract, history []*Contract) time.Time {
+	seniority := dateOf(c.StartDate)
+	for changed := true; changed; {
+		changed = false
+		for _, h := range history {
+			if h.ID == c.ID || !dateOf(h.StartDate).Before(seniority) {
+				continue
+			}
+			end := h.AsOf(seniority).EndDate
+			if end != nil && seniority.Sub(dateOf(*end)).Hours()/24 <= SeniorityGapDays+1 {
+				seniority, changed = dateOf(h.StartDate), true
+			}
+		}
+	}
+	return seniority
+}
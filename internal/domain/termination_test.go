@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// settlementContract is a full-time contract with the given terms.
+func settlementContract(contractType ContractType, start time.Time, end *time.Time, salary float64, group int) *Contract {
+	return &Contract{
+		ID:                 uuid.New(),
+		UserID:             uuid.New(),
+		StartDate:          start,
+		EndDate:            end,
+		Type:               contractType,
+		Salary:             salary,
+		WeeklyHours:        FullTimeWeeklyHours,
+		PartTimePercentage: 100,
+		ContributionGroup:  group,
+	}
+}
+
+func settlementDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestContractTerminationSettle(t *testing.T) {
+	// 36500 a year is 100 a day
+	tests := []struct {
+		name          string
+		contractType  ContractType
+		start         time.Time
+		date          time.Time
+		reason        TerminationReason
+		payments      int
+		vacationDays  float64
+		pendingDays   int
+		pending       float64
+		extra         float64
+		vacationPay   float64
+		severanceDays float64
+		severance     float64
+		total         float64
+	}{
+		{
+			// 33 days a year over 24 years is capped at 720 days
+			name: "unfair dismissal capped", contractType: ContractTypeIndefinite,
+			start: settlementDate(2000, time.January, 1), date: settlementDate(2024, time.June, 30),
+			reason: TerminationUnfairDismissal, payments: 12, vacationDays: 5,
+			pendingDays: 30, pending: 3041.67, vacationPay: 681.82, // 5 x 30 / 22 natural days
+			severanceDays: 720, severance: 72000, total: 75723.49,
+		},
+		{
+			// 1096 days, 2024 being a leap year
+			name: "objective dismissal", contractType: ContractTypeIndefinite,
+			start: settlementDate(2021, time.July, 1), date: settlementDate(2024, time.June, 30),
+			reason: TerminationObjectiveDismissal, payments: 12,
+			pendingDays: 30, pending: 3041.67,
+			severanceDays: 60.05, severance: 6005, total: 9046.67,
+		},
+		{
+			name: "objective dismissal capped", contractType: ContractTypeIndefinite,
+			start: settlementDate(2000, time.January, 1), date: settlementDate(2024, time.June, 30),
+			reason: TerminationObjectiveDismissal, payments: 12,
+			pendingDays: 30, pending: 3041.67,
+			severanceDays: 360, severance: 36000, total: 39041.67,
+		},
+		{
+			name: "resignation", contractType: ContractTypeIndefinite,
+			start: settlementDate(2000, time.January, 1), date: settlementDate(2024, time.June, 30),
+			reason: TerminationResignation, payments: 12,
+			pendingDays: 30, pending: 3041.67, total: 3041.67,
+		},
+		{
+			// 182 days at 12 days a year, without a cap
+			name: "end of temporary contract", contractType: ContractTypeTemporary,
+			start: settlementDate(2024, time.January, 1), date: settlementDate(2024, time.June, 30),
+			reason: TerminationEndOfTemporary, payments: 12,
+			pendingDays: 30, pending: 3041.67,
+			severanceDays: 5.98, severance: 598, total: 3639.67,
+		},
+		{
+			// The December extra payment accrues from July: 77 of 184 days
+			name: "extra payment pro rata", contractType: ContractTypeIndefinite,
+			start: settlementDate(2024, time.January, 1), date: settlementDate(2024, time.September, 15),
+			reason: TerminationResignation, payments: 14,
+			pendingDays: 15, pending: 1303.57, extra: 1091.03, total: 2394.60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := settlementContract(tt.contractType, tt.start, nil, 36500, 1)
+			term, err := c.Terminate(tt.date, tt.reason, uuid.New(), time.Now())
+			if err != nil {
+				t.Fatalf("Terminate: %v", err)
+			}
+			balance := &VacationBalance{Unit: DayUnitWorking, Remaining: tt.vacationDays}
+			if err := term.Settle(c, []*Contract{c}, nil, tt.payments, balance); err != nil {
+				t.Fatalf("Settle: %v", err)
+			}
+
+			s := term.Settlement
+			if s.PendingSalaryDays != tt.pendingDays {
+				t.Errorf("pending salary days = %d, want %d", s.PendingSalaryDays, tt.pendingDays)
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"pending salary", s.PendingSalary, tt.pending},
+				{"extra payments", s.ExtraPayments, tt.extra},
+				{"vacation pay", s.VacationPay, tt.vacationPay},
+				{"severance days", s.SeveranceDays, tt.severanceDays},
+				{"severance", s.Severance, tt.severance},
+				{"total", s.Total, tt.total},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %.2f, want %.2f", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestContractTerminationSettlePaidMonth(t *testing.T) {
+	c := settlementContract(ContractTypeIndefinite, settlementDate(2024, time.January, 1), nil, 36500, 1)
+	term, err := c.Terminate(settlementDate(2024, time.June, 30), TerminationResignation, uuid.New(), time.Now())
+	if err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	// The June payslip paid the month and the extra payment
+	payslips := []*Payslip{{ContractID: c.ID, Period: "2024-06"}}
+	if err := term.Settle(c, []*Contract{c}, payslips, 14, &VacationBalance{Unit: DayUnitWorking}); err != nil {
+		t.Fatalf("Settle: %v", err)
+	}
+	if s := term.Settlement; s.PendingSalary != 0 || s.ExtraPayments != 0 || s.Total != 0 {
+		t.Errorf("settlement = %+v, want nothing pending", s)
+	}
+}
+
+func TestSeniorityDate(t *testing.T) {
+	tests := []struct {
+		name string
+		gap  int // Days between the contracts
+		want time.Time
+	}{
+		{"short break", 15, settlementDate(2020, time.January, 1)},
+		{"longest break", SeniorityGapDays, settlementDate(2020, time.January, 1)},
+		{"long break", 32, settlementDate(2022, time.January, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := settlementContract(ContractTypeIndefinite, settlementDate(2022, time.January, 1), nil, 36500, 1)
+			end := current.StartDate.AddDate(0, 0, -tt.gap-1)
+			earlier := settlementContract(ContractTypeTemporary, settlementDate(2020, time.January, 1), &end, 30000, 1)
+
+			if got := seniorityDate(current, []*Contract{earlier, current}); !got.Equal(tt.want) {
+				t.Errorf("seniorityDate = %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestContractTerminate(t *testing.T) {
+	end := settlementDate(2024, time.June, 30)
+	tests := []struct {
+		name     string
+		contract *Contract
+		date     time.Time
+		reason   TerminationReason
+		field    string
+	}{
+		{"unknown reason", settlementContract(ContractTypeIndefinite, settlementDate(2024, time.January, 1), nil, 30000, 1), settlementDate(2024, time.March, 1), "RETIREMENT", "reason"},
+		{"before the start", settlementContract(ContractTypeIndefinite, settlementDate(2024, time.January, 1), nil, 30000, 1), settlementDate(2023, time.December, 31), TerminationResignation, "date"},
+		{"after the end", settlementContract(ContractTypeTemporary, settlementDate(2024, time.January, 1), &end, 30000, 1), settlementDate(2024, time.July, 1), TerminationResignation, "date"},
+		{"expiry of an indefinite contract", settlementContract(ContractTypeIndefinite, settlementDate(2024, time.January, 1), nil, 30000, 1), settlementDate(2024, time.March, 1), TerminationEndOfTemporary, "reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.contract.Terminate(tt.date, tt.reason, uuid.New(), time.Now())
+			v, ok := err.(*ValidationError)
+			if !ok || len(v.Fields) != 1 || v.Fields[0].Field != tt.field {
+				t.Fatalf("err = %v, want a validation error on %s", err, tt.field)
+			}
+		})
+	}
+
+	c := settlementContract(ContractTypeIndefinite, settlementDate(2024, time.January, 1), nil, 30000, 1)
+	if _, err := c.Terminate(settlementDate(2024, time.March, 1), TerminationResignation, uuid.New(), time.Now()); err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	if _, err := c.Terminate(settlementDate(2024, time.April, 1), TerminationResignation, uuid.New(), time.Now()); err != ErrContractTerminated {
+		t.Errorf("second Terminate err = %v, want ErrContractTerminated", err)
+	}
+}
@@ -0,0 +1,313 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+	_ "time/tzdata" // The register time zone must load without system zoneinfo
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAlreadyClockedIn      = errors.New("user is already clocked in")
+	ErrNotClockedIn          = errors.New("user is not clocked in")
+	ErrBreakInProgress       = errors.New("a break is already in progress")
+	ErrNoBreakInProgress     = errors.New("no break in progress")
+	ErrJustificationRequired = errors.New("a justification is required to edit a time entry")
+)
+
+// RegisterLocation is the time zone of the workplace. The register assigns
+// entries to the day and month they started on there, not in UTC: an entry
+// clocked in at 00:30 in Madrid belongs to that day even if it is 22:30 UTC.
+var RegisterLocation = mustLoadLocation("Europe/Madrid")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// TimeEntry is one working period of the working-time register (registro de
+// jornada, RDL 8/2019): when the employee clocked in and out, and the breaks
+// taken in between. ClockOut is nil while the employee is at work.
+type TimeEntry struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	ClockIn   time.Time    `json:"clock_in"`
+	ClockOut  *time.Time   `json:"clock_out,omitempty"`
+	Breaks    []*TimeBreak `json:"breaks"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TimeBreak is a pause within a time entry. End is nil while it lasts.
+type TimeBreak struct {
+	ID    uuid.UUID  `json:"id"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// TimeEntryEdit keeps what an admin changed on an entry and why, so the
+// register stays auditable by the labour inspection.
+type TimeEntryEdit struct {
+	ID               uuid.UUID  `json:"id"`
+	TimeEntryID      uuid.UUID  `json:"time_entry_id"`
+	EditorID         *uuid.UUID `json:"editor_id,omitempty"` // Nil once the editor is deleted
+	Reason           string     `json:"reason"`
+	PreviousClockIn  time.Time  `json:"previous_clock_in"`
+	PreviousClockOut *time.Time `json:"previous_clock_out,omitempty"`
+	ClockIn          time.Time  `json:"clock_in"`
+	ClockOut         *time.Time `json:"clock_out,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// NewTimeEntry clocks the user in at now.
+func NewTimeEntry(userID uuid.UUID, now time.Time) *TimeEntry {
+	return &TimeEntry{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ClockIn:   now,
+		Breaks:    []*TimeBreak{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (e *TimeEntry) IsOpen() bool {
+	return e.ClockOut == nil
+}
+
+// Close clocks out, ending any break still in progress.
+func (e *TimeEntry) Close(now time.Time) error {
+	if !e.IsOpen() {
+		return ErrNotClockedIn
+	}
+	if b := e.openBreak(); b != nil {
+		b.End = &now
+	}
+	e.ClockOut = &now
+	e.UpdatedAt = now
+	return nil
+}
+
+func (e *TimeEntry) StartBreak(now time.Time) error {
+	if !e.IsOpen() {
+		return ErrNotClockedIn
+	}
+	if e.openBreak() != nil {
+		return ErrBreakInProgress
+	}
+	e.Breaks = append(e.Breaks, &TimeBreak{ID: uuid.New(), Start: now})
+	e.UpdatedAt = now
+	return nil
+}
+
+func (e *TimeEntry) EndBreak(now time.Time) error {
+	b := e.openBreak()
+	if b == nil {
+		return ErrNoBreakInProgress
+	}
+	b.End = &now
+	e.UpdatedAt = now
+	return nil
+}
+
+func (e *TimeEntry) openBreak() *TimeBreak {
+	for _, b := range e.Breaks {
+		if b.End == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// Edit corrects the clock times of the entry on behalf of an admin. Every
+// break must still fall within the corrected times.
+func (e *TimeEntry) Edit(clockIn time.Time, clockOut *time.Time, editorID uuid.UUID, reason string, now time.Time) (*TimeEntryEdit, error) {
+	if reason == "" {
+		return nil, ErrJustificationRequired
+	}
+	if clockOut != nil && !clockOut.After(clockIn) {
+		return nil, ErrInvalidInput
+	}
+	for _, b := range e.Breaks {
+		if b.Start.Before(clockIn) {
+			return nil, ErrInvalidInput
+		}
+		if clockOut != nil && (b.End == nil || b.End.After(*clockOut)) {
+			return nil, ErrInvalidInput
+		}
+	}
+
+	edit := &TimeEntryEdit{
+		ID:               uuid.New(),
+		TimeEntryID:      e.ID,
+		EditorID:         &editorID,
+		Reason:           reason,
+		PreviousClockIn:  e.ClockIn,
+		PreviousClockOut: e.ClockOut,
+		ClockIn:          clockIn,
+		ClockOut:         clockOut,
+		CreatedAt:        now,
+	}
+	e.ClockIn = clockIn
+	e.ClockOut = clockOut
+	e.UpdatedAt = now
+	return edit, nil
+}
+
+// Worked returns the time at work excluding breaks. Open entries and breaks
+// are counted up to now.
+func (e *TimeEntry) Worked(now time.Time) time.Duration {
+	end := now
+	if e.ClockOut != nil {
+		end = *e.ClockOut
+	}
+	return end.Sub(e.ClockIn) - e.OnBreak(now)
+}
+
+// OnBreak returns the total duration of the breaks of the entry.
+func (e *TimeEntry) OnBreak(now time.Time) time.Duration {
+	var total time.Duration
+	for _, b := range e.Breaks {
+		end := now
+		if b.End != nil {
+			end = *b.End
+		}
+		total += end.Sub(b.Start)
+	}
+	return total
+}
+
+// DailyTime aggregates the entries that started on a given day.
+type DailyTime struct {
+	Date          string       `json:"date"` // YYYY-MM-DD
+	FirstIn       time.Time    `json:"first_in"`
+	LastOut       *time.Time   `json:"last_out,omitempty"`
+	WorkedHours   float64      `json:"worked_hours"`
+	BreakHours    float64      `json:"break_hours"`
+	OvertimeHours float64      `json:"overtime_hours"` // Beyond the contracted hours of the day
+	Entries       []*TimeEntry `json:"entries"`
+}
+
+// TimeSummary is the monthly working-time register of an employee.
+type TimeSummary struct {
+	UserID          uuid.UUID      `json:"user_id"`
+	Month           string         `json:"month"` // YYYY-MM
+	Days            []*DailyTime   `json:"days"`
+	Weeks           []*HoursPeriod `json:"weeks"` // Ending in the month
+	ContractedHours float64        `json:"contracted_hours"`
+	WorkedHours     float64        `json:"worked_hours"`
+	BreakHours      float64        `json:"break_hours"`
+	OvertimeHours   float64        `json:"overtime_hours"` // Of the weeks ending in the month
+	OpenEntries     int            `json:"open_entries"`   // Not clocked out yet
+}
+
+// NewTimeSummary groups the entries of a month by the day they started on.
+// Entries must be sorted by clock-in time. Contracted hours and overtime are
+// taken from the overtime report of the year, so both agree: overtime is
+// counted per week against the contract and each month adds up the weeks
+// ending in it. The overtime of each day is informative: it is the time worked
+// beyond the contracted hours of that day.
+func NewTimeSummary(userID uuid.UUID, month time.Time, entries []*TimeEntry, overtime *OvertimeReport, now time.Time) *TimeSummary {
+	summary := &TimeSummary{
+		UserID: userID,
+		Month:  month.Format("2006-01"),
+		Days:   []*DailyTime{},
+		Weeks:  []*HoursPeriod{},
+	}
+
+	var day *DailyTime
+	for _, e := range entries {
+		date := RegisterDate(e.ClockIn)
+		if day == nil || day.Date != date {
+			day = &DailyTime{Date: date, FirstIn: e.ClockIn}
+			summary.Days = append(summary.Days, day)
+		}
+		day.Entries = append(day.Entries, e)
+		day.WorkedHours = roundHours(day.WorkedHours + e.Worked(now).Hours())
+		day.BreakHours = roundHours(day.BreakHours + e.OnBreak(now).Hours())
+		if e.ClockOut != nil && (day.LastOut == nil || e.ClockOut.After(*day.LastOut)) {
+			day.LastOut = e.ClockOut
+		}
+
+		summary.WorkedHours = roundHours(summary.WorkedHours + e.Worked(now).Hours())
+		summary.BreakHours = roundHours(summary.BreakHours + e.OnBreak(now).Hours())
+		if e.IsOpen() {
+			summary.OpenEntries++
+		}
+	}
+
+	for _, d := range summary.Days {
+		d.OvertimeHours = roundHours(math.Max(0, d.WorkedHours-overtime.ContractedOn(d.Date)))
+	}
+	for _, m := range overtime.Months {
+		if m.Period == summary.Month {
+			summary.ContractedHours = m.ContractedHours
+			summary.OvertimeHours = m.OvertimeHours
+		}
+	}
+	for _, w := range overtime.Weeks {
+		if w.EndDate.Format("2006-01") == summary.Month {
+			summary.Weeks = append(summary.Weeks, w)
+		}
+	}
+	return summary
+}
+
+// TimeRegisterAcknowledgement records that the employee signed their
+// register of a month as correct.
+type TimeRegisterAcknowledgement struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Month          string    `json:"month"` // YYYY-MM
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+}
+
+func NewTimeRegisterAcknowledgement(userID uuid.UUID, month time.Time, now time.Time) (*TimeRegisterAcknowledgement, error) {
+	if _, next := RegisterMonthBounds(month); now.Before(next) {
+		return nil, ErrInvalidInput // The month must be over
+	}
+	return &TimeRegisterAcknowledgement{
+		UserID:         userID,
+		Month:          month.Format("2006-01"),
+		AcknowledgedAt: now,
+	}, nil
+}
+
+// TimeRegister is the monthly register handed to the labour inspection.
+type TimeRegister struct {
+	Company         *Company                     `json:"company"`
+	User            *User                        `json:"user"`
+	Summary         *TimeSummary                 `json:"summary"`
+	Acknowledgement *TimeRegisterAcknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// MonthBounds returns the first instant of the month and of the next one.
+func MonthBounds(month time.Time) (time.Time, time.Time) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// RegisterMonthBounds returns the first instant of the month and of the next
+// one in RegisterLocation. Only the year and month of month are used.
+func RegisterMonthBounds(month time.Time) (time.Time, time.Time) {
+	return MonthBounds(time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, RegisterLocation))
+}
+
+// RegisterDayStart returns the first instant of the given date in
+// RegisterLocation. Only the date of day is used.
+func RegisterDayStart(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, RegisterLocation)
+}
+
+// RegisterDate returns the YYYY-MM-DD date of an instant in RegisterLocation.
+func RegisterDate(t time.Time) string {
+	return t.In(RegisterLocation).Format("2006-01-02")
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -1,34 +1,51 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrUserHasRecords prevents deleting a user whose contract history, its
+// amendments and payslips, must be kept. The user is deactivated instead.
+var ErrUserHasRecords = errors.New("user has contract records that must be kept")
+
 type Role string
 
 const (
 	RoleAdmin    Role = "ADMIN"
+	RoleManager  Role = "MANAGER" // Approves the vacations of their direct reports
 	RoleEmployee Role = "EMPLOYEE"
 )
 
+func (r Role) Valid() bool {
+	return r == RoleAdmin || r == RoleManager || r == RoleEmployee
+}
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	CompanyID    uuid.UUID `json:"company_id"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Role         Role      `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	CompanyID    uuid.UUID  `json:"company_id"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Role         Role       `json:"role"`
+	Department   string     `json:"department,omitempty"`
+	ManagerID    *uuid.UUID `json:"manager_id,omitempty"` // Decides on the user's vacation requests
+	IBAN         string     `json:"-"`                    // Salary account, see BankAccount
+	// Access tokens carry it; bumping it revokes the ones already issued
+	TokenVersion int `json:"-"`
+	// Deactivated users cannot sign in; they are kept instead of deleted
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 func NewUser(companyID uuid.UUID, name, email, passwordHash string, role Role) (*User, error) {
 	if name == "" || email == "" || passwordHash == "" {
 		return nil, ErrInvalidInput
 	}
-	if role != RoleAdmin && role != RoleEmployee {
+	if !role.Valid() {
 		return nil, ErrInvalidInput
 	}
 	return &User{
@@ -42,3 +59,34 @@ func NewUser(companyID uuid.UUID, name, email, passwordHash string, role Role) (
 		UpdatedAt:    time.Now(),
 	}, nil
 }
+
+// RevokeTokens invalidates the access tokens issued so far, e.g. when the
+// role changes and the tokens carry the previous one.
+func (u *User) RevokeTokens() {
+	u.TokenVersion++
+}
+
+// Deactivate closes the account and revokes the tokens issued so far.
+func (u *User) Deactivate(now time.Time) {
+	if u.DeactivatedAt == nil {
+		u.DeactivatedAt = &now
+	}
+	u.RevokeTokens()
+	u.UpdatedAt = now
+}
+
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// SetPasswordHash changes the password and revokes the tokens issued with
+// the previous one.
+func (u *User) SetPasswordHash(hash string) {
+	u.PasswordHash = hash
+	u.RevokeTokens()
+}
+
+// ReportsTo reports whether managerID is the direct manager of the user.
+func (u *User) ReportsTo(managerID uuid.UUID) bool {
+	return u.ManagerID != nil && *u.ManagerID == managerID
+}
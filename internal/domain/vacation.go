@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,35 +11,155 @@ import (
 type VacationStatus string
 
 const (
-	VacationStatusPending  VacationStatus = "PENDING"
-	VacationStatusApproved VacationStatus = "APPROVED"
-	VacationStatusRejected VacationStatus = "REJECTED"
+	VacationStatusPending   VacationStatus = "PENDING"
+	VacationStatusApproved  VacationStatus = "APPROVED"
+	VacationStatusRejected  VacationStatus = "REJECTED"
+	VacationStatusCancelled VacationStatus = "CANCELLED"
 )
 
+var (
+	ErrVacationOverlap      = errors.New("vacation overlaps an existing request")
+	ErrInvalidTransition    = errors.New("invalid vacation status transition")
+	ErrVacationNotEditable  = errors.New("only pending vacations can be modified")
+	ErrSelfApproval         = errors.New("cannot decide on your own vacation")
+	ErrVacationAlreadyBegun = errors.New("approved vacation has already started")
+)
+
+// vacationTransitions lists the statuses reachable from each status.
+// Statuses not present as keys are final.
+var vacationTransitions = map[VacationStatus][]VacationStatus{
+	VacationStatusPending:  {VacationStatusApproved, VacationStatusRejected, VacationStatusCancelled},
+	VacationStatusApproved: {VacationStatusCancelled},
+}
+
+// CanTransition reports whether a vacation may move from one status to another.
+func (s VacationStatus) CanTransition(to VacationStatus) bool {
+	for _, allowed := range vacationTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether a vacation in this status holds days and dates.
+func (s VacationStatus) IsActive() bool {
+	return s == VacationStatusPending || s == VacationStatusApproved
+}
+
+// VacationOverlapError lists the vacations a request collides with.
+// It matches ErrVacationOverlap with errors.Is.
+type VacationOverlapError struct {
+	ConflictingIDs []uuid.UUID `json:"conflicting_vacation_ids"`
+}
+
+func (e *VacationOverlapError) Error() string {
+	return fmt.Sprintf("%s: %d conflicting vacation(s)", ErrVacationOverlap, len(e.ConflictingIDs))
+}
+
+func (e *VacationOverlapError) Is(target error) bool {
+	return target == ErrVacationOverlap
+}
+
+// Vacation is an absence request. Despite the name it covers every leave
+// type; LeaveTypeID is nil for plain vacations.
 type Vacation struct {
-	ID        uuid.UUID      `json:"id"`
-	UserID    uuid.UUID      `json:"user_id"`
-	StartDate time.Time      `json:"start_date"`
-	EndDate   time.Time      `json:"end_date"`
-	Status    VacationStatus `json:"status"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID              uuid.UUID      `json:"id"`
+	UserID          uuid.UUID      `json:"user_id"`
+	LeaveTypeID     *uuid.UUID     `json:"leave_type_id,omitempty"`
+	StartDate       time.Time      `json:"start_date"`
+	EndDate         time.Time      `json:"end_date"`
+	Period          DayPeriod      `json:"period"`
+	StartTime       *string        `json:"start_time,omitempty"` // HH:MM, only for HOURS
+	EndTime         *string        `json:"end_time,omitempty"`
+	Status          VacationStatus `json:"status"`
+	ApproverID      *uuid.UUID     `json:"approver_id,omitempty"` // Who took the last decision
+	DecidedAt       *time.Time     `json:"decided_at,omitempty"`
+	RejectionReason *string        `json:"rejection_reason,omitempty"`
+	AttachmentURL   *string        `json:"attachment_url,omitempty"` // Supporting document, e.g. a sick note
+	// Admin who accepted the request despite blackout or staffing constraints
+	ConstraintsOverriddenBy *uuid.UUID `json:"constraints_overridden_by,omitempty"`
+	ConstraintsOverriddenAt *time.Time `json:"constraints_overridden_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
 }
 
 func NewVacation(userID uuid.UUID, startDate, endDate time.Time) (*Vacation, error) {
+	return NewAbsence(userID, VacationLeaveType, startDate, endDate, nil)
+}
+
+// NewAbsence creates an absence of the given leave type. Types that do not
+// require approval are approved straight away.
+func NewAbsence(userID uuid.UUID, leaveType *LeaveType, startDate, endDate time.Time, attachmentURL *string) (*Vacation, error) {
 	if startDate.After(endDate) {
-		return nil, errors.New("start date cannot be after end date")
+		return nil, &ValidationError{Fields: []FieldError{{Field: "end_date", Message: "cannot be before the start date"}}}
+	}
+	if leaveType.RequiresAttachment && (attachmentURL == nil || *attachmentURL == "") {
+		return nil, ErrAttachmentRequired
 	}
 	// Basic validation: ensure dates are not in the past?
 	// Maybe let the service handle complex rules.
 
-	return &Vacation{
-		ID:        uuid.New(),
-		UserID:    userID,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Status:    VacationStatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}, nil
+	now := time.Now()
+	v := &Vacation{
+		ID:            uuid.New(),
+		UserID:        userID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		Period:        PeriodFullDay,
+		Status:        VacationStatusPending,
+		AttachmentURL: attachmentURL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if leaveType.ID != uuid.Nil {
+		id := leaveType.ID
+		v.LeaveTypeID = &id
+	}
+	if !leaveType.RequiresApproval {
+		v.Status = VacationStatusApproved
+		v.DecidedAt = &now
+	}
+	return v, nil
+}
+
+// Approve moves a pending vacation to APPROVED on behalf of approverID.
+func (v *Vacation) Approve(approverID uuid.UUID, now time.Time) error {
+	if approverID == v.UserID {
+		return ErrSelfApproval
+	}
+	return v.transition(VacationStatusApproved, approverID, nil, now)
+}
+
+// Reject moves a pending vacation to REJECTED, optionally recording why.
+func (v *Vacation) Reject(approverID uuid.UUID, reason string, now time.Time) error {
+	if approverID == v.UserID {
+		return ErrSelfApproval
+	}
+	var r *string
+	if reason != "" {
+		r = &reason
+	}
+	return v.transition(VacationStatusRejected, approverID, r, now)
+}
+
+// Cancel withdraws a vacation. Approved vacations can only be cancelled
+// before their first day.
+func (v *Vacation) Cancel(actorID uuid.UUID, now time.Time) error {
+	if v.Status == VacationStatusApproved && !now.Before(v.StartDate) {
+		return ErrVacationAlreadyBegun
+	}
+	return v.transition(VacationStatusCancelled, actorID, nil, now)
+}
+
+func (v *Vacation) transition(to VacationStatus, actorID uuid.UUID, reason *string, now time.Time) error {
+	if !v.Status.CanTransition(to) {
+		return ErrInvalidTransition
+	}
+	v.Status = to
+	v.ApproverID = &actorID
+	v.DecidedAt = &now
+	v.RejectionReason = reason
+	v.UpdatedAt = now
+	return nil
 }
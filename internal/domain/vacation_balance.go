@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DayUnit describes how vacation days are counted for a contract.
+type DayUnit string
+
+const (
+	DayUnitWorking DayUnit = "WORKING_DAYS" // Monday to Friday
+	DayUnitNatural DayUnit = "NATURAL_DAYS" // Every calendar day
+)
+
+var ErrInsufficientBalance = errors.New("insufficient vacation balance")
+
+// InsufficientBalanceError is returned when a request exceeds the remaining
+// balance for a given year. It matches ErrInsufficientBalance with errors.Is.
+type InsufficientBalanceError struct {
+	Year      int     `json:"year"`
+	Requested float64 `json:"requested"`
+	Remaining float64 `json:"remaining"`
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient vacation balance for %d: requested %.2f, remaining %.2f", e.Year, e.Requested, e.Remaining)
+}
+
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
+
+// VacationEntitlement returns the yearly vacation entitlement for a full year
+// worked under the given contract type.
+// Fixed-discontinuous contracts accrue natural days (art. 38 ET), the rest use
+// the 22 working days most collective agreements translate those 30 days into.
+func VacationEntitlement(contractType ContractType) (float64, DayUnit) {
+	if contractType == ContractTypeFixedDiscontinuous {
+		return 30, DayUnitNatural
+	}
+	return 22, DayUnitWorking
+}
+
+type VacationBalance struct {
+	UserID             uuid.UUID  `json:"user_id"`
+	Year               int        `json:"year"`
+	Unit               DayUnit    `json:"unit"`
+	Entitled           float64    `json:"entitled"`
+	CarriedOver        float64    `json:"carried_over"`         // From the previous year
+	CarryOverExpired   float64    `json:"carry_over_expired"`   // Carried days not used before the expiry date
+	CarryOverUsed      float64    `json:"carry_over_used"`      // Carried days covering approved vacation days up to the expiry date
+	CarryOverAvailable float64    `json:"carry_over_available"` // Carried days left, only for vacation days up to the expiry date
+	CarryOverExpiresAt *time.Time `json:"carry_over_expires_at,omitempty"`
+	Approved           float64    `json:"approved"`
+	Pending            float64    `json:"pending"`
+	Remaining          float64    `json:"remaining"`
+}
+
+// NewVacationBalance pro-rates the entitlement of every contract overlapping
+// the given year. The unit is taken from the latest terms in the year.
+func NewVacationBalance(userID uuid.UUID, year int, contracts []*Contract) *VacationBalance {
+	balance := &VacationBalance{
+		UserID: userID,
+		Year:   year,
+		Unit:   DayUnitWorking,
+	}
+
+	yearStart, yearEnd := YearBounds(year)
+	daysInYear := float64(yearEnd.Sub(yearStart).Hours()/24) + 1
+
+	var latest *Contract
+	for _, contract := range contracts {
+		// An amendment may change the type, and with it the entitlement
+		for _, c := range contract.periods() {
+			start, end, ok := c.overlap(yearStart, yearEnd)
+			if !ok {
+				continue
+			}
+			days, _ := VacationEntitlement(c.Type)
+			worked := float64(end.Sub(start).Hours()/24) + 1
+			balance.Entitled += days * worked / daysInYear
+
+			if latest == nil || c.StartDate.After(latest.StartDate) {
+				latest = c
+			}
+		}
+	}
+	if latest != nil {
+		_, balance.Unit = VacationEntitlement(latest.Type)
+	}
+
+	balance.Entitled = roundDays(balance.Entitled)
+	balance.Remaining = balance.Entitled
+	return balance
+}
+
+// Consume registers the days of a vacation against the balance.
+// Rejected vacations do not consume days.
+func (b *VacationBalance) Consume(status VacationStatus, days float64) {
+	switch status {
+	case VacationStatusApproved:
+		b.Approved = roundDays(b.Approved + days)
+	case VacationStatusPending:
+		b.Pending = roundDays(b.Pending + days)
+	default:
+		return
+	}
+	b.recompute()
+}
+
+// ApplyCarryOver adds the days carried from the previous year. They only
+// cover the approved vacation days up to the expiry date, usedBeforeExpiry;
+// once the carry-over has expired, the rest is lost.
+func (b *VacationBalance) ApplyCarryOver(c *VacationCarryOver, usedBeforeExpiry float64, now time.Time) {
+	b.CarriedOver = c.CarriedDays
+	b.CarryOverUsed = roundDays(math.Min(c.CarriedDays, usedBeforeExpiry))
+	expiresAt := c.ExpiresAt
+	b.CarryOverExpiresAt = &expiresAt
+
+	switch {
+	case c.Status == CarryOverStatusExpired:
+		b.CarryOverExpired = c.ExpiredDays
+	case c.IsExpired(now):
+		b.CarryOverExpired = c.UnusedDays(usedBeforeExpiry)
+	}
+	b.recompute()
+}
+
+func (b *VacationBalance) recompute() {
+	b.Remaining = roundDays(b.Entitled + b.CarriedOver - b.CarryOverExpired - b.Approved - b.Pending)
+	b.CarryOverAvailable = roundDays(math.Max(0, math.Min(b.Remaining, b.CarriedOver-b.CarryOverUsed-b.CarryOverExpired)))
+}
+
+// EntitledRemaining returns the days left for vacation days after the
+// carry-over expiry date, which carried days cannot cover.
+func (b *VacationBalance) EntitledRemaining() float64 {
+	return roundDays(b.Remaining - b.CarryOverAvailable)
+}
+
+// CountDays returns how many days between start and end (both inclusive)
+// count towards the balance for the given unit. Working days skip weekends
+// and holidays; natural days count every day.
+func CountDays(start, end time.Time, unit DayUnit, holidays HolidaySet) float64 {
+	if start.After(end) {
+		return 0
+	}
+	var days float64
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if unit == DayUnitWorking && (isWeekend(d) || holidays.Contains(d)) {
+			continue
+		}
+		days++
+	}
+	return days
+}
+
+// YearBounds returns the first and last day of the given year.
+func YearBounds(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// ClipToYear restricts the [start, end] range to the given year.
+func ClipToYear(start, end time.Time, year int) (time.Time, time.Time, bool) {
+	yearStart, yearEnd := YearBounds(year)
+	if start.Before(yearStart) {
+		start = yearStart
+	}
+	if end.After(yearEnd) {
+		end = yearEnd
+	}
+	return start, end, !start.After(end)
+}
+
+func (c *Contract) overlap(from, to time.Time) (time.Time, time.Time, bool) {
+	end := to
+	if c.EndDate != nil && c.EndDate.Before(to) {
+		end = *c.EndDate
+	}
+	start := from
+	if c.StartDate.After(from) {
+		start = c.StartDate
+	}
+	return start, end, !start.After(end)
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
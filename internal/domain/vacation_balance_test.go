@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewVacationBalance(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	contract := func(contractType ContractType, start time.Time, end *time.Time) *Contract {
+		return &Contract{ID: uuid.New(), Type: contractType, StartDate: start, EndDate: end}
+	}
+	june30 := day(time.June, 30)
+	lastYear := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	// 2024 is a leap year: 366 days
+	tests := []struct {
+		name      string
+		contracts []*Contract
+		entitled  float64
+		unit      DayUnit
+	}{
+		{"no contract", nil, 0, DayUnitWorking},
+		{"whole year", []*Contract{contract(ContractTypeIndefinite, time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC), nil)}, 22, DayUnitWorking},
+		{"from July", []*Contract{contract(ContractTypeIndefinite, day(time.July, 1), nil)}, 11.06, DayUnitWorking}, // 22 x 184 / 366
+		{"until June", []*Contract{contract(ContractTypeTemporary, day(time.January, 1), &june30)}, 10.94, DayUnitWorking},
+		{"fixed-discontinuous", []*Contract{contract(ContractTypeFixedDiscontinuous, day(time.January, 1), nil)}, 30, DayUnitNatural},
+		{"ended last year", []*Contract{contract(ContractTypeTemporary, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), &lastYear)}, 0, DayUnitWorking},
+		{
+			// 10.94 working days then 15.08 natural days; the latest terms set the unit
+			name: "change of contract type",
+			contracts: []*Contract{
+				contract(ContractTypeTemporary, day(time.January, 1), &june30),
+				contract(ContractTypeFixedDiscontinuous, day(time.July, 1), nil),
+			},
+			entitled: 26.02, unit: DayUnitNatural,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewVacationBalance(uuid.New(), 2024, tt.contracts)
+			if b.Entitled != tt.entitled || b.Remaining != tt.entitled {
+				t.Errorf("entitled = %.2f, remaining = %.2f, want %.2f", b.Entitled, b.Remaining, tt.entitled)
+			}
+			if b.Unit != tt.unit {
+				t.Errorf("unit = %s, want %s", b.Unit, tt.unit)
+			}
+		})
+	}
+}
+
+func TestVacationBalanceConsume(t *testing.T) {
+	b := &VacationBalance{Entitled: 22, Remaining: 22}
+	b.Consume(VacationStatusApproved, 5)
+	b.Consume(VacationStatusPending, 2.5)
+	b.Consume(VacationStatusRejected, 3)
+	b.Consume(VacationStatusCancelled, 1)
+
+	if b.Approved != 5 || b.Pending != 2.5 || b.Remaining != 14.5 {
+		t.Errorf("approved = %.2f, pending = %.2f, remaining = %.2f; want 5, 2.5 and 14.5", b.Approved, b.Pending, b.Remaining)
+	}
+}
+
+func TestCountDays(t *testing.T) {
+	// Monday 6 May to Sunday 12 May 2024, with a holiday on Wednesday
+	monday := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+	sunday := monday.AddDate(0, 0, 6)
+	holidays := NewHolidaySet([]*Holiday{{Date: monday.AddDate(0, 0, 2)}})
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		unit       DayUnit
+		holidays   HolidaySet
+		want       float64
+	}{
+		{"working week", monday, sunday, DayUnitWorking, nil, 5},
+		{"working week with a holiday", monday, sunday, DayUnitWorking, holidays, 4},
+		{"natural week with a holiday", monday, sunday, DayUnitNatural, holidays, 7},
+		{"weekend", sunday.AddDate(0, 0, -1), sunday, DayUnitWorking, nil, 0},
+		{"single day", monday, monday, DayUnitWorking, nil, 1},
+		{"start after end", sunday, monday, DayUnitNatural, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountDays(tt.start, tt.end, tt.unit, tt.holidays); got != tt.want {
+				t.Errorf("CountDays = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsufficientBalanceError(t *testing.T) {
+	var err error = &InsufficientBalanceError{Year: 2024, Requested: 5, Remaining: 3}
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Error("InsufficientBalanceError does not match ErrInsufficientBalance")
+	}
+}
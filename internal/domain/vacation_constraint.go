@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrConstraintViolation = errors.New("vacation violates company constraints")
+
+// BlackoutPeriod is a window in which vacations are not allowed, e.g. the
+// end-of-quarter close. An empty Department applies to the whole company.
+type BlackoutPeriod struct {
+	ID         uuid.UUID `json:"id"`
+	CompanyID  uuid.UUID `json:"company_id"`
+	Department string    `json:"department,omitempty"`
+	Name       string    `json:"name"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewBlackoutPeriod(companyID uuid.UUID, department, name string, startDate, endDate time.Time) (*BlackoutPeriod, error) {
+	if name == "" || startDate.After(endDate) {
+		return nil, ErrInvalidInput
+	}
+	return &BlackoutPeriod{
+		ID:         uuid.New(),
+		CompanyID:  companyID,
+		Department: department,
+		Name:       name,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// AppliesTo reports whether the period covers employees of the department.
+func (b *BlackoutPeriod) AppliesTo(department string) bool {
+	return b.Department == "" || b.Department == department
+}
+
+// StaffingRule requires at least MinPresent employees to remain at work on
+// every working day. An empty Department counts the whole company.
+type StaffingRule struct {
+	ID         uuid.UUID `json:"id"`
+	CompanyID  uuid.UUID `json:"company_id"`
+	Department string    `json:"department,omitempty"`
+	MinPresent int       `json:"min_present"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewStaffingRule(companyID uuid.UUID, department string, minPresent int) (*StaffingRule, error) {
+	if minPresent < 1 {
+		return nil, ErrInvalidInput
+	}
+	return &StaffingRule{
+		ID:         uuid.New(),
+		CompanyID:  companyID,
+		Department: department,
+		MinPresent: minPresent,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// AppliesTo reports whether employees of the department count for the rule.
+func (r *StaffingRule) AppliesTo(department string) bool {
+	return r.Department == "" || r.Department == department
+}
+
+// Shortfall returns the working days of the vacation on which fewer than
+// MinPresent members would remain at work if it were granted. absences are
+// the approved absences of the company; partial days count as absent.
+func (r *StaffingRule) Shortfall(vacation *Vacation, members []*User, absences []*Vacation, holidays HolidaySet) []time.Time {
+	inScope := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		inScope[m.ID] = true
+	}
+
+	var days []time.Time
+	for d := vacation.StartDate; !d.After(vacation.EndDate); d = d.AddDate(0, 0, 1) {
+		if isWeekend(d) || holidays.Contains(d) {
+			continue
+		}
+		absent := map[uuid.UUID]bool{vacation.UserID: true}
+		for _, a := range absences {
+			if inScope[a.UserID] && !d.Before(a.StartDate) && !d.After(a.EndDate) {
+				absent[a.UserID] = true
+			}
+		}
+		if len(members)-len(absent) < r.MinPresent {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+type ConstraintType string
+
+const (
+	ConstraintBlackout    ConstraintType = "BLACKOUT"
+	ConstraintMinStaffing ConstraintType = "MIN_STAFFING"
+)
+
+// ConstraintViolation describes one rule a vacation breaks. Name is set for
+// blackout periods, MinPresent and Dates for staffing rules.
+type ConstraintViolation struct {
+	Type       ConstraintType `json:"type"`
+	RuleID     uuid.UUID      `json:"rule_id"`
+	Department string         `json:"department,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	MinPresent int            `json:"min_present,omitempty"`
+	Dates      []time.Time    `json:"dates,omitempty"`
+}
+
+// ConstraintViolationError lists the constraints a vacation breaks.
+// It matches ErrConstraintViolation with errors.Is.
+type ConstraintViolationError struct {
+	Violations []ConstraintViolation `json:"violations"`
+}
+
+func (e *ConstraintViolationError) Error() string {
+	return fmt.Sprintf("%s: %d violation(s)", ErrConstraintViolation, len(e.Violations))
+}
+
+func (e *ConstraintViolationError) Is(target error) bool {
+	return target == ErrConstraintViolation
+}
+
+// OverrideConstraints records that an admin accepted the vacation despite
+// the constraints it breaks.
+func (v *Vacation) OverrideConstraints(adminID uuid.UUID, now time.Time) {
+	v.ConstraintsOverriddenBy = &adminID
+	v.ConstraintsOverriddenAt = &now
+}
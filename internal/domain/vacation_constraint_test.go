@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStaffingRuleShortfall(t *testing.T) {
+	// Monday 3 to Friday 7 March 2025
+	day := func(d int) time.Time {
+		return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	members := []*User{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	requester, colleague := members[0], members[1]
+	absence := func(user *User, start, end int) *Vacation {
+		return &Vacation{ID: uuid.New(), UserID: user.ID, StartDate: day(start), EndDate: day(end)}
+	}
+
+	tests := []struct {
+		name       string
+		minPresent int
+		vacation   *Vacation
+		absences   []*Vacation
+		holidays   HolidaySet
+		want       []time.Time
+	}{
+		{"nobody else off", 3, absence(requester, 3, 7), nil, nil, nil},
+		{"colleague off two days", 3, absence(requester, 3, 7), []*Vacation{absence(colleague, 4, 5)}, nil, []time.Time{day(4), day(5)}},
+		{"holiday", 3, absence(requester, 3, 7), []*Vacation{absence(colleague, 4, 5)}, NewHolidaySet([]*Holiday{{Date: day(5)}}), []time.Time{day(4)}},
+		{"weekend", 3, absence(requester, 7, 9), []*Vacation{absence(colleague, 3, 10)}, nil, []time.Time{day(7)}},
+		{"outsider off", 3, absence(requester, 3, 7), []*Vacation{absence(&User{ID: uuid.New()}, 3, 7)}, nil, nil},
+		// The requester's own absences are not counted twice
+		{"requester already off", 3, absence(requester, 3, 7), []*Vacation{absence(requester, 3, 3)}, nil, nil},
+		{"rule above the team size", 4, absence(requester, 3, 3), nil, nil, []time.Time{day(3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &StaffingRule{MinPresent: tt.minPresent}
+			got := rule.Shortfall(tt.vacation, members, tt.absences, tt.holidays)
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("Shortfall = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintsApplyTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		department string
+		want       bool
+	}{
+		{"whole company", "", "Ventas", true},
+		{"whole company, no department", "", "", true},
+		{"same department", "Ventas", "Ventas", true},
+		{"other department", "Ventas", "Soporte", false},
+		{"employee without department", "Ventas", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&BlackoutPeriod{Department: tt.rule}).AppliesTo(tt.department); got != tt.want {
+				t.Errorf("BlackoutPeriod.AppliesTo = %v, want %v", got, tt.want)
+			}
+			if got := (&StaffingRule{Department: tt.rule}).AppliesTo(tt.department); got != tt.want {
+				t.Errorf("StaffingRule.AppliesTo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConstraintsValidation(t *testing.T) {
+	start := time.Date(2025, time.March, 24, 0, 0, 0, 0, time.UTC)
+	if _, err := NewBlackoutPeriod(uuid.New(), "", "Cierre", start, start.AddDate(0, 0, -1)); err != ErrInvalidInput {
+		t.Errorf("NewBlackoutPeriod ending before it starts err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := NewBlackoutPeriod(uuid.New(), "", "", start, start); err != ErrInvalidInput {
+		t.Errorf("NewBlackoutPeriod without a name err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := NewStaffingRule(uuid.New(), "Ventas", 0); err != ErrInvalidInput {
+		t.Errorf("NewStaffingRule with no one present err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVacationStatusCanTransition(t *testing.T) {
+	statuses := []VacationStatus{VacationStatusPending, VacationStatusApproved, VacationStatusRejected, VacationStatusCancelled}
+	allowed := map[[2]VacationStatus]bool{
+		{VacationStatusPending, VacationStatusApproved}:   true,
+		{VacationStatusPending, VacationStatusRejected}:   true,
+		{VacationStatusPending, VacationStatusCancelled}:  true,
+		{VacationStatusApproved, VacationStatusCancelled}: true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got, want := from.CanTransition(to), allowed[[2]VacationStatus{from, to}]; got != want {
+				t.Errorf("%s -> %s = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestVacationDecisions(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	owner, approver := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name   string
+		status VacationStatus
+		start  time.Time
+		decide func(*Vacation) error
+		want   error
+		after  VacationStatus
+	}{
+		{"approve", VacationStatusPending, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Approve(approver, now) }, nil, VacationStatusApproved},
+		{"approve own", VacationStatusPending, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Approve(owner, now) }, ErrSelfApproval, VacationStatusPending},
+		{"approve twice", VacationStatusApproved, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Approve(approver, now) }, ErrInvalidTransition, VacationStatusApproved},
+		{"reject", VacationStatusPending, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Reject(approver, "busy", now) }, nil, VacationStatusRejected},
+		{"reject own", VacationStatusPending, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Reject(owner, "", now) }, ErrSelfApproval, VacationStatusPending},
+		{"reject approved", VacationStatusApproved, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Reject(approver, "", now) }, ErrInvalidTransition, VacationStatusApproved},
+		{"cancel pending", VacationStatusPending, now.AddDate(0, 0, -1), func(v *Vacation) error { return v.Cancel(owner, now) }, nil, VacationStatusCancelled},
+		{"cancel approved before it starts", VacationStatusApproved, now.AddDate(0, 0, 1), func(v *Vacation) error { return v.Cancel(owner, now) }, nil, VacationStatusCancelled},
+		{"cancel approved once started", VacationStatusApproved, now.Truncate(24 * time.Hour), func(v *Vacation) error { return v.Cancel(owner, now) }, ErrVacationAlreadyBegun, VacationStatusApproved},
+		{"cancel rejected", VacationStatusRejected, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Cancel(owner, now) }, ErrInvalidTransition, VacationStatusRejected},
+		{"approve cancelled", VacationStatusCancelled, now.AddDate(0, 1, 0), func(v *Vacation) error { return v.Approve(approver, now) }, ErrInvalidTransition, VacationStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Vacation{ID: uuid.New(), UserID: owner, StartDate: tt.start, EndDate: tt.start, Status: tt.status}
+			if err := tt.decide(v); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if v.Status != tt.after {
+				t.Errorf("status = %s, want %s", v.Status, tt.after)
+			}
+			if tt.want == nil && (v.DecidedAt == nil || !v.DecidedAt.Equal(now)) {
+				t.Errorf("decided at = %v, want %v", v.DecidedAt, now)
+			}
+		})
+	}
+}
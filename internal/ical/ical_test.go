@@ -0,0 +1,154 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		event string
+		want  Event
+	}{
+		{
+			"all-day event",
+			"UID:1\r\nSUMMARY:Año Nuevo\r\nDTSTART;VALUE=DATE:20250101\r\nDTEND;VALUE=DATE:20250102",
+			Event{UID: "1", Summary: "Año Nuevo", Start: day(time.January, 1), End: day(time.January, 2), AllDay: true},
+		},
+		{
+			// Without DTEND an all-day event lasts one day
+			"date without end",
+			"SUMMARY:Reyes\r\nDTSTART:20250106",
+			Event{Summary: "Reyes", Start: day(time.January, 6), End: day(time.January, 7), AllDay: true},
+		},
+		{
+			"folded and escaped summary",
+			"SUMMARY:Fiesta del Trabajo\\, \r\n festivo nacional\r\nDTSTART;VALUE=DATE:20250501",
+			Event{Summary: "Fiesta del Trabajo, festivo nacional", Start: day(time.May, 1), End: day(time.May, 2), AllDay: true},
+		},
+		{
+			"UTC date-time",
+			"SUMMARY:Reunión\r\nDTSTART:20250310T080000Z\r\nDTEND:20250310T090000Z",
+			Event{Summary: "Reunión", Start: time.Date(2025, time.March, 10, 8, 0, 0, 0, time.UTC), End: time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			"local date-time",
+			"SUMMARY:Reunión\r\nDTSTART;TZID=Europe/Madrid:20250310T090000",
+			Event{Summary: "Reunión", Start: time.Date(2025, time.March, 10, 9, 0, 0, 0, madrid), End: time.Date(2025, time.March, 10, 9, 0, 0, 0, madrid)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + tt.event + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+			events, err := Parse(strings.NewReader(data))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			got := events[0]
+			if got.UID != tt.want.UID || got.Summary != tt.want.Summary || got.AllDay != tt.want.AllDay ||
+				!got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("event = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+	}{
+		{"no start", "SUMMARY:Sin fecha"},
+		{"bad date", "DTSTART;VALUE=DATE:20251340"},
+		{"bad date-time", "DTSTART:2025-03-10T08:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + tt.event + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+			if _, err := Parse(strings.NewReader(data)); !errors.Is(err, ErrInvalidCalendar) {
+				t.Errorf("Parse err = %v, want ErrInvalidCalendar", err)
+			}
+		})
+	}
+}
+
+func TestEventDays(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, time.December, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       int
+	}{
+		{"one day", day(25), day(26), 1},
+		{"end is exclusive", day(24), day(27), 3},
+		{"no end", day(25), day(25), 1},
+		{"timed event", day(25).Add(9 * time.Hour), day(25).Add(17 * time.Hour), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := Event{Start: tt.start, End: tt.end}.Days()
+			if len(days) != tt.want {
+				t.Fatalf("Days = %v, want %d days", days, tt.want)
+			}
+			if !days[0].Equal(day(tt.start.Day())) {
+				t.Errorf("first day = %s, want %s", days[0], day(tt.start.Day()))
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	long := strings.Repeat("Vacaciones de verano en la costa ", 4) + "ñ"
+	events := []Event{
+		{UID: "1@myteam", Summary: "Ana - Vacaciones (mañana)", Start: time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), End: time.Date(2025, time.August, 5, 0, 0, 0, 0, time.UTC), AllDay: true},
+		{UID: "2@myteam", Summary: "Luis; Ausencia, médico", Start: time.Date(2025, time.August, 4, 7, 0, 0, 0, time.UTC), End: time.Date(2025, time.August, 4, 9, 0, 0, 0, time.UTC)},
+		{UID: "3@myteam", Summary: long, Start: time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC), End: time.Date(2025, time.August, 16, 0, 0, 0, 0, time.UTC), AllDay: true},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, "Ausencias del equipo", events); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := b.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+	for _, want := range []string{"DTSTART;VALUE=DATE:20250804\r\n", "DTEND;VALUE=DATE:20250805\r\n", "DTSTART:20250804T070000Z\r\n", `SUMMARY:Luis\; Ausencia\, médico`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+
+	parsed, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(parsed) != len(events) {
+		t.Fatalf("parsed %d events, want %d", len(parsed), len(events))
+	}
+	for i, e := range events {
+		got := parsed[i]
+		if got.UID != e.UID || got.Summary != e.Summary || got.AllDay != e.AllDay || !got.Start.Equal(e.Start) || !got.End.Equal(e.End) {
+			t.Errorf("event %d = %+v, want %+v", i, got, e)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package port
+
+import "context"
+
+// Mail is a plain-text email.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailSender delivers emails, e.g. through SMTP.
+type MailSender interface {
+	Send(ctx context.Context, mail Mail) error
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/fuenr/myteam/internal/domain"
 	"github.com/google/uuid"
@@ -21,26 +22,179 @@ type UserRepository interface {
 	BatchCreateUsers(ctx context.Context, users []*domain.User) error
 	GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	// GetUsersByCompanyID and CountUsers leave out deactivated users.
 	GetUsersByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.User) error
+	// DeleteUser returns domain.ErrUserHasRecords when the history of the
+	// user's contracts must be kept.
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	CountUsers(ctx context.Context) (int64, error)
 }
 
 type ContractRepository interface {
 	CreateContract(ctx context.Context, contract *domain.Contract) error
+	// GetContractByID and GetContractsByUserID return the contracts with their amendments and termination.
 	GetContractByID(ctx context.Context, id uuid.UUID) (*domain.Contract, error)
 	GetContractsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contract, error)
-	UpdateContract(ctx context.Context, contract *domain.Contract) error
+	// GetUnendedContracts returns the UPCOMING and ACTIVE contracts of every company with their amendments.
+	GetUnendedContracts(ctx context.Context) ([]*domain.Contract, error)
+	UpdateContractStatus(ctx context.Context, contract *domain.Contract) error
+	// CreateContractAmendment stores a change of terms; contracts themselves are never updated.
+	CreateContractAmendment(ctx context.Context, amendment *domain.ContractAmendment) error
+	// CreateContractTermination stores contract.Termination and the status of the contract.
+	// It returns domain.ErrContractTerminated if the contract was already terminated.
+	CreateContractTermination(ctx context.Context, contract *domain.Contract) error
 	DeleteContract(ctx context.Context, id uuid.UUID) error
 	CountContracts(ctx context.Context) (int64, error)
+	// SumSalaries adds up the salaries in force today, amendments included.
 	SumSalaries(ctx context.Context) (float64, error)
 }
 
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	// UseRefreshToken marks the token as used and returns false if it already was.
+	UseRefreshToken(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, revokedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error
+}
+
+type AccountTokenRepository interface {
+	// CreateAccountToken stores the token and deletes the unused ones of the same user and purpose.
+	CreateAccountToken(ctx context.Context, token *domain.AccountToken) error
+	GetAccountTokenByHash(ctx context.Context, hash string) (*domain.AccountToken, error)
+	// UseAccountToken marks the token as used and, in the same transaction,
+	// saves the password and token version of its user and revokes their
+	// refresh tokens. It returns false, saving nothing, if the token already was used.
+	UseAccountToken(ctx context.Context, id uuid.UUID, user *domain.User, usedAt time.Time) (bool, error)
+}
+
+type NotificationRepository interface {
+	// CreateNotifications skips the notifications whose key was already sent
+	// to the same user and returns how many were stored.
+	CreateNotifications(ctx context.Context, notifications []*domain.Notification) (int, error)
+	GetNotificationByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
+	// GetNotificationsByUserID returns the notifications of a user, newest first.
+	GetNotificationsByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*domain.Notification, error)
+	UpdateNotification(ctx context.Context, notification *domain.Notification) error
+}
+
+type PayslipRepository interface {
+	// CreatePayslip stores a payslip with its lines and rendered document.
+	// It returns domain.ErrDuplicate if the contract already has one for the period.
+	CreatePayslip(ctx context.Context, payslip *domain.Payslip, document []byte) error
+	// GetPayslipByID returns the payslip with its lines.
+	GetPayslipByID(ctx context.Context, id uuid.UUID) (*domain.Payslip, error)
+	// GetPayslipsByUserID returns the payslips of a user without their lines, newest first.
+	GetPayslipsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Payslip, error)
+	// GetPayslipsByCompanyID returns the payslips issued by a company for a YYYY-MM period, without their lines.
+	GetPayslipsByCompanyID(ctx context.Context, companyID uuid.UUID, period string) ([]*domain.Payslip, error)
+	GetPayslipDocument(ctx context.Context, id uuid.UUID) ([]byte, error)
+}
+
+type TimeEntryRepository interface {
+	// CreateTimeEntry returns domain.ErrAlreadyClockedIn if the user has an open entry.
+	CreateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error
+	GetTimeEntryByID(ctx context.Context, id uuid.UUID) (*domain.TimeEntry, error)
+	// GetOpenTimeEntry returns domain.ErrNotFound when the user is not clocked in.
+	GetOpenTimeEntry(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error)
+	// GetTimeEntriesByUserID returns the entries clocked in within [from, to), oldest first.
+	GetTimeEntriesByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.TimeEntry, error)
+	// GetUserIDsWithTimeEntries returns the users who clocked in within [from, to).
+	GetUserIDsWithTimeEntries(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// UpdateTimeEntry saves the clock times and replaces the breaks of the entry.
+	UpdateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error
+	// EditTimeEntry saves an admin correction together with its audit record.
+	EditTimeEntry(ctx context.Context, entry *domain.TimeEntry, edit *domain.TimeEntryEdit) error
+	GetTimeEntryEdits(ctx context.Context, entryID uuid.UUID) ([]*domain.TimeEntryEdit, error)
+	// SaveTimeRegisterAcknowledgement returns domain.ErrDuplicate if the month was already signed.
+	SaveTimeRegisterAcknowledgement(ctx context.Context, ack *domain.TimeRegisterAcknowledgement) error
+	// GetTimeRegisterAcknowledgement returns domain.ErrNotFound while the month is unsigned.
+	GetTimeRegisterAcknowledgement(ctx context.Context, userID uuid.UUID, month string) (*domain.TimeRegisterAcknowledgement, error)
+}
+
 type VacationRepository interface {
 	CreateVacation(ctx context.Context, vacation *domain.Vacation) error
 	GetVacationByID(ctx context.Context, id uuid.UUID) (*domain.Vacation, error)
 	GetVacationsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Vacation, error)
+	// GetOverlappingVacations returns the pending or approved vacations of a user that
+	// intersect [start, end], ignoring the vacation with excludeID.
+	GetOverlappingVacations(ctx context.Context, userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*domain.Vacation, error)
+	// GetAbsencesByCompanyID returns the pending or approved vacations of the
+	// company users that intersect [from, to].
+	GetAbsencesByCompanyID(ctx context.Context, companyID uuid.UUID, from, to time.Time) ([]*domain.TeamAbsence, error)
 	UpdateVacation(ctx context.Context, vacation *domain.Vacation) error
 	DeleteVacation(ctx context.Context, id uuid.UUID) error
 }
+
+type HolidayRepository interface {
+	CreateCalendar(ctx context.Context, calendar *domain.HolidayCalendar) error
+	GetCalendarByID(ctx context.Context, id uuid.UUID) (*domain.HolidayCalendar, error)
+	GetCalendarsByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.HolidayCalendar, error)
+	UpdateCalendar(ctx context.Context, calendar *domain.HolidayCalendar) error
+	DeleteCalendar(ctx context.Context, id uuid.UUID) error
+	// SaveHolidays inserts holidays, replacing any existing one on the same date of the same calendar.
+	SaveHolidays(ctx context.Context, holidays []*domain.Holiday) error
+	GetHolidays(ctx context.Context, calendarID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error)
+	DeleteHoliday(ctx context.Context, calendarID, id uuid.UUID) error
+	AssignCalendarToUser(ctx context.Context, userID uuid.UUID, calendarID *uuid.UUID) error
+	// GetCalendarByUserID returns domain.ErrNotFound when the user has no calendar assigned.
+	GetCalendarByUserID(ctx context.Context, userID uuid.UUID) (*domain.HolidayCalendar, error)
+}
+
+type CalendarFeedRepository interface {
+	// SaveCalendarFeed creates the feed of a user or replaces its token.
+	SaveCalendarFeed(ctx context.Context, feed *domain.CalendarFeed) error
+	GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	DeleteCalendarFeed(ctx context.Context, userID uuid.UUID) error
+}
+
+type LeaveTypeRepository interface {
+	CreateLeaveType(ctx context.Context, leaveType *domain.LeaveType) error
+	GetLeaveTypeByID(ctx context.Context, id uuid.UUID) (*domain.LeaveType, error)
+	GetLeaveTypesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.LeaveType, error)
+	UpdateLeaveType(ctx context.Context, leaveType *domain.LeaveType) error
+	// DeleteLeaveType returns domain.ErrLeaveTypeInUse if absences still reference it.
+	DeleteLeaveType(ctx context.Context, id uuid.UUID) error
+}
+
+type VacationPolicyRepository interface {
+	// GetVacationPolicy returns domain.ErrNotFound when the company has no policy.
+	GetVacationPolicy(ctx context.Context, companyID uuid.UUID) (*domain.VacationPolicy, error)
+	SaveVacationPolicy(ctx context.Context, policy *domain.VacationPolicy) error
+	// CreateCarryOvers stores the year close of several users atomically.
+	CreateCarryOvers(ctx context.Context, carryOvers []*domain.VacationCarryOver) error
+	// GetCarryOver returns the carry-over from fromYear into the next year, or domain.ErrNotFound.
+	GetCarryOver(ctx context.Context, userID uuid.UUID, fromYear int) (*domain.VacationCarryOver, error)
+	GetCarryOversByCompanyID(ctx context.Context, companyID uuid.UUID, fromYear int) ([]*domain.VacationCarryOver, error)
+	// GetActiveCarryOversExpiringBefore returns ACTIVE carry-overs whose expiry day is before the given date.
+	GetActiveCarryOversExpiringBefore(ctx context.Context, date time.Time) ([]*domain.VacationCarryOver, error)
+	UpdateCarryOver(ctx context.Context, carryOver *domain.VacationCarryOver) error
+}
+
+type VacationConstraintRepository interface {
+	CreateBlackoutPeriod(ctx context.Context, blackout *domain.BlackoutPeriod) error
+	GetBlackoutPeriodsByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.BlackoutPeriod, error)
+	// GetOverlappingBlackoutPeriods returns the periods of the company intersecting [start, end].
+	GetOverlappingBlackoutPeriods(ctx context.Context, companyID uuid.UUID, start, end time.Time) ([]*domain.BlackoutPeriod, error)
+	DeleteBlackoutPeriod(ctx context.Context, companyID, id uuid.UUID) error
+	CreateStaffingRule(ctx context.Context, rule *domain.StaffingRule) error
+	GetStaffingRulesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.StaffingRule, error)
+	DeleteStaffingRule(ctx context.Context, companyID, id uuid.UUID) error
+}
+
+type ScheduleRepository interface {
+	// CreateScheduleTemplate returns domain.ErrDuplicate if the company already has a template with that name.
+	CreateScheduleTemplate(ctx context.Context, template *domain.ScheduleTemplate) error
+	GetScheduleTemplateByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleTemplate, error)
+	GetScheduleTemplatesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.ScheduleTemplate, error)
+	// DeleteScheduleTemplate returns domain.ErrScheduleTemplateInUse if users are assigned to it.
+	DeleteScheduleTemplate(ctx context.Context, companyID, id uuid.UUID) error
+	CreateScheduleAssignment(ctx context.Context, assignment *domain.ScheduleAssignment) error
+	GetScheduleAssignmentByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleAssignment, error)
+	GetScheduleAssignmentsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduleAssignment, error)
+	// GetScheduleAssignmentsByCompanyID returns the assignments of the company users in force within [from, to].
+	GetScheduleAssignmentsByCompanyID(ctx context.Context, companyID uuid.UUID, from, to time.Time) ([]*domain.ScheduleAssignment, error)
+	UpdateScheduleAssignment(ctx context.Context, assignment *domain.ScheduleAssignment) error
+	DeleteScheduleAssignment(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,217 @@
+// Package sepa writes SEPA credit transfer initiations (ISO 20022
+// pain.001.001.03), the XML batch banks accept to pay salaries.
+package sepa
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// Party is the holder of an account. BIC may be empty.
+type Party struct {
+	Name string
+	IBAN string
+	BIC  string
+}
+
+// Payment is a transfer to a creditor. EndToEndID identifies it up to the
+// creditor's bank statement (max. 35 characters).
+type Payment struct {
+	EndToEndID  string
+	Amount      float64 // Euros
+	Creditor    Party
+	Remittance  string
+	PurposeCode string // e.g. SALA for salaries
+}
+
+// CreditTransfer is a batch of payments from a single debtor account.
+type CreditTransfer struct {
+	MessageID     string // Unique per file (max. 35 characters)
+	CreatedAt     time.Time
+	InitiatorName string
+	InitiatorID   string // Tax ID of the initiating party
+	ExecutionDate time.Time
+	Debtor        Party
+	Payments      []Payment
+}
+
+// Marshal renders the batch as a pain.001.001.03 document.
+func (t *CreditTransfer) Marshal() ([]byte, error) {
+	var sum float64
+	txs := make([]transaction, 0, len(t.Payments))
+	for _, p := range t.Payments {
+		sum += p.Amount
+		tx := transaction{
+			EndToEndID:  text(p.EndToEndID, 35),
+			Amount:      amount{Currency: "EUR", Value: euros(p.Amount)},
+			CreditorNm:  text(p.Creditor.Name, 70),
+			CreditorAcc: account{IBAN: p.Creditor.IBAN},
+			Remittance:  text(p.Remittance, 140),
+		}
+		if p.Creditor.BIC != "" {
+			tx.CreditorAgt = &agent{BIC: p.Creditor.BIC}
+		}
+		if p.PurposeCode != "" {
+			tx.Purpose = &code{Code: p.PurposeCode}
+		}
+		txs = append(txs, tx)
+	}
+
+	debtorAgent := agent{BIC: t.Debtor.BIC}
+	if t.Debtor.BIC == "" {
+		debtorAgent = agent{Other: &other{ID: "NOTPROVIDED"}}
+	}
+	doc := document{
+		Xmlns: namespace,
+		Initiation: initiation{
+			Header: groupHeader{
+				MessageID:    text(t.MessageID, 35),
+				CreatedAt:    t.CreatedAt.Format("2006-01-02T15:04:05"),
+				Transactions: len(txs),
+				ControlSum:   euros(sum),
+				Initiator:    party{Name: text(t.InitiatorName, 70), ID: &partyID{Org: orgID{Other: other{ID: t.InitiatorID}}}},
+			},
+			Payment: paymentInfo{
+				ID:            text(t.MessageID, 35),
+				Method:        "TRF",
+				BatchBooking:  true,
+				Transactions:  len(txs),
+				ControlSum:    euros(sum),
+				TypeInfo:      paymentType{ServiceLevel: code{Code: "SEPA"}, CategoryPurpose: &code{Code: "SALA"}},
+				ExecutionDate: t.ExecutionDate.Format("2006-01-02"),
+				Debtor:        party{Name: text(t.Debtor.Name, 70)},
+				DebtorAccount: account{IBAN: t.Debtor.IBAN},
+				DebtorAgent:   debtorAgent,
+				ChargeBearer:  "SLEV",
+				Transfers:     txs,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type document struct {
+	XMLName    xml.Name   `xml:"Document"`
+	Xmlns      string     `xml:"xmlns,attr"`
+	Initiation initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type initiation struct {
+	Header  groupHeader `xml:"GrpHdr"`
+	Payment paymentInfo `xml:"PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID    string `xml:"MsgId"`
+	CreatedAt    string `xml:"CreDtTm"`
+	Transactions int    `xml:"NbOfTxs"`
+	ControlSum   string `xml:"CtrlSum"`
+	Initiator    party  `xml:"InitgPty"`
+}
+
+type paymentInfo struct {
+	ID            string        `xml:"PmtInfId"`
+	Method        string        `xml:"PmtMtd"`
+	BatchBooking  bool          `xml:"BtchBookg"`
+	Transactions  int           `xml:"NbOfTxs"`
+	ControlSum    string        `xml:"CtrlSum"`
+	TypeInfo      paymentType   `xml:"PmtTpInf"`
+	ExecutionDate string        `xml:"ReqdExctnDt"`
+	Debtor        party         `xml:"Dbtr"`
+	DebtorAccount account       `xml:"DbtrAcct"`
+	DebtorAgent   agent         `xml:"DbtrAgt"`
+	ChargeBearer  string        `xml:"ChrgBr"`
+	Transfers     []transaction `xml:"CdtTrfTxInf"`
+}
+
+type paymentType struct {
+	ServiceLevel    code  `xml:"SvcLvl"`
+	CategoryPurpose *code `xml:"CtgyPurp,omitempty"`
+}
+
+type code struct {
+	Code string `xml:"Cd"`
+}
+
+type party struct {
+	Name string   `xml:"Nm"`
+	ID   *partyID `xml:"Id,omitempty"`
+}
+
+type partyID struct {
+	Org orgID `xml:"OrgId"`
+}
+
+type orgID struct {
+	Other other `xml:"Othr"`
+}
+
+type other struct {
+	ID string `xml:"Id"`
+}
+
+type account struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type agent struct {
+	BIC   string `xml:"FinInstnId>BIC,omitempty"`
+	Other *other `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type transaction struct {
+	EndToEndID  string  `xml:"PmtId>EndToEndId"`
+	Amount      amount  `xml:"Amt>InstdAmt"`
+	CreditorAgt *agent  `xml:"CdtrAgt,omitempty"`
+	CreditorNm  string  `xml:"Cdtr>Nm"`
+	CreditorAcc account `xml:"CdtrAcct"`
+	Purpose     *code   `xml:"Purp,omitempty"`
+	Remittance  string  `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+func euros(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// accents maps the letters most often found in Spanish names to the Latin
+// characters allowed by the SEPA rulebook.
+var accents = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n", "ç", "c", "à", "a", "è", "e", "ò", "o", "ï", "i",
+	"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N", "Ç", "C", "À", "A", "È", "E", "Ò", "O", "Ï", "I",
+	"ª", "a", "º", "o",
+)
+
+// text restricts s to the SEPA character set and to max characters.
+func text(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?:().,'+ ", r):
+			return r
+		default:
+			return ' '
+		}
+	}, accents.Replace(s))
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > max {
+		s = strings.TrimSpace(s[:max])
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package sepa
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	transfer := &CreditTransfer{
+		MessageID:     "NOMINAS-2025-03",
+		CreatedAt:     time.Date(2025, time.March, 28, 10, 30, 0, 0, time.UTC),
+		InitiatorName: "Cafetería Peñalver, S.L.",
+		InitiatorID:   "B12345678",
+		ExecutionDate: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		Debtor:        Party{Name: "Cafetería Peñalver, S.L.", IBAN: "ES9121000418450200051332"},
+		Payments: []Payment{
+			{EndToEndID: "NOMINA-1", Amount: 1500.10, Creditor: Party{Name: "José Núñez", IBAN: "ES7921000813610123456789", BIC: "CAIXESBBXXX"}, Remittance: "Nómina marzo 2025", PurposeCode: "SALA"},
+			{EndToEndID: "NOMINA-2", Amount: 2000.20, Creditor: Party{Name: "Ana <García> & Cía", IBAN: "DE89370400440532013000"}, Remittance: "Nómina marzo 2025"},
+		},
+	}
+	data, err := transfer.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var doc struct {
+		Xmlns  string `xml:"xmlns,attr"`
+		Header struct {
+			Transactions int    `xml:"NbOfTxs"`
+			ControlSum   string `xml:"CtrlSum"`
+			Initiator    string `xml:"InitgPty>Nm"`
+		} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+		Payment struct {
+			ControlSum  string `xml:"CtrlSum"`
+			DebtorAgent string `xml:"DbtrAgt>FinInstnId>Othr>Id"`
+			Transfers   []struct {
+				Amount      amount `xml:"Amt>InstdAmt"`
+				CreditorBIC string `xml:"CdtrAgt>FinInstnId>BIC"`
+				Creditor    string `xml:"Cdtr>Nm"`
+				IBAN        string `xml:"CdtrAcct>Id>IBAN"`
+				Purpose     string `xml:"Purp>Cd"`
+				Remittance  string `xml:"RmtInf>Ustrd"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"CstmrCdtTrfInitn>PmtInf"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if doc.Xmlns != "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03" {
+		t.Errorf("namespace = %s", doc.Xmlns)
+	}
+	if doc.Header.Transactions != 2 || doc.Header.ControlSum != "3500.30" || doc.Payment.ControlSum != "3500.30" {
+		t.Errorf("header = %d transactions for %s, payment %s; want 2 for 3500.30", doc.Header.Transactions, doc.Header.ControlSum, doc.Payment.ControlSum)
+	}
+	if doc.Header.Initiator != "Cafeteria Penalver, S.L." {
+		t.Errorf("initiator = %q", doc.Header.Initiator)
+	}
+	// Without a debtor BIC the bank finds it from the IBAN
+	if doc.Payment.DebtorAgent != "NOTPROVIDED" {
+		t.Errorf("debtor agent = %q, want NOTPROVIDED", doc.Payment.DebtorAgent)
+	}
+
+	tests := []struct {
+		amount, bic, creditor, iban, purpose, remittance string
+	}{
+		{"1500.10", "CAIXESBBXXX", "Jose Nunez", "ES7921000813610123456789", "SALA", "Nomina marzo 2025"},
+		{"2000.20", "", "Ana Garcia Cia", "DE89370400440532013000", "", "Nomina marzo 2025"},
+	}
+	if len(doc.Payment.Transfers) != len(tests) {
+		t.Fatalf("got %d transfers, want %d", len(doc.Payment.Transfers), len(tests))
+	}
+	for i, tt := range tests {
+		got := doc.Payment.Transfers[i]
+		if got.Amount.Value != tt.amount || got.Amount.Currency != "EUR" || got.CreditorBIC != tt.bic || got.Creditor != tt.creditor ||
+			got.IBAN != tt.iban || got.Purpose != tt.purpose || got.Remittance != tt.remittance {
+			t.Errorf("transfer %d = %+v, want %+v", i, got, tt)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"Peñalver Muñoz", 70, "Penalver Munoz"},
+		{"Calle Mayor, nº 3 - 2ª", 70, "Calle Mayor, no 3 - 2a"},
+		{"Ana & Luis  <S.L.>", 70, "Ana Luis S.L."},
+		{"Nómina de marzo", 9, "Nomina de"},
+		// No trailing space once cut
+		{"Nómina de marzo", 7, "Nomina"},
+	}
+	for _, tt := range tests {
+		if got := text(tt.in, tt.max); got != tt.want {
+			t.Errorf("text(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+	if got := text(strings.Repeat("a", 40), 35); len(got) != 35 {
+		t.Errorf("text cut to %d characters, want 35", len(got))
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type AbsenceHandler struct {
+	service *service.AbsenceService
+}
+
+func NewAbsenceHandler(service *service.AbsenceService) *AbsenceHandler {
+	return &AbsenceHandler{service: service}
+}
+
+// GetCompanyAbsences lists pending and approved absences between the from and
+// to query parameters (YYYY-MM-DD). Defaults to the next 30 days.
+func (h *AbsenceHandler) GetCompanyAbsences(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("companyID"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, err := parseDateParam(r, "from", today)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r, "to", from.AddDate(0, 0, 30))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	absences, err := h.service.GetCompanyAbsences(r.Context(), companyID, from, to, claims.Role)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, absences)
+}
+
+func (h *AbsenceHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	url, err := h.service.CreateFeed(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"url": url})
+}
+
+func (h *AbsenceHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RevokeFeed(r.Context(), userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFeed serves the iCalendar document. The token in the path is the only
+// credential, since calendar clients cannot send an Authorization header.
+func (h *AbsenceHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	body, err := h.service.RenderFeed(r.Context(), r.PathValue("token"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="absences.ics"`)
+	w.Write(body)
+}
+
+func parseDateParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
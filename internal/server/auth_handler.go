@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type AuthHandler struct {
+	service *service.AuthService
+}
+
+func NewAuthHandler(service *service.AuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token.
+// Body: {"refresh_token": "..."}. The refresh token cannot be used again.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// Logout ends the session of the refresh token. Body: {"refresh_token":
+// "..."}, or {"all": true} to end every session of the caller.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+		All          bool   `json:"all"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.RefreshToken == "" && !req.All) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), claims.UserID, req.RefreshToken, req.All); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePassword sets a new password and ends every session of the user.
+// Body: {"current_password": "...", "new_password": "..."}; Admins changing
+// the password of another user do not need the current one.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword, claims.UserID); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrInvalidToken) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	writeError(w, err)
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type HolidayHandler struct {
+	service *service.HolidayService
+}
+
+func NewHolidayHandler(service *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{service: service}
+}
+
+func (h *HolidayHandler) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.service.CreateCalendar(r.Context(), companyID, req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, calendar)
+}
+
+func (h *HolidayHandler) GetCalendars(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	calendars, err := h.service.GetCalendarsByCompany(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, calendars)
+}
+
+func (h *HolidayHandler) UpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.service.UpdateCalendar(r.Context(), companyID, calendarID, req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, calendar)
+}
+
+func (h *HolidayHandler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCalendar(r.Context(), companyID, calendarID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HolidayHandler) GetHolidays(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		var err error
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	holidays, err := h.service.GetHolidays(r.Context(), companyID, calendarID, year)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, holidays)
+}
+
+func (h *HolidayHandler) AddHolidays(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req []service.HolidayInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	holidays, err := h.service.AddHolidays(r.Context(), companyID, calendarID, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, holidays)
+}
+
+// ImportICal reads a text/calendar body. The scope query parameter applies to
+// every imported holiday and defaults to NATIONAL.
+func (h *HolidayHandler) ImportICal(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+
+	scope := domain.HolidayScopeNational
+	if s := r.URL.Query().Get("scope"); s != "" {
+		scope = domain.HolidayScope(s)
+	}
+
+	holidays, err := h.service.ImportICal(r.Context(), companyID, calendarID, scope, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, holidays)
+}
+
+func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+	holidayID, err := uuid.Parse(r.PathValue("holidayID"))
+	if err != nil {
+		http.Error(w, "Invalid holiday ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteHoliday(r.Context(), companyID, calendarID, holidayID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HolidayHandler) AssignUser(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := calendarPath(w, r)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AssignUser(r.Context(), companyID, calendarID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// calendarPath parses the company and calendar IDs of /companies/{id}/calendars/{calendarID}.
+func calendarPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	calendarID, err := uuid.Parse(r.PathValue("calendarID"))
+	if err != nil {
+		http.Error(w, "Invalid calendar ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, calendarID, true
+}
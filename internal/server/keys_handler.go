@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fuenr/myteam/internal/auth"
+)
+
+type KeysHandler struct {
+	keys *auth.KeySet
+}
+
+func NewKeysHandler(keys *auth.KeySet) *KeysHandler {
+	return &KeysHandler{keys: keys}
+}
+
+// GetJWKS publishes the public keys tokens are verified with, so other
+// services can check them without sharing a secret.
+func (h *KeysHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, h.keys.JWKS())
+}
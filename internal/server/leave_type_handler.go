@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type LeaveTypeHandler struct {
+	service *service.LeaveTypeService
+}
+
+func NewLeaveTypeHandler(service *service.LeaveTypeService) *LeaveTypeHandler {
+	return &LeaveTypeHandler{service: service}
+}
+
+func (h *LeaveTypeHandler) CreateLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.LeaveTypeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	leaveType, err := h.service.Create(r.Context(), companyID, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, leaveType)
+}
+
+func (h *LeaveTypeHandler) GetLeaveTypes(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	leaveTypes, err := h.service.GetByCompany(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, leaveTypes)
+}
+
+func (h *LeaveTypeHandler) UpdateLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, leaveTypeID, ok := leaveTypePath(w, r)
+	if !ok {
+		return
+	}
+
+	var input service.LeaveTypeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	leaveType, err := h.service.Update(r.Context(), companyID, leaveTypeID, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, leaveType)
+}
+
+func (h *LeaveTypeHandler) DeleteLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, leaveTypeID, ok := leaveTypePath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), companyID, leaveTypeID); err != nil {
+		if errors.Is(err, domain.ErrLeaveTypeInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func leaveTypePath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	leaveTypeID, err := uuid.Parse(r.PathValue("leaveTypeID"))
+	if err != nil {
+		http.Error(w, "Invalid leave type ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, leaveTypeID, true
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(service *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// GetNotifications returns the notifications of the caller; ?unread=true skips the read ones.
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	notifications, err := h.service.GetByUser(r.Context(), claims.UserID, r.URL.Query().Get("unread") == "true")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	notification, err := h.service.MarkRead(r.Context(), claims.UserID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, notification)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type OvertimeHandler struct {
+	service *service.OvertimeService
+}
+
+func NewOvertimeHandler(service *service.OvertimeService) *OvertimeHandler {
+	return &OvertimeHandler{service: service}
+}
+
+// GetReport returns the weekly and monthly overtime of ?year= (defaults to the current year).
+func (h *OvertimeHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.service.GetReport(r.Context(), userID, year)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
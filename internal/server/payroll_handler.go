@@ -0,0 +1,250 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type PayrollHandler struct {
+	service *service.PayrollService
+}
+
+func NewPayrollHandler(service *service.PayrollService) *PayrollHandler {
+	return &PayrollHandler{service: service}
+}
+
+// GetSimulation estimates the net pay of a contract. Optional query
+// parameters: ?year=, ?payments=12|14 and ?group= (contribution group).
+func (h *PayrollHandler) GetSimulation(w http.ResponseWriter, r *http.Request) {
+	contractID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid contract ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.PayrollSimulationInput
+	for name, value := range map[string]*int{"year": &input.Year, "payments": &input.PaymentsPerYear, "group": &input.ContributionGroup} {
+		if s := r.URL.Query().Get(name); s != "" {
+			if *value, err = strconv.Atoi(s); err != nil {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	simulation, err := h.service.Simulate(r.Context(), contractID, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, simulation)
+}
+
+// IssuePayslips issues the payslips of a month for every employee of the
+// company. Body: {"month": "YYYY-MM", "payments": 14}.
+func (h *PayrollHandler) IssuePayslips(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Month    string `json:"month"`
+		Payments int    `json:"payments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	month, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.service.IssuePayslips(r.Context(), companyID, month, req.Payments, claims.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, run)
+}
+
+func (h *PayrollHandler) GetPayslips(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	payslips, err := h.service.GetPayslips(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, payslips)
+}
+
+func (h *PayrollHandler) GetPayslip(w http.ResponseWriter, r *http.Request) {
+	userID, payslipID, ok := payslipPath(w, r)
+	if !ok {
+		return
+	}
+
+	payslip, err := h.service.GetPayslip(r.Context(), userID, payslipID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, payslip)
+}
+
+// DownloadPayslip sends the archived PDF of a payslip.
+func (h *PayrollHandler) DownloadPayslip(w http.ResponseWriter, r *http.Request) {
+	userID, payslipID, ok := payslipPath(w, r)
+	if !ok {
+		return
+	}
+
+	payslip, document, err := h.service.GetPayslipDocument(r.Context(), userID, payslipID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeFile(w, fmt.Sprintf("nomina-%s-%s.pdf", payslip.Period, payslip.ContractID.String()[:8]), document)
+}
+
+func payslipPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	payslipID, err := uuid.Parse(r.PathValue("payslipID"))
+	if err != nil {
+		http.Error(w, "Invalid payslip ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, payslipID, true
+}
+
+func (h *PayrollHandler) GetBankAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.GetBankAccount(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, account)
+}
+
+// SetBankAccount sets the salary account of a user. Body: {"iban": "ES..."}.
+func (h *PayrollHandler) SetBankAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.BankAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.SetBankAccount(r.Context(), userID, req.IBAN)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, account)
+}
+
+func (h *PayrollHandler) GetCompanyBankAccount(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.GetCompanyBankAccount(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, account)
+}
+
+// SetCompanyBankAccount sets the account salaries are paid from.
+// Body: {"iban": "ES...", "bic": "CAIXESBBXXX"}; the BIC is optional.
+func (h *PayrollHandler) SetCompanyBankAccount(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.BankAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.SetCompanyBankAccount(r.Context(), companyID, req.IBAN, req.BIC)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, account)
+}
+
+// ExportPayrollTransfers sends the SEPA XML batch paying the payslips of a
+// month: ?month=YYYY-MM and an optional ?execution_date=YYYY-MM-DD (today
+// by default).
+func (h *PayrollHandler) ExportPayrollTransfers(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+	month, err := time.Parse("2006-01", r.URL.Query().Get("month"))
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+	executionDate := time.Now()
+	if s := r.URL.Query().Get("execution_date"); s != "" {
+		if executionDate, err = time.Parse("2006-01-02", s); err != nil {
+			http.Error(w, "Invalid execution_date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	document, err := h.service.ExportPayrollTransfers(r.Context(), companyID, month, executionDate)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeFile(w, "transferencias-nominas-"+month.Format("2006-01")+".xml", document)
+}
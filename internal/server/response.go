@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/fuenr/myteam/internal/domain"
+)
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// writeError maps the generic domain errors to HTTP status codes. Validation
+// errors list the invalid fields so forms can show each message.
+func writeError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":  err.Error(),
+			"fields": validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrDuplicate):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var downloadTypes = map[string]string{
+	".csv": "text/csv; charset=utf-8",
+	".pdf": "application/pdf",
+	".xml": "application/xml",
+	".zip": "application/zip",
+}
+
+// writeFile sends body as a download named filename.
+func writeFile(w http.ResponseWriter, filename string, body []byte) {
+	contentType, ok := downloadTypes[path.Ext(filename)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
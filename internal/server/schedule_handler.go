@@ -0,0 +1,193 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type ScheduleHandler struct {
+	service *service.ScheduleService
+}
+
+func NewScheduleHandler(service *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+func (h *ScheduleHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.ScheduleTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.CreateTemplate(r.Context(), companyID, input)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, template)
+}
+
+func (h *ScheduleHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	templates, err := h.service.GetTemplates(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (h *ScheduleHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("templateID"))
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), companyID, id); err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ScheduleHandler) AssignSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.ScheduleAssignmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.service.AssignSchedule(r.Context(), userID, input)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, assignment)
+}
+
+func (h *ScheduleHandler) GetAssignments(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	assignments, err := h.service.GetAssignments(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignments)
+}
+
+func (h *ScheduleHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid assignment ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteAssignment(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRoster returns the shift plan of ?week=YYYY-Www (defaults to the current week).
+func (h *ScheduleHandler) GetRoster(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	year, week := time.Now().ISOWeek()
+	if weekStr := r.URL.Query().Get("week"); weekStr != "" {
+		if _, err := fmt.Sscanf(weekStr, "%d-W%d", &year, &week); err != nil {
+			http.Error(w, "Invalid week, expected YYYY-Www", http.StatusBadRequest)
+			return
+		}
+	}
+	weekStart, ok := isoWeekStart(year, week)
+	if !ok {
+		http.Error(w, "Invalid week, expected YYYY-Www", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	roster, err := h.service.GetRoster(r.Context(), companyID, weekStart, claims.Role)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, roster)
+}
+
+// isoWeekStart returns the Monday of an ISO 8601 week.
+func isoWeekStart(year, week int) (time.Time, bool) {
+	// January 4th always falls in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return monday, true
+}
+
+// writeScheduleError returns the rest violations as JSON so planners can
+// see which shifts to move.
+func writeScheduleError(w http.ResponseWriter, err error) {
+	var restErr *domain.RestViolationError
+	switch {
+	case errors.As(err, &restErr):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":      err.Error(),
+			"violations": restErr.Violations,
+		})
+	case errors.Is(err, domain.ErrInsufficientRest):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrScheduleOverlap), errors.Is(err, domain.ErrScheduleTemplateInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err)
+	}
+}
@@ -1,11 +1,16 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
 )
 
+// shutdownTimeout is how long the requests in flight may take to finish
+// once the server is asked to stop.
+const shutdownTimeout = 15 * time.Second
+
 type Server struct {
 	addr   string
 	router http.Handler
@@ -18,7 +23,9 @@ func NewServer(port string, router http.Handler) *Server {
 	}
 }
 
-func (s *Server) Run() error {
+// Run serves until ctx is cancelled, then stops accepting connections and
+// waits for the requests in flight.
+func (s *Server) Run(ctx context.Context) error {
 	srv := &http.Server{
 		Addr:         s.addr,
 		Handler:      s.router,
@@ -27,6 +34,19 @@ func (s *Server) Run() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("Server starting on %s", s.addr)
-	return srv.ListenAndServe()
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on %s", s.addr)
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Server shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
 }
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type SettlementHandler struct {
+	service *service.SettlementService
+}
+
+func NewSettlementHandler(service *service.SettlementService) *SettlementHandler {
+	return &SettlementHandler{service: service}
+}
+
+// TerminateContract ends a contract and returns its final settlement.
+// Body: {"date": "YYYY-MM-DD", "reason": "OBJECTIVE_DISMISSAL", "payments": 14}.
+func (h *SettlementHandler) TerminateContract(w http.ResponseWriter, r *http.Request) {
+	contractID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid contract ID", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Date     string                   `json:"date"`
+		Reason   domain.TerminationReason `json:"reason"`
+		Payments int                      `json:"payments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		http.Error(w, "Invalid date", http.StatusBadRequest)
+		return
+	}
+
+	termination, err := h.service.Terminate(r.Context(), contractID, service.TerminationInput{
+		Date:            date,
+		Reason:          req.Reason,
+		PaymentsPerYear: req.Payments,
+		AuthorID:        claims.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrContractTerminated) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, termination)
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+type TimeEntryHandler struct {
+	service *service.TimeEntryService
+}
+
+func NewTimeEntryHandler(service *service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{service: service}
+}
+
+func (h *TimeEntryHandler) ClockIn(w http.ResponseWriter, r *http.Request) {
+	h.clock(w, r, http.StatusCreated, h.service.ClockIn)
+}
+
+func (h *TimeEntryHandler) ClockOut(w http.ResponseWriter, r *http.Request) {
+	h.clock(w, r, http.StatusOK, h.service.ClockOut)
+}
+
+func (h *TimeEntryHandler) StartBreak(w http.ResponseWriter, r *http.Request) {
+	h.clock(w, r, http.StatusOK, h.service.StartBreak)
+}
+
+func (h *TimeEntryHandler) EndBreak(w http.ResponseWriter, r *http.Request) {
+	h.clock(w, r, http.StatusOK, h.service.EndBreak)
+}
+
+type clockFunc func(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error)
+
+func (h *TimeEntryHandler) clock(w http.ResponseWriter, r *http.Request, status int, fn clockFunc) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := fn(r.Context(), userID)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+	writeJSON(w, status, entry)
+}
+
+// GetEntries lists the entries between the from and to query parameters
+// (YYYY-MM-DD). Defaults to the current month.
+func (h *TimeEntryHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	monthStart, nextMonth := domain.MonthBounds(time.Now().In(domain.RegisterLocation))
+	from, err := parseDateParam(r, "from", monthStart)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r, "to", nextMonth.AddDate(0, 0, -1))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.GetEntries(r.Context(), userID, from, to)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// GetMonthlySummary returns the register of ?month=YYYY-MM (defaults to the current month).
+func (h *TimeEntryHandler) GetMonthlySummary(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	month, err := parseMonthParam(r)
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.GetMonthlySummary(r.Context(), userID, month)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (h *TimeEntryHandler) EditEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid time entry ID", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input service.EditTimeEntryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input.ID = id
+	input.EditorID = claims.UserID
+
+	entry, err := h.service.EditEntry(r.Context(), input)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeEntryHandler) GetEdits(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid time entry ID", http.StatusBadRequest)
+		return
+	}
+
+	edits, err := h.service.GetEdits(r.Context(), id)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, edits)
+}
+
+// parseMonthParam reads ?month=YYYY-MM, defaulting to the current month.
+func parseMonthParam(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("month")
+	if value == "" {
+		now := time.Now().In(domain.RegisterLocation)
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse("2006-01", value)
+}
+
+func writeTimeEntryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "Time entry not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrAlreadyClockedIn),
+		errors.Is(err, domain.ErrNotClockedIn),
+		errors.Is(err, domain.ErrBreakInProgress),
+		errors.Is(err, domain.ErrNoBreakInProgress):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrJustificationRequired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeError(w, err)
+	}
+}
+
+// ExportTimeRegister downloads the register of ?month=YYYY-MM as ?format=pdf (default) or csv.
+func (h *TimeEntryHandler) ExportTimeRegister(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	month, err := parseMonthParam(r)
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+	format := exportFormat(r)
+
+	body, err := h.service.ExportTimeRegister(r.Context(), userID, month, format)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeFile(w, fmt.Sprintf("registro-jornada-%s.%s", month.Format("2006-01"), format), body)
+}
+
+// ExportCompanyTimeRegisters downloads a ZIP with the register of every employee.
+func (h *TimeEntryHandler) ExportCompanyTimeRegisters(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+	month, err := parseMonthParam(r)
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+
+	body, err := h.service.ExportCompanyTimeRegisters(r.Context(), companyID, month, exportFormat(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeFile(w, fmt.Sprintf("registro-jornada-%s.zip", month.Format("2006-01")), body)
+}
+
+// AcknowledgeTimeRegister signs the register of a past month. Body: {"month": "YYYY-MM"}.
+func (h *TimeEntryHandler) AcknowledgeTimeRegister(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Month string `json:"month"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	month, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		http.Error(w, "Invalid month", http.StatusBadRequest)
+		return
+	}
+
+	ack, err := h.service.AcknowledgeTimeRegister(r.Context(), userID, claims.UserID, month)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, ack)
+}
+
+func exportFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	return service.FormatPDF
+}
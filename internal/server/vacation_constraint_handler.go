@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fuenr/myteam/internal/service"
+	"github.com/google/uuid"
+)
+
+func (h *VacationHandler) GetBlackoutPeriods(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	blackouts, err := h.service.GetBlackoutPeriods(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, blackouts)
+}
+
+func (h *VacationHandler) CreateBlackoutPeriod(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.BlackoutPeriodInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	blackout, err := h.service.CreateBlackoutPeriod(r.Context(), companyID, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, blackout)
+}
+
+func (h *VacationHandler) DeleteBlackoutPeriod(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("blackoutID"))
+	if err != nil {
+		http.Error(w, "Invalid blackout period ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteBlackoutPeriod(r.Context(), companyID, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *VacationHandler) GetStaffingRules(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.service.GetStaffingRules(r.Context(), companyID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
+
+func (h *VacationHandler) CreateStaffingRule(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.StaffingRuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.service.CreateStaffingRule(r.Context(), companyID, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+func (h *VacationHandler) DeleteStaffingRule(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("ruleID"))
+	if err != nil {
+		http.Error(w, "Invalid staffing rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteStaffingRule(r.Context(), companyID, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,9 +1,15 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/fuenr/myteam/internal/adapter/middleware"
+	"github.com/fuenr/myteam/internal/auth"
 	"github.com/fuenr/myteam/internal/domain"
 	"github.com/fuenr/myteam/internal/service"
 	"github.com/google/uuid"
@@ -31,10 +37,14 @@ func (h *VacationHandler) CreateVacation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	input.UserID = userID
+	input.LeaveTypeID = nil // Other leave types go through CreateAbsence
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims); ok {
+		input.ActorID, input.ActorRole = claims.UserID, claims.Role
+	}
 
 	vacation, err := h.service.CreateVacation(r.Context(), input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeVacationError(w, err)
 		return
 	}
 
@@ -42,6 +52,57 @@ func (h *VacationHandler) CreateVacation(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(vacation)
 }
 
+// CreateAbsence requests an absence of any leave type.
+func (h *VacationHandler) CreateAbsence(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var input service.CreateVacationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input.UserID = userID
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims); ok {
+		input.ActorID, input.ActorRole = claims.UserID, claims.Role
+	}
+
+	absence, err := h.service.CreateVacation(r.Context(), input)
+	if err != nil {
+		writeVacationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(absence)
+}
+
+func (h *VacationHandler) GetAbsencesByUserID(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkReader(w, r, userID) {
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	absences, err := h.service.GetAbsencesByUserID(r.Context(), userID, claims.UserID, claims.Role)
+	if err != nil {
+		writeVacationError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(absences)
+}
+
 func (h *VacationHandler) GetVacationsByUserID(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.PathValue("userID")
 	userID, err := uuid.Parse(userIDStr)
@@ -50,9 +111,13 @@ func (h *VacationHandler) GetVacationsByUserID(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if !h.checkReader(w, r, userID) {
+		return
+	}
+
 	vacations, err := h.service.GetVacationsByUserID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeVacationError(w, err)
 		return
 	}
 
@@ -73,14 +138,16 @@ func (h *VacationHandler) UpdateVacation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	input.ID = id
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	input.ActorID, input.ActorRole = claims.UserID, claims.Role
 
 	vacation, err := h.service.UpdateVacation(r.Context(), input)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			http.Error(w, "Vacation not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeVacationError(w, err)
 		return
 	}
 
@@ -95,14 +162,148 @@ func (h *VacationHandler) DeleteVacation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.service.DeleteVacation(r.Context(), id); err != nil {
-		if err == domain.ErrNotFound {
-			http.Error(w, "Vacation not found", http.StatusNotFound)
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := service.VacationDecisionInput{ID: id, ActorID: claims.UserID, ActorRole: claims.Role}
+	if err := h.service.DeleteVacation(r.Context(), input); err != nil {
+		writeVacationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *VacationHandler) ApproveVacation(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.ApproveVacation)
+}
+
+func (h *VacationHandler) RejectVacation(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.RejectVacation)
+}
+
+func (h *VacationHandler) CancelVacation(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.CancelVacation)
+}
+
+type decisionFunc func(ctx context.Context, input service.VacationDecisionInput) (*domain.Vacation, error)
+
+func (h *VacationHandler) decide(w http.ResponseWriter, r *http.Request, fn decisionFunc) {
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid vacation ID", http.StatusBadRequest)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// The body is optional; it only carries the rejection reason or the constraint override.
+	var input service.VacationDecisionInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	input.ID = id
+	input.ActorID = claims.UserID
+	input.ActorRole = claims.Role
+
+	vacation, err := fn(r.Context(), input)
+	if err != nil {
+		writeVacationError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(vacation)
+}
+
+func (h *VacationHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if !h.checkReader(w, r, userID) {
+		return
+	}
+
+	balance, err := h.service.GetBalance(r.Context(), userID, year)
+	if err != nil {
+		writeVacationError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(balance)
+}
+
+// checkReader answers 403 unless the caller may see the vacations of the user:
+// their own, those of their direct reports for managers, or anyone's.
+func (h *VacationHandler) checkReader(w http.ResponseWriter, r *http.Request, userID uuid.UUID) bool {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	if err := h.service.CheckReader(r.Context(), userID, claims.UserID, claims.Role); err != nil {
+		writeVacationError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeVacationError maps service errors to HTTP responses.
+// Business rule violations are returned as JSON so clients can show the details.
+func writeVacationError(w http.ResponseWriter, err error) {
+	var balanceErr *domain.InsufficientBalanceError
+	var overlapErr *domain.VacationOverlapError
+	var constraintErr *domain.ConstraintViolationError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "Vacation not found", http.StatusNotFound)
+	case errors.As(err, &overlapErr):
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":                    err.Error(),
+			"conflicting_vacation_ids": overlapErr.ConflictingIDs,
+		})
+	case errors.As(err, &balanceErr):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":   err.Error(),
+			"balance": balanceErr,
+		})
+	case errors.As(err, &constraintErr):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":      err.Error(),
+			"violations": constraintErr.Violations,
+		})
+	case errors.Is(err, domain.ErrInvalidTransition),
+		errors.Is(err, domain.ErrVacationNotEditable),
+		errors.Is(err, domain.ErrVacationAlreadyBegun):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrSelfApproval):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrAttachmentRequired), errors.Is(err, domain.ErrInvalidPeriod):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeError(w, err)
+	}
 }
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/ical"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// feedWindow is how far back and ahead of today the iCalendar feed reaches.
+const feedWindow = 365 * 24 * time.Hour
+
+type AbsenceService struct {
+	vacationRepo port.VacationRepository
+	feedRepo     port.CalendarFeedRepository
+	userRepo     port.UserRepository
+	publicURL    string
+}
+
+func NewAbsenceService(vacationRepo port.VacationRepository, feedRepo port.CalendarFeedRepository, userRepo port.UserRepository, publicURL string) *AbsenceService {
+	return &AbsenceService{
+		vacationRepo: vacationRepo,
+		feedRepo:     feedRepo,
+		userRepo:     userRepo,
+		publicURL:    publicURL,
+	}
+}
+
+// GetCompanyAbsences returns who is off between from and to (both inclusive).
+// Viewers without PermAbsenceReadDetails only see why colleagues are off
+// when it is a vacation.
+func (s *AbsenceService) GetCompanyAbsences(ctx context.Context, companyID uuid.UUID, from, to time.Time, viewer domain.Role) ([]*domain.TeamAbsence, error) {
+	if from.After(to) {
+		return nil, domain.ErrInvalidInput
+	}
+	absences, err := s.vacationRepo.GetAbsencesByCompanyID(ctx, companyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.Can(domain.PermAbsenceReadDetails) {
+		absences = domain.RedactAbsences(absences)
+	}
+	return absences, nil
+}
+
+// CreateFeed issues a new secret feed URL for the user, invalidating the
+// previous one. The token is only returned here; it cannot be recovered later.
+func (s *AbsenceService) CreateFeed(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	feed := &domain.CalendarFeed{
+		UserID:    user.ID,
+		CompanyID: user.CompanyID,
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now(),
+	}
+	if err := s.feedRepo.SaveCalendarFeed(ctx, feed); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/feeds/%s/absences.ics", s.publicURL, token), nil
+}
+
+func (s *AbsenceService) RevokeFeed(ctx context.Context, userID uuid.UUID) error {
+	return s.feedRepo.DeleteCalendarFeed(ctx, userID)
+}
+
+// RenderFeed returns the iCalendar document of the company the feed token
+// belongs to. Anyone holding the URL can read it, so absences that are not
+// vacations are always shown as AbsenceLabel.
+func (s *AbsenceService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feedRepo.GetCalendarFeedByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = domain.WithCompanyScope(ctx, feed.CompanyID)
+	now := time.Now()
+	absences, err := s.vacationRepo.GetAbsencesByCompanyID(ctx, feed.CompanyID, now.Add(-feedWindow), now.Add(feedWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ical.Event, 0, len(absences))
+	for _, a := range domain.RedactAbsences(absences) {
+		summary := a.UserName + " - " + a.LeaveTypeName
+		if label := a.PeriodLabel(); label != "" {
+			summary += " (" + label + ")"
+		}
+		if a.Status == domain.VacationStatusPending {
+			summary += " (pendiente)"
+		}
+		events = append(events, ical.Event{
+			UID:     a.ID.String() + "@myteam",
+			Summary: summary,
+			Start:   a.StartDate,
+			End:     a.EndDate.AddDate(0, 0, 1), // DTEND is exclusive
+			AllDay:  true,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, "Ausencias del equipo", events); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/ical"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// absenceStore serves a single feed and the absences of its company. The
+// embedded ports are nil: calling anything not implemented here panics.
+type absenceStore struct {
+	port.VacationRepository
+	port.CalendarFeedRepository
+
+	feed     *domain.CalendarFeed
+	absences []*domain.TeamAbsence
+}
+
+func (s *absenceStore) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	if tokenHash != s.feed.TokenHash {
+		return nil, domain.ErrNotFound
+	}
+	return s.feed, nil
+}
+
+func (s *absenceStore) GetAbsencesByCompanyID(ctx context.Context, companyID uuid.UUID, from, to time.Time) ([]*domain.TeamAbsence, error) {
+	if companyID != s.feed.CompanyID {
+		return nil, nil
+	}
+	return s.absences, nil
+}
+
+func TestRenderFeed(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, time.August, d, 0, 0, 0, 0, time.UTC)
+	}
+	absence := func(name string, start, end time.Time, status domain.VacationStatus) *domain.TeamAbsence {
+		return &domain.TeamAbsence{
+			Vacation:      &domain.Vacation{ID: uuid.New(), UserID: uuid.New(), StartDate: start, EndDate: end, Period: domain.PeriodFullDay, Status: status},
+			UserName:      name,
+			LeaveTypeName: "Vacaciones",
+		}
+	}
+	morning := absence("Luis", day(8), day(8), domain.VacationStatusApproved)
+	morning.Period = domain.PeriodMorning
+	sick := absence("Marta", day(11), day(12), domain.VacationStatusApproved)
+	leaveTypeID := uuid.New()
+	sick.LeaveTypeID, sick.LeaveTypeName = &leaveTypeID, "Baja médica"
+
+	store := &absenceStore{
+		feed: &domain.CalendarFeed{UserID: uuid.New(), CompanyID: uuid.New(), TokenHash: hashToken("secret")},
+		absences: []*domain.TeamAbsence{
+			absence("Ana", day(4), day(8), domain.VacationStatusApproved),
+			absence("Ana", day(18), day(22), domain.VacationStatusPending),
+			morning,
+			sick,
+		},
+	}
+	s := NewAbsenceService(store, store, nil, "https://myteam.example")
+
+	if _, err := s.RenderFeed(context.Background(), "guessed"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("RenderFeed with an unknown token err = %v, want ErrNotFound", err)
+	}
+
+	data, err := s.RenderFeed(context.Background(), "secret")
+	if err != nil {
+		t.Fatalf("RenderFeed: %v", err)
+	}
+	if strings.Contains(string(data), "Baja") {
+		t.Error("the feed shows the leave type of a sick leave")
+	}
+	events, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		summary    string
+		start, end time.Time // The end is exclusive
+	}{
+		{"Ana - Vacaciones", day(4), day(9)},
+		{"Ana - Vacaciones (pendiente)", day(18), day(23)},
+		{"Luis - Vacaciones (mañana)", day(8), day(9)},
+		{"Marta - " + domain.AbsenceLabel, day(11), day(13)},
+	}
+	if len(events) != len(tests) {
+		t.Fatalf("got %d events, want %d", len(events), len(tests))
+	}
+	for i, tt := range tests {
+		e := events[i]
+		if e.Summary != tt.summary || !e.AllDay || !e.Start.Equal(tt.start) || !e.End.Equal(tt.end) {
+			t.Errorf("event %d = %q %s-%s, want %q %s-%s", i, e.Summary, e.Start.Format(time.DateOnly), e.End.Format(time.DateOnly),
+				tt.summary, tt.start.Format(time.DateOnly), tt.end.Format(time.DateOnly))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountService emails the links that let users set their password: the
+// invitation of new users and the reset of forgotten passwords.
+type AccountService struct {
+	userRepo  port.UserRepository
+	tokenRepo port.AccountTokenRepository
+	mailer    port.MailSender
+	appURL    string // Base URL of the web app, where the links point
+}
+
+func NewAccountService(userRepo port.UserRepository, tokenRepo port.AccountTokenRepository, mailer port.MailSender, appURL string) *AccountService {
+	return &AccountService{userRepo: userRepo, tokenRepo: tokenRepo, mailer: mailer, appURL: appURL}
+}
+
+// Invite emails the user a link to choose their password, valid for a week.
+func (s *AccountService) Invite(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive() {
+		return domain.ErrNotFound
+	}
+	return s.send(ctx, user, domain.AccountTokenInvitation)
+}
+
+// forgotPasswordTimeout bounds the lookup and delivery of a reset email once
+// the request has been answered.
+const forgotPasswordTimeout = time.Minute
+
+// ForgotPassword emails a reset link, valid for an hour, to the user with
+// that email. It returns at once and works in the background, logging any
+// failure: neither the answer nor its timing tells who has an account.
+func (s *AccountService) ForgotPassword(ctx context.Context, email string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), forgotPasswordTimeout)
+	go func() {
+		defer cancel()
+		user, err := s.userRepo.GetUserByEmail(ctx, email)
+		if err != nil {
+			if err != domain.ErrNotFound {
+				log.Printf("Failed to look up the user for a password reset: %v", err)
+			}
+			return
+		}
+		if !user.IsActive() {
+			return
+		}
+		ctx = domain.WithCompanyScope(ctx, user.CompanyID)
+		if err := s.send(ctx, user, domain.AccountTokenPasswordReset); err != nil {
+			log.Printf("Failed to send the password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+}
+
+// ResetPassword sets the password with an invitation or reset token, which
+// cannot be used again, and ends every session of the user.
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	now := time.Now()
+	t, err := s.tokenRepo.GetAccountTokenByHash(ctx, domain.HashAccountToken(token))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if !t.IsActive(now) {
+		return domain.ErrInvalidToken
+	}
+	// A weak password does not spend the token
+	if err := checkNewPassword(newPassword); err != nil {
+		return err
+	}
+
+	ctx = domain.WithCompanyScope(ctx, t.CompanyID)
+	user, err := s.userRepo.GetUserByID(ctx, t.UserID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if !user.IsActive() {
+		return domain.ErrInvalidToken
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.SetPasswordHash(string(hash))
+	user.UpdatedAt = now
+
+	// The token is spent with the password update: if saving fails, it still works
+	used, err := s.tokenRepo.UseAccountToken(ctx, t.ID, user, now)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *AccountService) send(ctx context.Context, user *domain.User, purpose domain.AccountTokenPurpose) error {
+	token, secret, err := domain.NewAccountToken(user.ID, purpose, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.tokenRepo.CreateAccountToken(ctx, token); err != nil {
+		return err
+	}
+
+	link := s.appURL + "/set-password?token=" + url.QueryEscape(secret)
+	mail := port.Mail{To: user.Email}
+	switch purpose {
+	case domain.AccountTokenInvitation:
+		mail.Subject = "You have been invited to MyTeam"
+		mail.Body = fmt.Sprintf("Hello %s,\n\nYou have been invited to MyTeam. Choose your password to sign in:\n\n%s\n\nThe link expires in 7 days.\n", user.Name, link)
+	default:
+		mail.Subject = "Reset your MyTeam password"
+		mail.Body = fmt.Sprintf("Hello %s,\n\nSomeone asked to reset your MyTeam password. Choose a new one here:\n\n%s\n\nThe link expires in 1 hour and works once. If it was not you, ignore this email.\n", user.Name, link)
+	}
+	return s.mailer.Send(ctx, mail)
+}
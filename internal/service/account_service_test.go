@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// accountTokenStore keeps account tokens in memory and saves the password
+// of their users in users. Spending a token fails with saveErr, leaving it
+// unused, when it is set.
+type accountTokenStore struct {
+	users   *userStore
+	tokens  []*domain.AccountToken
+	saveErr error
+}
+
+func (s *accountTokenStore) CreateAccountToken(ctx context.Context, token *domain.AccountToken) error {
+	s.tokens = append(s.tokens, token)
+	return nil
+}
+
+func (s *accountTokenStore) GetAccountTokenByHash(ctx context.Context, hash string) (*domain.AccountToken, error) {
+	for _, t := range s.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			copied.CompanyID = s.users.users[t.UserID].CompanyID
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *accountTokenStore) UseAccountToken(ctx context.Context, id uuid.UUID, user *domain.User, usedAt time.Time) (bool, error) {
+	if s.saveErr != nil {
+		return false, s.saveErr
+	}
+	for _, t := range s.tokens {
+		if t.ID == id {
+			if t.UsedAt != nil {
+				return false, nil
+			}
+			t.UsedAt = &usedAt
+			return true, s.users.UpdateUser(ctx, user)
+		}
+	}
+	return false, domain.ErrNotFound
+}
+
+// mailbox delivers the emails to a channel.
+type mailbox chan port.Mail
+
+func (m mailbox) Send(ctx context.Context, mail port.Mail) error {
+	select {
+	case m <- mail:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resetToken returns the token of the link in the email.
+func resetToken(t *testing.T, mail port.Mail) string {
+	t.Helper()
+	_, link, ok := strings.Cut(mail.Body, "token=")
+	if !ok {
+		t.Fatalf("no link in the email: %s", mail.Body)
+	}
+	token, err := url.QueryUnescape(strings.Fields(link)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newTestAccountService() (*AccountService, *accountTokenStore, mailbox, *domain.User) {
+	user := &domain.User{ID: uuid.New(), CompanyID: uuid.New(), Name: "Ana", Email: "ana@example.com", Role: domain.RoleEmployee}
+	tokens := &accountTokenStore{users: &userStore{users: map[uuid.UUID]*domain.User{user.ID: user}}}
+	mails := make(mailbox, 1)
+	return NewAccountService(tokens.users, tokens, mails, "https://myteam.example"), tokens, mails, user
+}
+
+func TestAccountServiceForgotPassword(t *testing.T) {
+	s, tokens, mails, user := newTestAccountService()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The email is sent in the background: the end of the request does not stop it
+	s.ForgotPassword(ctx, user.Email)
+	cancel()
+	var mail port.Mail
+	select {
+	case mail = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reset email was sent")
+	}
+	if mail.To != user.Email {
+		t.Errorf("email sent to %s, want %s", mail.To, user.Email)
+	}
+
+	if err := s.ResetPassword(context.Background(), resetToken(t, mail), "nuevo-secreto"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	saved := tokens.users.users[user.ID]
+	if err := bcrypt.CompareHashAndPassword([]byte(saved.PasswordHash), []byte("nuevo-secreto")); err != nil || saved.TokenVersion != 1 {
+		t.Errorf("saved user has token version %d and password err %v, want the new password and version 1", saved.TokenVersion, err)
+	}
+	// Single use
+	if err := s.ResetPassword(context.Background(), resetToken(t, mail), "otro-secreto"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("ResetPassword with a used token err = %v, want ErrInvalidToken", err)
+	}
+
+	// Unknown and deactivated users get no email
+	tokens.users.users[user.ID].Deactivate(time.Now())
+	for _, email := range []string{"nadie@example.com", user.Email} {
+		s.ForgotPassword(context.Background(), email)
+	}
+	select {
+	case mail := <-mails:
+		t.Errorf("email sent to %s", mail.To)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAccountServiceResetPasswordRejects(t *testing.T) {
+	errDatabase := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		apply    func(token *domain.AccountToken, tokens *accountTokenStore, user *domain.User)
+		password string
+		want     error
+	}{
+		{"expired", func(token *domain.AccountToken, _ *accountTokenStore, _ *domain.User) {
+			token.ExpiresAt = time.Now().Add(-time.Minute)
+		}, "nuevo-secreto", domain.ErrInvalidToken},
+		{"deactivated user", func(_ *domain.AccountToken, _ *accountTokenStore, user *domain.User) {
+			user.Deactivate(time.Now())
+		}, "nuevo-secreto", domain.ErrInvalidToken},
+		{"weak password", nil, "corto", domain.ErrInvalidInput},
+		{"failed save", func(_ *domain.AccountToken, tokens *accountTokenStore, _ *domain.User) {
+			tokens.saveErr = errDatabase
+		}, "nuevo-secreto", errDatabase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, tokens, mails, user := newTestAccountService()
+			if err := s.Invite(context.Background(), user.ID); err != nil {
+				t.Fatalf("Invite: %v", err)
+			}
+			token := resetToken(t, <-mails)
+			if tt.apply != nil {
+				tt.apply(tokens.tokens[0], tokens, user)
+			}
+
+			if err := s.ResetPassword(context.Background(), token, tt.password); !errors.Is(err, tt.want) {
+				t.Fatalf("ResetPassword err = %v, want %v", err, tt.want)
+			}
+			if tokens.tokens[0].UsedAt != nil {
+				t.Error("the rejected reset spent the token")
+			}
+			if tt.want == domain.ErrInvalidToken {
+				return
+			}
+			// The token still works
+			tokens.saveErr = nil
+			if err := s.ResetPassword(context.Background(), token, "nuevo-secreto"); err != nil {
+				t.Errorf("ResetPassword after the rejection: %v", err)
+			}
+		})
+	}
+}
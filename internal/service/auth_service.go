@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthService issues, refreshes and revokes the tokens of user sessions.
+type AuthService struct {
+	userRepo         port.UserRepository
+	refreshTokenRepo port.RefreshTokenRepository
+}
+
+func NewAuthService(userRepo port.UserRepository, refreshTokenRepo port.RefreshTokenRepository) *AuthService {
+	return &AuthService{userRepo: userRepo, refreshTokenRepo: refreshTokenRepo}
+}
+
+// TokenPair is a short-lived access token and the refresh token to renew it.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds the access token lasts
+}
+
+// IssueTokens starts a new session for the user.
+func (s *AuthService) IssueTokens(ctx context.Context, user *domain.User) (*TokenPair, error) {
+	return s.issue(ctx, user, uuid.Nil, time.Now())
+}
+
+func (s *AuthService) issue(ctx context.Context, user *domain.User, familyID uuid.UUID, now time.Time) (*TokenPair, error) {
+	accessToken, err := auth.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, secret, err := domain.NewRefreshToken(user.ID, familyID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+	return &TokenPair{AccessToken: accessToken, RefreshToken: secret, ExpiresIn: int(auth.AccessTokenTTL.Seconds())}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of the same session. A
+// token presented twice revokes the session, since either the client or an
+// attacker holds a stolen copy.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	now := time.Now()
+	token, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if token.UsedAt != nil {
+		if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+	if !token.IsActive(now) {
+		return nil, domain.ErrInvalidToken
+	}
+	// Two concurrent requests with the same token: only one wins
+	used, err := s.refreshTokenRepo.UseRefreshToken(ctx, token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	ctx = domain.WithCompanyScope(ctx, token.CompanyID)
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrInvalidToken
+	}
+	return s.issue(ctx, user, token.FamilyID, now)
+}
+
+// Logout ends the session of the refresh token. With all, it ends every
+// session of its user and revokes the access tokens already issued.
+func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, refreshToken string, all bool) error {
+	now := time.Now()
+	if all {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		user.RevokeTokens()
+		return s.revokeSessions(ctx, user, now)
+	}
+
+	token, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if token.UserID != userID {
+		return domain.ErrInvalidToken
+	}
+	return s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now)
+}
+
+// ChangePassword sets a new password and ends every session of the user.
+// Users changing their own password must confirm the current one.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string, actorID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if actorID == userID {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+			return domain.ErrInvalidCredentials
+		}
+	}
+	if err := checkNewPassword(newPassword); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.SetPasswordHash(string(hash))
+	return s.revokeSessions(ctx, user, time.Now())
+}
+
+func checkNewPassword(password string) error {
+	if len(password) < 8 {
+		return &domain.ValidationError{Fields: []domain.FieldError{{Field: "new_password", Message: "must have at least 8 characters"}}}
+	}
+	return nil
+}
+
+// revokeSessions saves the user, whose token version the caller bumped, and
+// revokes all their refresh tokens.
+func (s *AuthService) revokeSessions(ctx context.Context, user *domain.User, now time.Time) error {
+	user.UpdatedAt = now
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, user.ID, now)
+}
+
+// CheckToken rejects the access tokens of deleted or deactivated users, the ones issued
+// before their token version was bumped and the ones of another company.
+func (s *AuthService) CheckToken(ctx context.Context, claims *auth.Claims) error {
+	user, err := s.userRepo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if !user.IsActive() || user.TokenVersion != claims.TokenVersion || user.CompanyID != claims.CompanyID {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+// CheckUser returns domain.ErrNotFound for users outside the company scope
+// of ctx.
+func (s *AuthService) CheckUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := s.userRepo.GetUserByID(ctx, userID)
+	return err
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// refreshStore keeps refresh tokens in memory. The embedded port is nil:
+// calling anything not implemented here panics.
+type refreshStore struct {
+	port.RefreshTokenRepository
+
+	tokens []*domain.RefreshToken
+}
+
+func (s *refreshStore) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	s.tokens = append(s.tokens, token)
+	return nil
+}
+
+func (s *refreshStore) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	for _, t := range s.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *refreshStore) UseRefreshToken(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	for _, t := range s.tokens {
+		if t.ID == id {
+			if t.UsedAt != nil {
+				return false, nil
+			}
+			t.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, domain.ErrNotFound
+}
+
+func (s *refreshStore) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, revokedAt time.Time) error {
+	for _, t := range s.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func newTestAuthService(t *testing.T) (*AuthService, *userStore, *domain.User) {
+	t.Helper()
+	keys, err := auth.EphemeralKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetKeySet(keys)
+	t.Cleanup(func() { auth.SetKeySet(nil) })
+
+	user := &domain.User{ID: uuid.New(), CompanyID: uuid.New(), Role: domain.RoleEmployee}
+	users := &userStore{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	return NewAuthService(users, &refreshStore{}), users, user
+}
+
+func TestAuthServiceRefresh(t *testing.T) {
+	s, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	first, err := s.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh returned the same refresh token")
+	}
+	claims, err := auth.ValidateToken(second.AccessToken)
+	if err != nil || claims.UserID != user.ID {
+		t.Fatalf("access token of the refresh: claims %+v, err %v", claims, err)
+	}
+
+	// The rotated token is spent: presenting it again revokes the session,
+	// including the token issued in its place
+	if _, err := s.Refresh(ctx, first.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Refresh with a used token err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := s.Refresh(ctx, second.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Refresh after the reuse err = %v, want ErrInvalidToken", err)
+	}
+
+	// Other sessions go on
+	other, err := s.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	if _, err := s.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Refresh of another session: %v", err)
+	}
+	if _, err := s.Refresh(ctx, "guessed"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Refresh with an unknown token err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthServiceRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(token *domain.RefreshToken, users *userStore, user *domain.User)
+	}{
+		{"expired", func(token *domain.RefreshToken, _ *userStore, _ *domain.User) {
+			token.ExpiresAt = time.Now().Add(-time.Minute)
+		}},
+		{"revoked", func(token *domain.RefreshToken, _ *userStore, _ *domain.User) {
+			revokedAt := time.Now()
+			token.RevokedAt = &revokedAt
+		}},
+		{"deleted user", func(_ *domain.RefreshToken, users *userStore, user *domain.User) {
+			delete(users.users, user.ID)
+		}},
+		{"deactivated user", func(_ *domain.RefreshToken, _ *userStore, user *domain.User) {
+			user.Deactivate(time.Now())
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, users, user := newTestAuthService(t)
+			ctx := context.Background()
+			pair, err := s.IssueTokens(ctx, user)
+			if err != nil {
+				t.Fatalf("IssueTokens: %v", err)
+			}
+			tt.apply(s.refreshTokenRepo.(*refreshStore).tokens[0], users, user)
+
+			if _, err := s.Refresh(ctx, pair.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+				t.Errorf("Refresh err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestAuthServiceCheckToken(t *testing.T) {
+	s, _, user := newTestAuthService(t)
+	user.TokenVersion = 2
+
+	tests := []struct {
+		name   string
+		claims auth.Claims
+		want   error
+	}{
+		{"current version", auth.Claims{UserID: user.ID, CompanyID: user.CompanyID, TokenVersion: 2}, nil},
+		{"issued before the version was bumped", auth.Claims{UserID: user.ID, CompanyID: user.CompanyID, TokenVersion: 1}, domain.ErrInvalidToken},
+		{"version from the future", auth.Claims{UserID: user.ID, CompanyID: user.CompanyID, TokenVersion: 3}, domain.ErrInvalidToken},
+		{"user moved to another company", auth.Claims{UserID: user.ID, CompanyID: uuid.New(), TokenVersion: 2}, domain.ErrInvalidToken},
+		{"deleted user", auth.Claims{UserID: uuid.New(), CompanyID: user.CompanyID}, domain.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.CheckToken(context.Background(), &tt.claims); !errors.Is(err, tt.want) {
+				t.Errorf("CheckToken err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
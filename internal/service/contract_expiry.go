@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProcessExpirations moves the contracts to the status of today's date and
+// reminds the company admins of the contracts ending within one of the
+// domain.ContractExpiryNoticeDays. Reminders already sent are skipped, so
+// it can run several times a day. It returns how many contracts changed
+// status and how many notifications were sent.
+func (s *ContractService) ProcessExpirations(ctx context.Context, now time.Time) (int, int, error) {
+	contracts, err := s.contractRepo.GetUnendedContracts(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var transitioned int
+	var notifications []*domain.Notification
+	admins := make(map[uuid.UUID][]*domain.User) // By company
+	for _, c := range contracts {
+		changed, err := s.refreshStatus(ctx, c, now)
+		if err != nil {
+			return 0, 0, err
+		}
+		if changed {
+			transitioned++
+		}
+
+		notice := c.ExpiryNotice(now)
+		if c.Status != domain.ContractStatusActive || notice == 0 {
+			continue
+		}
+		employee, err := s.userRepo.GetUserByID(ctx, c.UserID)
+		if err != nil {
+			return 0, 0, err
+		}
+		companyAdmins, ok := admins[employee.CompanyID]
+		if !ok {
+			if companyAdmins, err = s.companyAdmins(ctx, employee.CompanyID); err != nil {
+				return 0, 0, err
+			}
+			admins[employee.CompanyID] = companyAdmins
+		}
+		for _, admin := range companyAdmins {
+			notifications = append(notifications, domain.NewContractExpiringNotification(admin, employee, c, notice, now))
+		}
+	}
+
+	if len(notifications) == 0 {
+		return transitioned, 0, nil
+	}
+	notified, err := s.notificationRepo.CreateNotifications(ctx, notifications)
+	if err != nil {
+		return 0, 0, err
+	}
+	return transitioned, notified, nil
+}
+
+// refreshStatus saves the status of the contract on now if it changed.
+func (s *ContractService) refreshStatus(ctx context.Context, c *domain.Contract, now time.Time) (bool, error) {
+	status := c.StatusOn(now)
+	if status == c.Status {
+		return false, nil
+	}
+	c.Status, c.UpdatedAt = status, now
+	if err := s.contractRepo.UpdateContractStatus(ctx, c); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *ContractService) companyAdmins(ctx context.Context, companyID uuid.UUID) ([]*domain.User, error) {
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	var admins []*domain.User
+	for _, u := range users {
+		if u.Role.Can(domain.PermContractManage) {
+			admins = append(admins, u)
+		}
+	}
+	return admins, nil
+}
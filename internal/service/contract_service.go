@@ -10,27 +10,35 @@ import (
 )
 
 type ContractService struct {
-	contractRepo port.ContractRepository
-	userRepo     port.UserRepository
+	contractRepo     port.ContractRepository
+	userRepo         port.UserRepository
+	notificationRepo port.NotificationRepository
 }
 
-func NewContractService(contractRepo port.ContractRepository, userRepo port.UserRepository) *ContractService {
+func NewContractService(contractRepo port.ContractRepository, userRepo port.UserRepository, notificationRepo port.NotificationRepository) *ContractService {
 	return &ContractService{
-		contractRepo: contractRepo,
-		userRepo:     userRepo,
+		contractRepo:     contractRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
-func (s *ContractService) Create(ctx context.Context, userID uuid.UUID, startDate time.Time, endDate *time.Time, contractType domain.ContractType, position string, salary float64) (*domain.Contract, error) {
+func (s *ContractService) Create(ctx context.Context, userID uuid.UUID, startDate time.Time, endDate *time.Time, contractType domain.ContractType, cause domain.ContractCause, justification string, activationPeriods []domain.ActivationPeriod, position string, salary, weeklyHours, partTimePercentage float64, contributionGroup int) (*domain.Contract, error) {
 	// 1. Verify user exists
 	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
 		return nil, err
 	}
 
 	// 2. Create Contract Entity (Validates logic)
-	contract, err := domain.NewContract(userID, startDate, endDate, contractType, position, salary)
+	contract, err := domain.NewContract(userID, startDate, endDate, contractType, cause, justification, activationPeriods, position, salary)
 	if err != nil {
-		return nil, domain.ErrInvalidInput // Wrap or return specific error
+		return nil, err // Field-level *domain.ValidationError
+	}
+	if err := contract.SetWorkingHours(weeklyHours, partTimePercentage); err != nil {
+		return nil, err
+	}
+	if err := contract.SetContributionGroup(contributionGroup); err != nil {
+		return nil, err
 	}
 
 	// 3. Persist
@@ -44,47 +52,80 @@ func (s *ContractService) Get(ctx context.Context, id uuid.UUID) (*domain.Contra
 	return s.contractRepo.GetContractByID(ctx, id)
 }
 
-func (s *ContractService) GetByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Contract, error) {
-	// Verify user exists? Not strictly necessary for listing (will return empty), but good practice.
+// GetByUser returns the contracts of a user with their terms in force today
+// and the timeline of their amendments. An empty status returns them all.
+func (s *ContractService) GetByUser(ctx context.Context, userID uuid.UUID, status domain.ContractStatus) ([]*domain.ContractHistory, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidInput
+	}
 	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
 		return nil, err
 	}
-	return s.contractRepo.GetContractsByUserID(ctx, userID)
+	contracts, err := s.contractRepo.GetContractsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	histories := make([]*domain.ContractHistory, 0, len(contracts))
+	for _, c := range contracts {
+		if status != "" && c.Status != status {
+			continue
+		}
+		histories = append(histories, c.History(now))
+	}
+	return histories, nil
 }
 
-func (s *ContractService) Update(ctx context.Context, id uuid.UUID, startDate time.Time, endDate *time.Time, contractType domain.ContractType, position string, salary float64) (*domain.Contract, error) {
-	contract, err := s.contractRepo.GetContractByID(ctx, id)
+// GetCurrent returns the contract in force today with its amended terms.
+func (s *ContractService) GetCurrent(ctx context.Context, userID uuid.UUID) (*domain.Contract, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	contracts, err := s.contractRepo.GetContractsByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use validation from NewContract or ad-hoc?
-	// Let's reuse logic by creating a dummy or just checking manually.
-	// Re-validating critical rules:
-	if contractType == domain.ContractTypeIndefinite {
-		endDate = nil
-	} else {
-		if endDate == nil {
-			return nil, domain.ErrInvalidInput // or specific error "End date required"
-		}
-		if endDate.Before(startDate) {
-			return nil, domain.ErrInvalidInput
-		}
+	contract := domain.CurrentContract(contracts, time.Now())
+	if contract == nil {
+		return nil, domain.ErrNotFound
 	}
+	return contract, nil
+}
 
-	contract.StartDate = startDate
-	contract.EndDate = endDate
-	contract.Type = contractType
-	contract.Position = position
-	contract.Salary = salary
-	contract.UpdatedAt = time.Now()
+// Amend changes the terms of a contract from effectiveDate on. The previous
+// terms are kept so the history of the contract is never lost.
+func (s *ContractService) Amend(ctx context.Context, id uuid.UUID, effectiveDate time.Time, changes domain.ContractChanges, reason string, authorID uuid.UUID) (*domain.ContractAmendment, error) {
+	contract, err := s.contractRepo.GetContractByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.contractRepo.UpdateContract(ctx, contract); err != nil {
+	now := time.Now()
+	amendment, err := contract.Amend(effectiveDate, changes, reason, authorID, now)
+	if err != nil {
 		return nil, err
 	}
-	return contract, nil
+	if err := s.contractRepo.CreateContractAmendment(ctx, amendment); err != nil {
+		return nil, err
+	}
+	// Extensions and conversions may reopen or end the contract right away
+	if _, err := s.refreshStatus(ctx, contract, now); err != nil {
+		return nil, err
+	}
+	return amendment, nil
 }
 
+// Delete removes a contract entered by mistake. Amended contracts keep their
+// history and are terminated instead.
 func (s *ContractService) Delete(ctx context.Context, id uuid.UUID) error {
+	contract, err := s.contractRepo.GetContractByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(contract.Amendments) > 0 {
+		return domain.ErrContractHasAmendments
+	}
 	return s.contractRepo.DeleteContract(ctx, id)
 }
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/fuenr/myteam/internal/domain"
 	"github.com/fuenr/myteam/internal/port"
@@ -11,13 +14,15 @@ type DashboardService struct {
 	companyRepo  port.CompanyRepository
 	userRepo     port.UserRepository
 	contractRepo port.ContractRepository
+	overtime     *OvertimeService
 }
 
-func NewDashboardService(companyRepo port.CompanyRepository, userRepo port.UserRepository, contractRepo port.ContractRepository) *DashboardService {
+func NewDashboardService(companyRepo port.CompanyRepository, userRepo port.UserRepository, contractRepo port.ContractRepository, overtime *OvertimeService) *DashboardService {
 	return &DashboardService{
 		companyRepo:  companyRepo,
 		userRepo:     userRepo,
 		contractRepo: contractRepo,
+		overtime:     overtime,
 	}
 }
 
@@ -37,11 +42,19 @@ func (s *DashboardService) GetStats(ctx context.Context) (*domain.DashboardStats
 		return nil, err
 	}
 
+	year := time.Now().Year()
+	overtime, err := s.overtime.GetTotals(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.DashboardStatsResponse{
 		DisplayStats: []domain.StatItem{
 			{Title: "Total Users", Value: userCount, Type: "users"},
 			{Title: "Active Companies", Value: companyCount, Type: "companies"},
 			{Title: "Annual Payroll", Value: int64(totalSalaries), Type: "salaries"},
+			{Title: fmt.Sprintf("Overtime Hours %d", year), Value: int64(math.Round(overtime.OvertimeHours)), Type: "overtime"},
+			{Title: "Over Overtime Cap", Value: overtime.UsersOverCap, Type: "overtime_cap"},
 		},
 	}, nil
 }
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/ical"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type HolidayService struct {
+	repo        port.HolidayRepository
+	companyRepo port.CompanyRepository
+	userRepo    port.UserRepository
+}
+
+func NewHolidayService(repo port.HolidayRepository, companyRepo port.CompanyRepository, userRepo port.UserRepository) *HolidayService {
+	return &HolidayService{
+		repo:        repo,
+		companyRepo: companyRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *HolidayService) CreateCalendar(ctx context.Context, companyID uuid.UUID, name string) (*domain.HolidayCalendar, error) {
+	if _, err := s.companyRepo.GetCompanyByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	calendar, err := domain.NewHolidayCalendar(companyID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *HolidayService) GetCalendarsByCompany(ctx context.Context, companyID uuid.UUID) ([]*domain.HolidayCalendar, error) {
+	return s.repo.GetCalendarsByCompanyID(ctx, companyID)
+}
+
+func (s *HolidayService) UpdateCalendar(ctx context.Context, companyID, id uuid.UUID, name string) (*domain.HolidayCalendar, error) {
+	if name == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	calendar, err := s.getCalendar(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar.Name = name
+	calendar.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateCalendar(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *HolidayService) DeleteCalendar(ctx context.Context, companyID, id uuid.UUID) error {
+	if _, err := s.getCalendar(ctx, companyID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteCalendar(ctx, id)
+}
+
+type HolidayInput struct {
+	Date  string              `json:"date"` // Format YYYY-MM-DD
+	Name  string              `json:"name"`
+	Scope domain.HolidayScope `json:"scope"`
+}
+
+func (s *HolidayService) AddHolidays(ctx context.Context, companyID, calendarID uuid.UUID, inputs []HolidayInput) ([]*domain.Holiday, error) {
+	if _, err := s.getCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+
+	holidays := make([]*domain.Holiday, 0, len(inputs))
+	for _, in := range inputs {
+		date, err := time.Parse("2006-01-02", in.Date)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		holiday, err := domain.NewHoliday(calendarID, date, in.Name, in.Scope)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	if err := s.repo.SaveHolidays(ctx, holidays); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// ImportICal adds every day covered by the events of an iCalendar file to the
+// calendar, using the event summary as the holiday name.
+func (s *HolidayService) ImportICal(ctx context.Context, companyID, calendarID uuid.UUID, scope domain.HolidayScope, r io.Reader) ([]*domain.Holiday, error) {
+	if _, err := s.getCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+
+	events, err := ical.Parse(r)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var holidays []*domain.Holiday
+	for _, e := range events {
+		name := e.Summary
+		if name == "" {
+			name = "Festivo"
+		}
+		for _, day := range e.Days() {
+			holiday, err := domain.NewHoliday(calendarID, day, name, scope)
+			if err != nil {
+				return nil, err
+			}
+			holidays = append(holidays, holiday)
+		}
+	}
+
+	if err := s.repo.SaveHolidays(ctx, holidays); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (s *HolidayService) GetHolidays(ctx context.Context, companyID, calendarID uuid.UUID, year int) ([]*domain.Holiday, error) {
+	if _, err := s.getCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+	from, to := domain.YearBounds(year)
+	return s.repo.GetHolidays(ctx, calendarID, from, to)
+}
+
+func (s *HolidayService) DeleteHoliday(ctx context.Context, companyID, calendarID, id uuid.UUID) error {
+	if _, err := s.getCalendar(ctx, companyID, calendarID); err != nil {
+		return err
+	}
+	return s.repo.DeleteHoliday(ctx, calendarID, id)
+}
+
+// AssignUser sets the calendar used to count the working days of a user.
+// Both must belong to the given company.
+func (s *HolidayService) AssignUser(ctx context.Context, companyID, calendarID, userID uuid.UUID) error {
+	if _, err := s.getCalendar(ctx, companyID, calendarID); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.CompanyID != companyID {
+		return domain.ErrNotFound
+	}
+	return s.repo.AssignCalendarToUser(ctx, userID, &calendarID)
+}
+
+// userHolidays loads the holidays of the calendar assigned to the user for a
+// year. Users without a calendar only have weekends off.
+func userHolidays(ctx context.Context, repo port.HolidayRepository, userID uuid.UUID, year int) (domain.HolidaySet, error) {
+	calendar, err := repo.GetCalendarByUserID(ctx, userID)
+	if err == domain.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := domain.YearBounds(year)
+	holidays, err := repo.GetHolidays(ctx, calendar.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewHolidaySet(holidays), nil
+}
+
+// getCalendar fetches a calendar and hides those of other companies.
+func (s *HolidayService) getCalendar(ctx context.Context, companyID, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	calendar, err := s.repo.GetCalendarByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.CompanyID != companyID {
+		return nil, domain.ErrNotFound
+	}
+	return calendar, nil
+}
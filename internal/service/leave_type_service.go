@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type LeaveTypeService struct {
+	repo        port.LeaveTypeRepository
+	companyRepo port.CompanyRepository
+}
+
+func NewLeaveTypeService(repo port.LeaveTypeRepository, companyRepo port.CompanyRepository) *LeaveTypeService {
+	return &LeaveTypeService{
+		repo:        repo,
+		companyRepo: companyRepo,
+	}
+}
+
+type LeaveTypeInput struct {
+	Name                 string `json:"name"`
+	Paid                 bool   `json:"paid"`
+	CountsAgainstBalance bool   `json:"counts_against_balance"`
+	RequiresAttachment   bool   `json:"requires_attachment"`
+	RequiresApproval     bool   `json:"requires_approval"`
+}
+
+func (s *LeaveTypeService) Create(ctx context.Context, companyID uuid.UUID, input LeaveTypeInput) (*domain.LeaveType, error) {
+	if _, err := s.companyRepo.GetCompanyByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	leaveType, err := domain.NewLeaveType(companyID, input.Name, input.Paid, input.CountsAgainstBalance, input.RequiresAttachment, input.RequiresApproval)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateLeaveType(ctx, leaveType); err != nil {
+		return nil, err
+	}
+	return leaveType, nil
+}
+
+func (s *LeaveTypeService) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]*domain.LeaveType, error) {
+	return s.repo.GetLeaveTypesByCompanyID(ctx, companyID)
+}
+
+func (s *LeaveTypeService) Update(ctx context.Context, companyID, id uuid.UUID, input LeaveTypeInput) (*domain.LeaveType, error) {
+	if input.Name == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	leaveType, err := s.get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveType.Name = input.Name
+	leaveType.Paid = input.Paid
+	leaveType.CountsAgainstBalance = input.CountsAgainstBalance
+	leaveType.RequiresAttachment = input.RequiresAttachment
+	leaveType.RequiresApproval = input.RequiresApproval
+	leaveType.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateLeaveType(ctx, leaveType); err != nil {
+		return nil, err
+	}
+	return leaveType, nil
+}
+
+func (s *LeaveTypeService) Delete(ctx context.Context, companyID, id uuid.UUID) error {
+	if _, err := s.get(ctx, companyID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteLeaveType(ctx, id)
+}
+
+// get fetches a leave type and hides those of other companies.
+func (s *LeaveTypeService) get(ctx context.Context, companyID, id uuid.UUID) (*domain.LeaveType, error) {
+	leaveType, err := s.repo.GetLeaveTypeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType.CompanyID != companyID {
+		return nil, domain.ErrNotFound
+	}
+	return leaveType, nil
+}
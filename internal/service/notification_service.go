@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	repo port.NotificationRepository
+}
+
+func NewNotificationService(repo port.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+func (s *NotificationService) GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*domain.Notification, error) {
+	return s.repo.GetNotificationsByUserID(ctx, userID, unreadOnly)
+}
+
+// MarkRead marks a notification of the user as read. Notifications of other
+// users are reported as not found.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) (*domain.Notification, error) {
+	n, err := s.repo.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	n.MarkRead(time.Now())
+	if err := s.repo.UpdateNotification(ctx, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// totalsTTL is how long the overtime totals of the dashboard are reused.
+// Working them out builds the yearly report of every user.
+const totalsTTL = 15 * time.Minute
+
+type OvertimeService struct {
+	timeRepo     port.TimeEntryRepository
+	contractRepo port.ContractRepository
+	vacationRepo port.VacationRepository
+	holidayRepo  port.HolidayRepository
+	userRepo     port.UserRepository
+
+	mu     sync.Mutex
+	totals map[totalsKey]cachedTotals
+}
+
+// totalsKey identifies the totals of a year for a company; uuid.Nil for
+// unscoped callers.
+type totalsKey struct {
+	companyID uuid.UUID
+	year      int
+}
+
+type cachedTotals struct {
+	totals    *OvertimeTotals
+	expiresAt time.Time
+}
+
+func NewOvertimeService(timeRepo port.TimeEntryRepository, contractRepo port.ContractRepository, vacationRepo port.VacationRepository, holidayRepo port.HolidayRepository, userRepo port.UserRepository) *OvertimeService {
+	return &OvertimeService{
+		timeRepo:     timeRepo,
+		contractRepo: contractRepo,
+		vacationRepo: vacationRepo,
+		holidayRepo:  holidayRepo,
+		userRepo:     userRepo,
+		totals:       make(map[totalsKey]cachedTotals),
+	}
+}
+
+// GetReport compares the hours worked by a user in a year with their contract.
+func (s *OvertimeService) GetReport(ctx context.Context, userID uuid.UUID, year int) (*domain.OvertimeReport, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.report(ctx, userID, year)
+}
+
+func (s *OvertimeService) report(ctx context.Context, userID uuid.UUID, year int) (*domain.OvertimeReport, error) {
+	contracts, err := s.contractRepo.GetContractsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := domain.YearBounds(year)
+	entries, err := s.timeRepo.GetTimeEntriesByUserID(ctx, userID, domain.RegisterDayStart(from), domain.RegisterDayStart(to.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	vacations, err := s.vacationRepo.GetVacationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var absences []*domain.Vacation
+	for _, v := range vacations {
+		if v.Status == domain.VacationStatusApproved {
+			absences = append(absences, v)
+		}
+	}
+
+	holidays, err := userHolidays(ctx, s.holidayRepo, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewOvertimeReport(userID, year, contracts, entries, absences, holidays, time.Now()), nil
+}
+
+// OvertimeTotals adds up the overtime of every user who clocked in during the year.
+type OvertimeTotals struct {
+	OvertimeHours float64
+	UsersOverCap  int64
+}
+
+// GetTotals returns the totals of the company the caller is scoped to. They
+// are cached for totalsTTL.
+func (s *OvertimeService) GetTotals(ctx context.Context, year int) (*OvertimeTotals, error) {
+	key := totalsKey{year: year}
+	key.companyID, _ = domain.CompanyScope(ctx)
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.totals[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.totals, nil
+	}
+
+	totals, err := s.computeTotals(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.totals[key] = cachedTotals{totals: totals, expiresAt: now.Add(totalsTTL)}
+	s.mu.Unlock()
+	return totals, nil
+}
+
+// forgetTotals drops the cached totals of a year so corrections to the time
+// register show up on the dashboard straight away.
+func (s *OvertimeService) forgetTotals(year int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.totals {
+		if key.year == year {
+			delete(s.totals, key)
+		}
+	}
+}
+
+func (s *OvertimeService) computeTotals(ctx context.Context, year int) (*OvertimeTotals, error) {
+	from, to := domain.YearBounds(year)
+	userIDs, err := s.timeRepo.GetUserIDsWithTimeEntries(ctx, domain.RegisterDayStart(from), domain.RegisterDayStart(to.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &OvertimeTotals{}
+	for _, id := range userIDs {
+		report, err := s.report(ctx, id, year)
+		if err != nil {
+			return nil, err
+		}
+		totals.OvertimeHours += report.OvertimeHours
+		if report.ExceedsCap {
+			totals.UsersOverCap++
+		}
+	}
+	return totals, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type PayrollService struct {
+	contractRepo port.ContractRepository
+	payslipRepo  port.PayslipRepository
+	userRepo     port.UserRepository
+	companyRepo  port.CompanyRepository
+	taxTables    domain.TaxTables
+}
+
+func NewPayrollService(contractRepo port.ContractRepository, payslipRepo port.PayslipRepository, userRepo port.UserRepository, companyRepo port.CompanyRepository, taxTables domain.TaxTables) *PayrollService {
+	return &PayrollService{
+		contractRepo: contractRepo,
+		payslipRepo:  payslipRepo,
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		taxTables:    taxTables,
+	}
+}
+
+// LoadTaxTables reads a JSON array of yearly tables from path and adds them
+// to the given ones, replacing the tables of the same year.
+func LoadTaxTables(path string, tables domain.TaxTables) (domain.TaxTables, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var loaded []*domain.TaxTable
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, err
+	}
+
+	merged := make(domain.TaxTables, len(tables)+len(loaded))
+	for year, t := range tables {
+		merged[year] = t
+	}
+	for _, t := range loaded {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("invalid tax table for year %d", t.Year)
+		}
+		merged[t.Year] = t
+	}
+	return merged, nil
+}
+
+type PayrollSimulationInput struct {
+	Year            int // Terms in force today, or on December 31 of a past year
+	PaymentsPerYear int // 12 or 14; 0 means 14
+	// ContributionGroup overrides the group of the contract when not 0
+	ContributionGroup int
+}
+
+// Simulate estimates the monthly gross, contributions, IRPF and net pay of a contract.
+func (s *PayrollService) Simulate(ctx context.Context, contractID uuid.UUID, input PayrollSimulationInput) (*domain.PayrollSimulation, error) {
+	contract, err := s.contractRepo.GetContractByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	date := now
+	if input.Year != 0 && input.Year != now.Year() {
+		date = time.Date(input.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+	if input.PaymentsPerYear == 0 {
+		input.PaymentsPerYear = 14
+	}
+	group := input.ContributionGroup
+	if group == 0 {
+		group = contract.ContributionGroup
+	}
+
+	table, err := s.taxTable(date.Year())
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPayrollSimulation(contract, date, group, input.PaymentsPerYear, table)
+}
+
+func (s *PayrollService) taxTable(year int) (*domain.TaxTable, error) {
+	table, ok := s.taxTables.ForYear(year)
+	if !ok {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "year", Message: "no tax table for this year"}}}
+	}
+	return table, nil
+}
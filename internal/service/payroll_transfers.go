@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/sepa"
+	"github.com/google/uuid"
+)
+
+// GetBankAccount returns the salary account of a user; IBAN is empty when
+// none has been set.
+func (s *PayrollService) GetBankAccount(ctx context.Context, userID uuid.UUID) (*domain.BankAccount, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BankAccount{IBAN: user.IBAN}, nil
+}
+
+func (s *PayrollService) SetBankAccount(ctx context.Context, userID uuid.UUID, iban string) (*domain.BankAccount, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := domain.NewBankAccount(iban, "")
+	if err != nil {
+		return nil, err
+	}
+
+	user.IBAN = account.IBAN
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetCompanyBankAccount returns the account salaries are paid from.
+func (s *PayrollService) GetCompanyBankAccount(ctx context.Context, companyID uuid.UUID) (*domain.BankAccount, error) {
+	company, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BankAccount{IBAN: company.IBAN, BIC: company.BIC}, nil
+}
+
+func (s *PayrollService) SetCompanyBankAccount(ctx context.Context, companyID uuid.UUID, iban, bic string) (*domain.BankAccount, error) {
+	company, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := domain.NewBankAccount(iban, bic)
+	if err != nil {
+		return nil, err
+	}
+
+	company.IBAN = account.IBAN
+	company.BIC = account.BIC
+	company.UpdatedAt = time.Now()
+	if err := s.companyRepo.UpdateCompany(ctx, company); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// ExportPayrollTransfers builds the SEPA credit transfer batch (pain.001)
+// paying the net amount of every payslip the company issued for the month.
+// The company and every paid employee must have an IBAN.
+func (s *PayrollService) ExportPayrollTransfers(ctx context.Context, companyID uuid.UUID, month, executionDate time.Time) ([]byte, error) {
+	company, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	payslips, err := s.payslipRepo.GetPayslipsByCompanyID(ctx, companyID, month.Format("2006-01"))
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	now := time.Now()
+	v := &domain.ValidationError{}
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC); executionDate.Before(today) {
+		v.Add("execution_date", "must not be in the past")
+	}
+	if company.IBAN == "" {
+		v.Add("company", "has no bank account")
+	}
+	var payments []sepa.Payment
+	for _, p := range payslips {
+		if p.NetPay <= 0 {
+			continue
+		}
+		u, ok := byID[p.UserID]
+		if !ok {
+			continue
+		}
+		if u.IBAN == "" {
+			v.Add("users", u.Name+" has no bank account")
+			continue
+		}
+		payments = append(payments, sepa.Payment{
+			EndToEndID:  strings.ReplaceAll(p.ID.String(), "-", ""),
+			Amount:      p.NetPay,
+			Creditor:    sepa.Party{Name: u.Name, IBAN: u.IBAN},
+			Remittance:  "Nomina " + p.Period,
+			PurposeCode: "SALA",
+		})
+	}
+	if len(payments) == 0 && len(v.Fields) == 0 {
+		v.Add("month", "has no net pay to transfer")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	transfer := &sepa.CreditTransfer{
+		MessageID:     "NOM-" + month.Format("200601") + "-" + now.Format("20060102150405"),
+		CreatedAt:     now,
+		InitiatorName: company.Name,
+		InitiatorID:   company.CIF,
+		ExecutionDate: executionDate,
+		Debtor:        sepa.Party{Name: company.Name, IBAN: company.IBAN, BIC: company.BIC},
+		Payments:      payments,
+	}
+	return transfer.Marshal()
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/pdf"
+	"github.com/google/uuid"
+)
+
+// PayslipRun is the result of issuing the payslips of a company for a month.
+type PayslipRun struct {
+	Period  string            `json:"period"`
+	Issued  []*domain.Payslip `json:"issued"`
+	Skipped []PayslipSkip     `json:"skipped"`
+}
+
+// PayslipSkip tells why the payslip of a contract was not issued.
+type PayslipSkip struct {
+	UserID     uuid.UUID `json:"user_id"`
+	ContractID uuid.UUID `json:"contract_id"`
+	Reason     string    `json:"reason"`
+}
+
+// IssuePayslips computes, renders and archives the payslip of every contract
+// of the company employees covering the month. Payslips already issued are
+// skipped, so the run can be repeated for late hires or fixed contracts.
+func (s *PayrollService) IssuePayslips(ctx context.Context, companyID uuid.UUID, month time.Time, paymentsPerYear int, issuedBy uuid.UUID) (*PayslipRun, error) {
+	now := time.Now()
+	if month.After(now) {
+		return nil, domain.ErrInvalidInput
+	}
+	if paymentsPerYear == 0 {
+		paymentsPerYear = 14
+	}
+	company, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	table, err := s.taxTable(month.Year())
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &PayslipRun{Period: month.Format("2006-01"), Issued: []*domain.Payslip{}, Skipped: []PayslipSkip{}}
+	for _, u := range users {
+		contracts, err := s.contractRepo.GetContractsByUserID(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range contracts {
+			payslip, ok, err := domain.NewPayslip(companyID, c, month, paymentsPerYear, table, issuedBy, now)
+			var validationErr *domain.ValidationError
+			if errors.As(err, &validationErr) {
+				run.Skipped = append(run.Skipped, PayslipSkip{UserID: u.ID, ContractID: c.ID, Reason: validationErr.Fields[0].Message})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			document, err := renderPayslipPDF(company, u, payslip)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.payslipRepo.CreatePayslip(ctx, payslip, document); err != nil {
+				if err == domain.ErrDuplicate {
+					run.Skipped = append(run.Skipped, PayslipSkip{UserID: u.ID, ContractID: c.ID, Reason: "already issued"})
+					continue
+				}
+				return nil, err
+			}
+			run.Issued = append(run.Issued, payslip)
+		}
+	}
+	return run, nil
+}
+
+// GetPayslips returns the payslips of a user, newest first.
+func (s *PayrollService) GetPayslips(ctx context.Context, userID uuid.UUID) ([]*domain.Payslip, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	payslips, err := s.payslipRepo.GetPayslipsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if payslips == nil {
+		payslips = []*domain.Payslip{}
+	}
+	return payslips, nil
+}
+
+// GetPayslip returns a payslip of the user with its lines.
+func (s *PayrollService) GetPayslip(ctx context.Context, userID, id uuid.UUID) (*domain.Payslip, error) {
+	payslip, err := s.payslipRepo.GetPayslipByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payslip.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return payslip, nil
+}
+
+// GetPayslipDocument returns the archived PDF of a payslip of the user.
+func (s *PayrollService) GetPayslipDocument(ctx context.Context, userID, id uuid.UUID) (*domain.Payslip, []byte, error) {
+	payslip, err := s.GetPayslip(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := s.payslipRepo.GetPayslipDocument(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payslip, document, nil
+}
+
+func renderPayslipPDF(company *domain.Company, user *domain.User, p *domain.Payslip) ([]byte, error) {
+	const (
+		left, right = 50.0, 545.0
+		rowHeight   = 16.0
+	)
+	doc := pdf.New("Nómina " + p.Period + " - " + user.Name)
+	page := doc.AddPage()
+
+	page.Text(left, 60, 16, true, "Recibo de salarios")
+	page.Text(left, 85, 10, false, fmt.Sprintf("Empresa: %s (CIF %s)", company.Name, company.CIF))
+	page.Text(left, 100, 10, false, fmt.Sprintf("Trabajador: %s <%s>", user.Name, user.Email))
+	page.Text(left, 115, 10, false, fmt.Sprintf("Puesto: %s - %s - Grupo de cotización %d", p.Position, p.ContractType, p.ContributionGroup))
+	page.Text(left, 130, 10, false, fmt.Sprintf("Periodo: %s a %s (%d días)", p.PeriodStart.Format("02/01/2006"), p.PeriodEnd.Format("02/01/2006"), p.Days))
+
+	y := 160.0
+	section := func(title string, lines []domain.Contribution, totalLabel string, total float64) {
+		page.Text(left, y, 10, true, title)
+		page.TextRight(right-160, y, 9, true, "Base")
+		page.TextRight(right-80, y, 9, true, "Tipo (%)")
+		page.TextRight(right, y, 9, true, "Importe")
+		page.Line(left, y+4, right, y+4)
+		y += rowHeight
+		for _, l := range lines {
+			page.Text(left, y, 9, false, l.Concept)
+			if l.Rate != 0 {
+				page.TextRight(right-160, y, 9, false, euros(l.Base))
+				page.TextRight(right-80, y, 9, false, fmt.Sprintf("%.2f", l.Rate))
+			}
+			page.TextRight(right, y, 9, false, euros(l.Amount))
+			y += rowHeight
+		}
+		page.Line(left, y-12, right, y-12)
+		page.Text(left, y, 9, true, totalLabel)
+		page.TextRight(right, y, 9, true, euros(total))
+		y += 2 * rowHeight
+	}
+
+	section("Devengos", p.Earnings, "Total devengado", p.GrossPay)
+	section("Deducciones", p.Deductions, "Total a deducir", p.TotalDeductions)
+	page.Text(left, y, 12, true, "Líquido a percibir")
+	page.TextRight(right, y, 12, true, euros(p.NetPay))
+	y += 3 * rowHeight
+	section("Aportación de la empresa", p.EmployerContributions, "Coste total para la empresa", p.EmployerCost)
+
+	page.Text(left, y, 8, false, fmt.Sprintf("Base de cotización: %s. Emitida el %s.", euros(p.ContributionBase), p.CreatedAt.Format("02/01/2006")))
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func euros(amount float64) string {
+	return fmt.Sprintf("%.2f €", amount)
+}
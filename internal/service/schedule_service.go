@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type ScheduleService struct {
+	repo         port.ScheduleRepository
+	userRepo     port.UserRepository
+	vacationRepo port.VacationRepository
+}
+
+func NewScheduleService(repo port.ScheduleRepository, userRepo port.UserRepository, vacationRepo port.VacationRepository) *ScheduleService {
+	return &ScheduleService{
+		repo:         repo,
+		userRepo:     userRepo,
+		vacationRepo: vacationRepo,
+	}
+}
+
+type ScheduleTemplateInput struct {
+	Name string                `json:"name"`
+	Days []domain.ShiftPattern `json:"days"` // From Monday, 7 per week of the rotation
+}
+
+func (s *ScheduleService) CreateTemplate(ctx context.Context, companyID uuid.UUID, input ScheduleTemplateInput) (*domain.ScheduleTemplate, error) {
+	template, err := domain.NewScheduleTemplate(companyID, input.Name, input.Days)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateScheduleTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *ScheduleService) GetTemplates(ctx context.Context, companyID uuid.UUID) ([]*domain.ScheduleTemplate, error) {
+	return s.repo.GetScheduleTemplatesByCompanyID(ctx, companyID)
+}
+
+func (s *ScheduleService) DeleteTemplate(ctx context.Context, companyID, id uuid.UUID) error {
+	return s.repo.DeleteScheduleTemplate(ctx, companyID, id)
+}
+
+type ScheduleAssignmentInput struct {
+	TemplateID uuid.UUID `json:"template_id"`
+	StartDate  string    `json:"start_date"`         // Format YYYY-MM-DD
+	EndDate    *string   `json:"end_date,omitempty"` // Format YYYY-MM-DD, omitted while in force
+}
+
+// AssignSchedule puts a user on a template. A previous open-ended assignment
+// is ended the day before the new one starts; any other overlap is refused.
+// The resulting plan must keep the minimum rest between shifts.
+func (s *ScheduleService) AssignSchedule(ctx context.Context, userID uuid.UUID, input ScheduleAssignmentInput) (*domain.ScheduleAssignment, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.repo.GetScheduleTemplateByID(ctx, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.CompanyID != user.CompanyID {
+		return nil, domain.ErrNotFound
+	}
+
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	var endDate *time.Time
+	if input.EndDate != nil {
+		d, err := time.Parse("2006-01-02", *input.EndDate)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		endDate = &d
+	}
+
+	assignment, err := domain.NewScheduleAssignment(userID, template.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetScheduleAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var superseded *domain.ScheduleAssignment
+	for _, a := range existing {
+		if !a.Overlaps(assignment) {
+			continue
+		}
+		if a.EndDate != nil || !a.StartDate.Before(startDate) || superseded != nil {
+			return nil, domain.ErrScheduleOverlap
+		}
+		dayBefore := startDate.AddDate(0, 0, -1)
+		a.EndDate = &dayBefore
+		superseded = a
+	}
+
+	templates, err := s.repo.GetScheduleTemplatesByCompanyID(ctx, user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.ScheduleTemplate, len(templates))
+	for _, t := range templates {
+		byID[t.ID] = t
+	}
+
+	// Check a whole cycle of the new template and the day on each side of it,
+	// where it meets the neighbouring assignments.
+	to := startDate.AddDate(0, 0, len(template.Days))
+	if endDate != nil && endDate.Before(to) {
+		to = *endDate
+	}
+	shifts := domain.PlanShifts(append(existing, assignment), byID, startDate.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if violations := domain.CheckRest(shifts); len(violations) > 0 {
+		return nil, &domain.RestViolationError{Violations: violations}
+	}
+
+	if superseded != nil {
+		if err := s.repo.UpdateScheduleAssignment(ctx, superseded); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.CreateScheduleAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (s *ScheduleService) GetAssignments(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduleAssignment, error) {
+	return s.repo.GetScheduleAssignmentsByUserID(ctx, userID)
+}
+
+func (s *ScheduleService) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteScheduleAssignment(ctx, id)
+}
+
+// GetRoster returns the shifts of every employee of the company in the week
+// starting on the Monday weekStart, with approved absences as gaps. Viewers
+// without PermAbsenceReadDetails see them as in the team calendar.
+func (s *ScheduleService) GetRoster(ctx context.Context, companyID uuid.UUID, weekStart time.Time, viewer domain.Role) (*domain.Roster, error) {
+	if weekStart.Weekday() != time.Monday {
+		return nil, domain.ErrInvalidInput
+	}
+	weekEnd := weekStart.AddDate(0, 0, 6)
+
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.GetScheduleAssignmentsByCompanyID(ctx, companyID, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := s.repo.GetScheduleTemplatesByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.vacationRepo.GetAbsencesByCompanyID(ctx, companyID, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.Can(domain.PermAbsenceReadDetails) {
+		absences = domain.RedactAbsences(absences)
+	}
+
+	return domain.NewRoster(companyID, weekStart, users, assignments, templates, absences), nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// SettlementService terminates contracts and computes their final
+// settlement (finiquito).
+type SettlementService struct {
+	contractRepo    port.ContractRepository
+	payslipRepo     port.PayslipRepository
+	vacationService *VacationService
+}
+
+func NewSettlementService(contractRepo port.ContractRepository, payslipRepo port.PayslipRepository, vacationService *VacationService) *SettlementService {
+	return &SettlementService{
+		contractRepo:    contractRepo,
+		payslipRepo:     payslipRepo,
+		vacationService: vacationService,
+	}
+}
+
+type TerminationInput struct {
+	Date            time.Time // Last day worked
+	Reason          domain.TerminationReason
+	PaymentsPerYear int // 12 or 14; 0 means 14
+	AuthorID        uuid.UUID
+}
+
+// Terminate closes the contract on the given date and stores its settlement.
+// The vacation balance is computed with the contract already ended, so only
+// the days accrued until the last day are paid.
+func (s *SettlementService) Terminate(ctx context.Context, contractID uuid.UUID, input TerminationInput) (*domain.ContractTermination, error) {
+	if input.PaymentsPerYear == 0 {
+		input.PaymentsPerYear = 14
+	}
+	contract, err := s.contractRepo.GetContractByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	termination, err := contract.Terminate(input.Date, input.Reason, input.AuthorID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.contractRepo.GetContractsByUserID(ctx, contract.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range history {
+		if c.ID == contract.ID {
+			history[i] = contract
+		}
+	}
+	balance, _, err := s.vacationService.balanceOf(ctx, contract.UserID, termination.Date.Year(), history)
+	if err != nil {
+		return nil, err
+	}
+	payslips, err := s.payslipRepo.GetPayslipsByUserID(ctx, contract.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := termination.Settle(contract, history, payslips, input.PaymentsPerYear, balance); err != nil {
+		return nil, err
+	}
+
+	contract.Status, contract.UpdatedAt = contract.StatusOn(now), now
+	if err := s.contractRepo.CreateContractTermination(ctx, contract); err != nil {
+		return nil, err
+	}
+	return termination, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+type TimeEntryService struct {
+	repo        port.TimeEntryRepository
+	userRepo    port.UserRepository
+	companyRepo port.CompanyRepository
+	overtime    *OvertimeService
+}
+
+func NewTimeEntryService(repo port.TimeEntryRepository, userRepo port.UserRepository, companyRepo port.CompanyRepository, overtime *OvertimeService) *TimeEntryService {
+	return &TimeEntryService{
+		repo:        repo,
+		userRepo:    userRepo,
+		companyRepo: companyRepo,
+		overtime:    overtime,
+	}
+}
+
+func (s *TimeEntryService) ClockIn(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetOpenTimeEntry(ctx, userID); err == nil {
+		return nil, domain.ErrAlreadyClockedIn
+	} else if err != domain.ErrNotFound {
+		return nil, err
+	}
+
+	entry := domain.NewTimeEntry(userID, time.Now())
+	if err := s.repo.CreateTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *TimeEntryService) ClockOut(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	return s.updateOpen(ctx, userID, (*domain.TimeEntry).Close)
+}
+
+func (s *TimeEntryService) StartBreak(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	return s.updateOpen(ctx, userID, (*domain.TimeEntry).StartBreak)
+}
+
+func (s *TimeEntryService) EndBreak(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	return s.updateOpen(ctx, userID, (*domain.TimeEntry).EndBreak)
+}
+
+// updateOpen applies a change to the entry the user is clocked in on.
+func (s *TimeEntryService) updateOpen(ctx context.Context, userID uuid.UUID, apply func(*domain.TimeEntry, time.Time) error) (*domain.TimeEntry, error) {
+	entry, err := s.repo.GetOpenTimeEntry(ctx, userID)
+	if err == domain.ErrNotFound {
+		return nil, domain.ErrNotClockedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(entry, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetEntries returns the entries clocked in between from and to (both inclusive).
+func (s *TimeEntryService) GetEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.TimeEntry, error) {
+	if from.After(to) {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.GetTimeEntriesByUserID(ctx, userID, domain.RegisterDayStart(from), domain.RegisterDayStart(to.AddDate(0, 0, 1)))
+}
+
+// EditTimeEntryInput corrects the clock times of an entry. Times use RFC 3339;
+// a nil ClockOut leaves the entry open.
+type EditTimeEntryInput struct {
+	ID       uuid.UUID `json:"-"`
+	EditorID uuid.UUID `json:"-"`
+	ClockIn  string    `json:"clock_in"`
+	ClockOut *string   `json:"clock_out,omitempty"`
+	Reason   string    `json:"reason"`
+}
+
+func (s *TimeEntryService) EditEntry(ctx context.Context, input EditTimeEntryInput) (*domain.TimeEntry, error) {
+	clockIn, err := time.Parse(time.RFC3339, input.ClockIn)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	var clockOut *time.Time
+	if input.ClockOut != nil {
+		t, err := time.Parse(time.RFC3339, *input.ClockOut)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		clockOut = &t
+	}
+
+	entry, err := s.repo.GetTimeEntryByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	year := entry.ClockIn.In(domain.RegisterLocation).Year()
+	edit, err := entry.Edit(clockIn, clockOut, input.EditorID, input.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EditTimeEntry(ctx, entry, edit); err != nil {
+		return nil, err
+	}
+	// The correction may move the entry to another year
+	s.overtime.forgetTotals(year)
+	s.overtime.forgetTotals(entry.ClockIn.In(domain.RegisterLocation).Year())
+	return entry, nil
+}
+
+func (s *TimeEntryService) GetEdits(ctx context.Context, entryID uuid.UUID) ([]*domain.TimeEntryEdit, error) {
+	if _, err := s.repo.GetTimeEntryByID(ctx, entryID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTimeEntryEdits(ctx, entryID)
+}
+
+// GetMonthlySummary returns the daily start and end times and the hours worked
+// by a user in the month.
+func (s *TimeEntryService) GetMonthlySummary(ctx context.Context, userID uuid.UUID, month time.Time) (*domain.TimeSummary, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, userID, month)
+}
+
+// summary builds the register of a month, with the overtime of the year's
+// report so both give the same figures.
+func (s *TimeEntryService) summary(ctx context.Context, userID uuid.UUID, month time.Time) (*domain.TimeSummary, error) {
+	from, to := domain.RegisterMonthBounds(month)
+	entries, err := s.repo.GetTimeEntriesByUserID(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	overtime, err := s.overtime.report(ctx, userID, month.Year())
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewTimeSummary(userID, month, entries, overtime, time.Now()), nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// timeStore keeps the time entries of one user in memory. The embedded ports
+// are nil: calling anything not implemented here panics.
+type timeStore struct {
+	port.TimeEntryRepository
+	port.ContractRepository
+	port.VacationRepository
+	port.HolidayRepository
+	port.UserRepository
+
+	entries   []*domain.TimeEntry
+	contracts []*domain.Contract
+}
+
+func (s *timeStore) GetTimeEntryByID(ctx context.Context, id uuid.UUID) (*domain.TimeEntry, error) {
+	for _, e := range s.entries {
+		if e.ID == id {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *timeStore) GetTimeEntriesByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.TimeEntry, error) {
+	var found []*domain.TimeEntry
+	for _, e := range s.entries {
+		if e.UserID == userID && !e.ClockIn.Before(from) && e.ClockIn.Before(to) {
+			found = append(found, e)
+		}
+	}
+	return found, nil
+}
+
+func (s *timeStore) GetUserIDsWithTimeEntries(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, e := range s.entries {
+		if !seen[e.UserID] && !e.ClockIn.Before(from) && e.ClockIn.Before(to) {
+			seen[e.UserID] = true
+			ids = append(ids, e.UserID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *timeStore) EditTimeEntry(ctx context.Context, entry *domain.TimeEntry, edit *domain.TimeEntryEdit) error {
+	for i, e := range s.entries {
+		if e.ID == entry.ID {
+			s.entries[i] = entry
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (s *timeStore) GetContractsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contract, error) {
+	return s.contracts, nil
+}
+
+func (s *timeStore) GetVacationsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Vacation, error) {
+	return nil, nil
+}
+
+func (s *timeStore) GetCalendarByUserID(ctx context.Context, userID uuid.UUID) (*domain.HolidayCalendar, error) {
+	return nil, domain.ErrNotFound
+}
+
+func newTimeStoreServices(store *timeStore) (*TimeEntryService, *OvertimeService) {
+	overtime := NewOvertimeService(store, store, store, store, store)
+	return NewTimeEntryService(store, store, nil, overtime), overtime
+}
+
+func TestEditEntryRefreshesOvertimeTotals(t *testing.T) {
+	// A past year, so every day of it counts
+	year := time.Now().Year() - 1
+	at := func(hour int) time.Time {
+		return time.Date(year, time.March, 4, hour, 0, 0, 0, domain.RegisterLocation)
+	}
+	out := at(11)
+	entry := &domain.TimeEntry{ID: uuid.New(), UserID: uuid.New(), ClockIn: at(9), ClockOut: &out}
+	// Without a contract every hour worked is overtime
+	store := &timeStore{entries: []*domain.TimeEntry{entry}}
+	s, overtime := newTimeStoreServices(store)
+	ctx := context.Background()
+
+	totals, err := overtime.GetTotals(ctx, year)
+	if err != nil {
+		t.Fatalf("GetTotals: %v", err)
+	}
+	if totals.OvertimeHours != 2 {
+		t.Fatalf("overtime = %.2f, want 2", totals.OvertimeHours)
+	}
+
+	clockOut := at(14).Format(time.RFC3339)
+	input := EditTimeEntryInput{ID: entry.ID, EditorID: uuid.New(), ClockIn: at(9).Format(time.RFC3339), ClockOut: &clockOut, Reason: "Olvidó fichar la salida"}
+	if _, err := s.EditEntry(ctx, input); err != nil {
+		t.Fatalf("EditEntry: %v", err)
+	}
+
+	totals, err = overtime.GetTotals(ctx, year)
+	if err != nil {
+		t.Fatalf("GetTotals: %v", err)
+	}
+	if totals.OvertimeHours != 5 {
+		t.Errorf("overtime after the correction = %.2f, want 5", totals.OvertimeHours)
+	}
+}
+
+func TestSummaryDailyOvertime(t *testing.T) {
+	year := time.Now().Year() - 1
+	at := func(day, hour int) time.Time {
+		return time.Date(year, time.March, day, hour, 0, 0, 0, domain.RegisterLocation)
+	}
+	userID := uuid.New()
+	contract := &domain.Contract{ID: uuid.New(), UserID: userID, StartDate: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), Type: domain.ContractTypeIndefinite, WeeklyHours: 40}
+
+	tests := []struct {
+		name      string
+		contracts []*domain.Contract
+		from, to  int
+		want      float64
+	}{
+		{"within the workday", []*domain.Contract{contract}, 9, 17, 0},
+		{"two hours over", []*domain.Contract{contract}, 9, 19, 2},
+		{"without a contract", nil, 9, 17, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The first Wednesday of March
+			day := 1
+			for at(day, 0).Weekday() != time.Wednesday {
+				day++
+			}
+			out := at(day, tt.to)
+			store := &timeStore{
+				entries:   []*domain.TimeEntry{{ID: uuid.New(), UserID: userID, ClockIn: at(day, tt.from), ClockOut: &out}},
+				contracts: tt.contracts,
+			}
+			s, _ := newTimeStoreServices(store)
+
+			summary, err := s.summary(context.Background(), userID, at(1, 0))
+			if err != nil {
+				t.Fatalf("summary: %v", err)
+			}
+			if len(summary.Days) != 1 {
+				t.Fatalf("got %d days, want 1", len(summary.Days))
+			}
+			if got := summary.Days[0].OvertimeHours; got != tt.want {
+				t.Errorf("daily overtime = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/pdf"
+	"github.com/google/uuid"
+)
+
+// Export formats of the monthly time register.
+const (
+	FormatPDF = "pdf"
+	FormatCSV = "csv"
+)
+
+// GetTimeRegister gathers the monthly register of a user.
+func (s *TimeEntryService) GetTimeRegister(ctx context.Context, userID uuid.UUID, month time.Time) (*domain.TimeRegister, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	company, err := s.companyRepo.GetCompanyByID(ctx, user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeRegister(ctx, company, user, month)
+}
+
+func (s *TimeEntryService) timeRegister(ctx context.Context, company *domain.Company, user *domain.User, month time.Time) (*domain.TimeRegister, error) {
+	summary, err := s.summary(ctx, user.ID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	register := &domain.TimeRegister{
+		Company: company,
+		User:    user,
+		Summary: summary,
+	}
+	ack, err := s.repo.GetTimeRegisterAcknowledgement(ctx, user.ID, register.Summary.Month)
+	if err != nil && err != domain.ErrNotFound {
+		return nil, err
+	}
+	register.Acknowledgement = ack
+	return register, nil
+}
+
+// AcknowledgeTimeRegister signs the register of a finished month. Only the
+// employee can sign their own register.
+func (s *TimeEntryService) AcknowledgeTimeRegister(ctx context.Context, userID, actorID uuid.UUID, month time.Time) (*domain.TimeRegisterAcknowledgement, error) {
+	if actorID != userID {
+		return nil, domain.ErrForbidden
+	}
+	ack, err := domain.NewTimeRegisterAcknowledgement(userID, month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTimeRegisterAcknowledgement(ctx, ack); err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// ExportTimeRegister renders the monthly register of a user as PDF or CSV.
+func (s *TimeEntryService) ExportTimeRegister(ctx context.Context, userID uuid.UUID, month time.Time, format string) ([]byte, error) {
+	if format != FormatPDF && format != FormatCSV {
+		return nil, domain.ErrInvalidInput
+	}
+	register, err := s.GetTimeRegister(ctx, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	return renderTimeRegister(register, format)
+}
+
+// ExportCompanyTimeRegisters renders the register of every employee of the
+// company for the month and bundles them in a ZIP archive.
+func (s *TimeEntryService) ExportCompanyTimeRegisters(ctx context.Context, companyID uuid.UUID, month time.Time, format string) ([]byte, error) {
+	if format != FormatPDF && format != FormatCSV {
+		return nil, domain.ErrInvalidInput
+	}
+	company, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, u := range users {
+		register, err := s.timeRegister(ctx, company, u, month)
+		if err != nil {
+			return nil, err
+		}
+		body, err := renderTimeRegister(register, format)
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprintf("registro-jornada-%s-%s-%s.%s", register.Summary.Month, fileSlug(u.Name), u.ID.String()[:8], format)
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(body); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderTimeRegister(register *domain.TimeRegister, format string) ([]byte, error) {
+	if format == FormatCSV {
+		return renderTimeRegisterCSV(register)
+	}
+	return renderTimeRegisterPDF(register)
+}
+
+func renderTimeRegisterCSV(register *domain.TimeRegister) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	s := register.Summary
+
+	w.Write([]string{"Registro de jornada"})
+	w.Write([]string{"Empresa", register.Company.Name, register.Company.CIF})
+	w.Write([]string{"Trabajador", register.User.Name, register.User.Email})
+	w.Write([]string{"Mes", s.Month})
+	w.Write(nil)
+	w.Write([]string{"Fecha", "Tramos", "Pausas (h)", "Horas trabajadas", "Horas extra"})
+	for _, d := range s.Days {
+		w.Write([]string{d.Date, dayShifts(d), hours(d.BreakHours), hours(d.WorkedHours), hours(d.OvertimeHours)})
+	}
+	// The overtime of the month is the one of its weeks, below
+	w.Write([]string{"Total", "", hours(s.BreakHours), hours(s.WorkedHours), ""})
+	w.Write(nil)
+	w.Write([]string{"Semana", "Horas contratadas", "Horas trabajadas", "Horas extra"})
+	for _, wk := range s.Weeks {
+		w.Write([]string{wk.Period, hours(wk.ContractedHours), hours(wk.WorkedHours), hours(wk.OvertimeHours)})
+	}
+	w.Write([]string{"Horas extra del mes", "", "", hours(s.OvertimeHours)})
+	w.Write(nil)
+	w.Write([]string{"Conforme del trabajador", acknowledgement(register)})
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func renderTimeRegisterPDF(register *domain.TimeRegister) ([]byte, error) {
+	const (
+		left, right = 50.0, 545.0
+		rowHeight   = 16.0
+		bottom      = 760.0
+	)
+	s := register.Summary
+	doc := pdf.New("Registro de jornada " + s.Month + " - " + register.User.Name)
+
+	page := doc.AddPage()
+	page.Text(left, 60, 16, true, "Registro de jornada")
+	page.Text(left, 85, 10, false, fmt.Sprintf("Empresa: %s (CIF %s)", register.Company.Name, register.Company.CIF))
+	page.Text(left, 100, 10, false, fmt.Sprintf("Trabajador: %s <%s>", register.User.Name, register.User.Email))
+	page.Text(left, 115, 10, false, "Mes: "+s.Month)
+
+	header := func(y float64) float64 {
+		page.Text(left, y, 9, true, "Fecha")
+		page.Text(left+70, y, 9, true, "Tramos")
+		page.TextRight(right-140, y, 9, true, "Pausas (h)")
+		page.TextRight(right-60, y, 9, true, "Trabajadas (h)")
+		page.TextRight(right, y, 9, true, "Extra (h)")
+		page.Line(left, y+4, right, y+4)
+		return y + rowHeight
+	}
+	row := func(y float64, bold bool, date, shifts, breaks, worked, overtime string) {
+		page.Text(left, y, 9, bold, date)
+		page.Text(left+70, y, 9, bold, shifts)
+		page.TextRight(right-140, y, 9, bold, breaks)
+		page.TextRight(right-60, y, 9, bold, worked)
+		page.TextRight(right, y, 9, bold, overtime)
+	}
+
+	y := header(145)
+	for _, d := range s.Days {
+		if y > bottom {
+			page = doc.AddPage()
+			y = header(60)
+		}
+		row(y, false, d.Date, dayShifts(d), hours(d.BreakHours), hours(d.WorkedHours), hours(d.OvertimeHours))
+		y += rowHeight
+	}
+	page.Line(left, y-12, right, y-12)
+	row(y, true, "Total", "", hours(s.BreakHours), hours(s.WorkedHours), "")
+
+	// Overtime is counted per week against the contract
+	weekHeader := func(y float64) float64 {
+		page.Text(left, y, 9, true, "Semana")
+		page.TextRight(right-160, y, 9, true, "Contratadas (h)")
+		page.TextRight(right-80, y, 9, true, "Trabajadas (h)")
+		page.TextRight(right, y, 9, true, "Extra (h)")
+		page.Line(left, y+4, right, y+4)
+		return y + rowHeight
+	}
+	weekRow := func(y float64, bold bool, label string, contracted, worked, overtime string) {
+		page.Text(left, y, 9, bold, label)
+		page.TextRight(right-160, y, 9, bold, contracted)
+		page.TextRight(right-80, y, 9, bold, worked)
+		page.TextRight(right, y, 9, bold, overtime)
+	}
+	if y > bottom-2*rowHeight {
+		page = doc.AddPage()
+		y = 60
+	} else {
+		y += 2 * rowHeight
+	}
+	y = weekHeader(y)
+	for _, wk := range s.Weeks {
+		if y > bottom {
+			page = doc.AddPage()
+			y = weekHeader(60)
+		}
+		weekRow(y, false, wk.Period, hours(wk.ContractedHours), hours(wk.WorkedHours), hours(wk.OvertimeHours))
+		y += rowHeight
+	}
+	page.Line(left, y-12, right, y-12)
+	weekRow(y, true, "Horas extra del mes", "", "", hours(s.OvertimeHours))
+
+	if y > bottom-60 { // Keep the signature block on one page
+		page = doc.AddPage()
+		y = 60
+	}
+	y += 50
+	page.Text(left, y, 10, true, "Conforme del trabajador")
+	page.Text(left+300, y, 10, true, "Por la empresa")
+	page.Line(left, y+45, left+200, y+45)
+	page.Line(left+300, y+45, right, y+45)
+	page.Text(left, y+60, 8, false, acknowledgement(register))
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// dayShifts lists the clock-in and clock-out times of the day as "09:00-14:00 15:00-18:00".
+func dayShifts(d *domain.DailyTime) string {
+	shifts := make([]string, 0, len(d.Entries))
+	for _, e := range d.Entries {
+		out := "..."
+		if e.ClockOut != nil {
+			out = e.ClockOut.In(domain.RegisterLocation).Format("15:04")
+		}
+		shifts = append(shifts, e.ClockIn.In(domain.RegisterLocation).Format("15:04")+"-"+out)
+	}
+	return strings.Join(shifts, " ")
+}
+
+func acknowledgement(register *domain.TimeRegister) string {
+	if register.Acknowledgement == nil {
+		return "Pendiente de firma"
+	}
+	return "Firmado electrónicamente el " + register.Acknowledgement.AcknowledgedAt.In(domain.RegisterLocation).Format("02/01/2006 15:04")
+}
+
+func hours(h float64) string {
+	return fmt.Sprintf("%.2f", h)
+}
+
+// fileSlug turns a name into something safe to use in a file name.
+func fileSlug(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, name)
+	return strings.Trim(slug, "-")
+}
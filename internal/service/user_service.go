@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"time"
 
 	"github.com/fuenr/myteam/internal/domain"
@@ -22,23 +24,23 @@ func NewUserService(userRepo port.UserRepository, companyRepo port.CompanyReposi
 	}
 }
 
-func (s *UserService) Create(ctx context.Context, companyID uuid.UUID, name, email, password string, role domain.Role) (*domain.User, error) {
-	// Verify company exists
-	if _, err := s.companyRepo.GetCompanyByID(ctx, companyID); err != nil {
+func (s *UserService) Create(ctx context.Context, companyID uuid.UUID, name, email, password string, role domain.Role, department string) (*domain.User, error) {
+	_, invited := domain.CompanyScope(ctx)
+	ctx, err := s.checkCanAddUsers(ctx, companyID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Hash password using bcrypt
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	passwordHash, err := s.hashPassword(password, invited)
 	if err != nil {
 		return nil, err
 	}
-	passwordHash := string(hashedBytes)
 
 	user, err := domain.NewUser(companyID, name, email, passwordHash, role)
 	if err != nil {
 		return nil, err
 	}
+	user.Department = department
 
 	if err := s.userRepo.CreateUser(ctx, user); err != nil {
 		return nil, err
@@ -47,23 +49,25 @@ func (s *UserService) Create(ctx context.Context, companyID uuid.UUID, name, ema
 }
 
 func (s *UserService) BatchCreate(ctx context.Context, companyID uuid.UUID, usersReq []domain.CreateUserRequest) ([]*domain.User, error) {
-	if _, err := s.companyRepo.GetCompanyByID(ctx, companyID); err != nil {
+	_, invited := domain.CompanyScope(ctx)
+	ctx, err := s.checkCanAddUsers(ctx, companyID)
+	if err != nil {
 		return nil, err
 	}
 
 	var users []*domain.User
 
 	for _, req := range usersReq {
-		hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		passwordHash, err := s.hashPassword(req.Password, invited)
 		if err != nil {
 			return nil, err
 		}
-		passwordHash := string(hashedBytes)
 
 		user, err := domain.NewUser(companyID, req.Name, req.Email, passwordHash, req.Role)
 		if err != nil {
 			return nil, err
 		}
+		user.Department = req.Department
 		users = append(users, user)
 	}
 
@@ -74,6 +78,52 @@ func (s *UserService) BatchCreate(ctx context.Context, companyID uuid.UUID, user
 	return users, nil
 }
 
+// hashPassword hashes the password of a new user. Admins, whose users are
+// invited, may leave it empty and invite the user to choose one
+// (AccountService.Invite): until then the account gets a random password
+// nobody knows.
+func (s *UserService) hashPassword(password string, invited bool) (string, error) {
+	if password == "" {
+		if !invited {
+			return "", domain.ErrInvalidInput
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return "", err
+		}
+		password = base64.RawURLEncoding.EncodeToString(secret)
+	}
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedBytes), nil
+}
+
+// checkCanAddUsers verifies the company exists within the scope of ctx and
+// returns ctx scoped to it. Anonymous requests, which have no scope, may only
+// add the first user of a company, its admin, when the company registers.
+func (s *UserService) checkCanAddUsers(ctx context.Context, companyID uuid.UUID) (context.Context, error) {
+	_, scoped := domain.CompanyScope(ctx)
+	if !scoped {
+		ctx = domain.WithCompanyScope(ctx, companyID)
+	}
+	if _, err := s.companyRepo.GetCompanyByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+	if scoped {
+		return ctx, nil
+	}
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		return nil, domain.ErrForbidden
+	}
+	return ctx, nil
+}
+
 func (s *UserService) Get(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	return s.userRepo.GetUserByID(ctx, id)
 }
@@ -82,15 +132,27 @@ func (s *UserService) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]
 	return s.userRepo.GetUsersByCompanyID(ctx, companyID)
 }
 
-func (s *UserService) Update(ctx context.Context, id uuid.UUID, name, email string, role domain.Role) (*domain.User, error) {
+func (s *UserService) Update(ctx context.Context, id uuid.UUID, name, email string, role domain.Role, department string, managerID *uuid.UUID) (*domain.User, error) {
+	if !role.Valid() {
+		return nil, domain.ErrInvalidInput
+	}
 	user, err := s.userRepo.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkManager(ctx, user, managerID); err != nil {
+		return nil, err
+	}
 
+	// Access tokens carry the role: the ones issued with the previous role are revoked
+	if role != user.Role {
+		user.RevokeTokens()
+	}
 	user.Name = name
 	user.Email = email
 	user.Role = role
+	user.Department = department
+	user.ManagerID = managerID
 	user.UpdatedAt = time.Now()
 
 	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
@@ -99,6 +161,28 @@ func (s *UserService) Update(ctx context.Context, id uuid.UUID, name, email stri
 	return user, nil
 }
 
+// checkManager verifies the manager is another user of the same company and
+// that the reporting lines stay a tree: the user cannot manage their managers.
+func (s *UserService) checkManager(ctx context.Context, user *domain.User, managerID *uuid.UUID) error {
+	for id := managerID; id != nil; {
+		if *id == user.ID {
+			return domain.ErrInvalidInput
+		}
+		manager, err := s.userRepo.GetUserByID(ctx, *id)
+		if err != nil {
+			if err == domain.ErrNotFound {
+				return domain.ErrInvalidInput
+			}
+			return err
+		}
+		if manager.CompanyID != user.CompanyID {
+			return domain.ErrInvalidInput
+		}
+		id = manager.ManagerID
+	}
+	return nil
+}
+
 func (s *UserService) Login(ctx context.Context, email, password string) (*domain.User, error) {
 	// 1. Find user by email
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
@@ -113,10 +197,25 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*domai
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
 		return nil, domain.ErrInvalidCredentials
 	}
+	if !user.IsActive() {
+		return nil, domain.ErrInvalidCredentials
+	}
 
 	return user, nil
 }
 
+// Delete removes a user with everything they own. The user is deactivated
+// first, so their tokens stop working even if the delete fails; users whose
+// contract history must be kept stay deactivated and domain.ErrUserHasRecords
+// is returned.
 func (s *UserService) Delete(ctx context.Context, id uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Deactivate(time.Now())
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
 	return s.userRepo.DeleteUser(ctx, id)
 }
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuenr/myteam/internal/auth"
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// userStore keeps users in memory. Deleting a user fails with deleteErr when
+// it is set. The embedded port is nil: calling anything not implemented here
+// panics.
+type userStore struct {
+	port.UserRepository
+
+	users     map[uuid.UUID]*domain.User
+	deleteErr error
+}
+
+func (s *userStore) CreateUser(ctx context.Context, user *domain.User) error {
+	s.users[user.ID] = user
+	return nil
+}
+
+func (s *userStore) GetUsersByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.User, error) {
+	var users []*domain.User
+	for _, u := range s.users {
+		if u.CompanyID == companyID {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (s *userStore) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (s *userStore) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *userStore) UpdateUser(ctx context.Context, user *domain.User) error {
+	if _, ok := s.users[user.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	s.users[user.ID] = user
+	return nil
+}
+
+func (s *userStore) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	if _, ok := s.users[id]; !ok {
+		return domain.ErrNotFound
+	}
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	delete(s.users, id)
+	return nil
+}
+
+// companyStore finds the company only within its scope, like the Postgres
+// queries: a context nobody scoped finds nothing.
+type companyStore struct {
+	port.CompanyRepository
+
+	company *domain.Company
+}
+
+func (s *companyStore) GetCompanyByID(ctx context.Context, id uuid.UUID) (*domain.Company, error) {
+	if scope, _ := domain.CompanyScope(ctx); id != s.company.ID || scope != id {
+		return nil, domain.ErrNotFound
+	}
+	return s.company, nil
+}
+
+func TestUserServiceCreateAnonymous(t *testing.T) {
+	company := &domain.Company{ID: uuid.New(), Name: "Acme"}
+	users := &userStore{users: map[uuid.UUID]*domain.User{}}
+	s := NewUserService(users, &companyStore{company: company})
+	ctx := context.Background()
+
+	// The registration scopes itself to the company it adds the admin to
+	if _, err := s.Create(ctx, company.ID, "Ana", "ana@example.com", "", domain.RoleAdmin, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Create without password err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := s.Create(ctx, company.ID, "Ana", "ana@example.com", "secreto123", domain.RoleAdmin, ""); err != nil {
+		t.Fatalf("Create of the first user: %v", err)
+	}
+	if _, err := s.Create(ctx, company.ID, "Eva", "eva@example.com", "secreto123", domain.RoleAdmin, ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Create of a second user err = %v, want ErrForbidden", err)
+	}
+	if _, err := s.Create(ctx, uuid.New(), "Eva", "eva@example.com", "secreto123", domain.RoleAdmin, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Create in an unknown company err = %v, want ErrNotFound", err)
+	}
+
+	// Admins of another company do not find it
+	other := domain.WithCompanyScope(ctx, uuid.New())
+	if _, err := s.Create(other, company.ID, "Eva", "eva@example.com", "", domain.RoleEmployee, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Create from another company err = %v, want ErrNotFound", err)
+	}
+	// Its own admins may leave the password to the invitation
+	own := domain.WithCompanyScope(ctx, company.ID)
+	if _, err := s.Create(own, company.ID, "Eva", "eva@example.com", "", domain.RoleEmployee, ""); err != nil {
+		t.Errorf("Create by the admin: %v", err)
+	}
+}
+
+func TestUserServiceDelete(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secreto123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errDatabase := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		deleteErr   error
+		wantDeleted bool
+	}{
+		{"no records", nil, true},
+		{"amended contract", domain.ErrUserHasRecords, false},
+		// Access ends even if the delete fails
+		{"database error", errDatabase, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New(), CompanyID: uuid.New(), Email: "ana@example.com", PasswordHash: string(hash), Role: domain.RoleEmployee}
+			store := &userStore{users: map[uuid.UUID]*domain.User{user.ID: user}, deleteErr: tt.deleteErr}
+			s := NewUserService(store, nil)
+			ctx := context.Background()
+
+			if err := s.Delete(ctx, user.ID); !errors.Is(err, tt.deleteErr) {
+				t.Fatalf("Delete err = %v, want %v", err, tt.deleteErr)
+			}
+			kept, err := store.GetUserByID(ctx, user.ID)
+			if deleted := errors.Is(err, domain.ErrNotFound); deleted != tt.wantDeleted {
+				t.Fatalf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantDeleted {
+				return
+			}
+			if kept.IsActive() || kept.TokenVersion != 1 {
+				t.Errorf("kept user active = %v with token version %d, want deactivated and tokens revoked", kept.IsActive(), kept.TokenVersion)
+			}
+			if _, err := s.Login(ctx, user.Email, "secreto123"); !errors.Is(err, domain.ErrInvalidCredentials) {
+				t.Errorf("Login of a deactivated user err = %v, want ErrInvalidCredentials", err)
+			}
+			claims := &auth.Claims{UserID: user.ID, CompanyID: user.CompanyID, TokenVersion: 0}
+			if err := NewAuthService(store, nil).CheckToken(ctx, claims); !errors.Is(err, domain.ErrInvalidToken) {
+				t.Errorf("CheckToken of a token issued before err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+
+	if err := NewUserService(&userStore{}, nil).Delete(context.Background(), uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete of an unknown user err = %v, want ErrNotFound", err)
+	}
+}
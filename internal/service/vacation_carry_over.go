@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+// GetPolicy returns the carry-over policy of a company, or the default one.
+func (s *VacationService) GetPolicy(ctx context.Context, companyID uuid.UUID) (*domain.VacationPolicy, error) {
+	policy, err := s.policyRepo.GetVacationPolicy(ctx, companyID)
+	if err == domain.ErrNotFound {
+		return domain.DefaultVacationPolicy(companyID), nil
+	}
+	return policy, err
+}
+
+type VacationPolicyInput struct {
+	MaxCarryOverDays float64 `json:"max_carry_over_days"`
+	ExpiryMonth      int     `json:"expiry_month"`
+	ExpiryDay        int     `json:"expiry_day"`
+}
+
+func (s *VacationService) SavePolicy(ctx context.Context, companyID uuid.UUID, input VacationPolicyInput) (*domain.VacationPolicy, error) {
+	policy, err := domain.NewVacationPolicy(companyID, input.MaxCarryOverDays, input.ExpiryMonth, input.ExpiryDay)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policyRepo.SaveVacationPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CloseYear snapshots the balance of every user of the company for a past
+// year and carries the allowed days into the next one. Users already closed
+// are skipped, so the operation can be repeated for late joiners.
+func (s *VacationService) CloseYear(ctx context.Context, companyID uuid.UUID, year int, closedBy uuid.UUID) ([]*domain.VacationCarryOver, error) {
+	if year >= time.Now().Year() {
+		return nil, domain.ErrInvalidInput
+	}
+
+	policy, err := s.GetPolicy(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetUsersByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var carryOvers []*domain.VacationCarryOver
+	for _, u := range users {
+		if _, err := s.policyRepo.GetCarryOver(ctx, u.ID, year); err == nil {
+			continue
+		} else if err != domain.ErrNotFound {
+			return nil, err
+		}
+
+		balance, err := s.GetBalance(ctx, u.ID, year)
+		if err != nil {
+			return nil, err
+		}
+		carryOvers = append(carryOvers, domain.NewVacationCarryOver(companyID, balance, policy, closedBy))
+	}
+
+	if len(carryOvers) > 0 {
+		if err := s.policyRepo.CreateCarryOvers(ctx, carryOvers); err != nil {
+			return nil, err
+		}
+	}
+	return carryOvers, nil
+}
+
+// GetCarryOvers returns the year close audit trail of a company.
+func (s *VacationService) GetCarryOvers(ctx context.Context, companyID uuid.UUID, fromYear int) ([]*domain.VacationCarryOver, error) {
+	return s.policyRepo.GetCarryOversByCompanyID(ctx, companyID, fromYear)
+}
+
+// ExpireCarryOvers records the carried days lost by every carry-over whose
+// expiry day is over. It returns how many carry-overs were expired.
+func (s *VacationService) ExpireCarryOvers(ctx context.Context, now time.Time) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	carryOvers, err := s.policyRepo.GetActiveCarryOversExpiringBefore(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range carryOvers {
+		// The balance of the following year already works out the unused days
+		balance, err := s.GetBalance(ctx, c.UserID, c.FromYear+1)
+		if err != nil {
+			return 0, err
+		}
+		c.Expire(balance.CarryOverExpired, now)
+		if err := s.policyRepo.UpdateCarryOver(ctx, c); err != nil {
+			return 0, err
+		}
+	}
+	return len(carryOvers), nil
+}
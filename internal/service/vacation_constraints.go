@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/google/uuid"
+)
+
+type BlackoutPeriodInput struct {
+	Department string `json:"department,omitempty"`
+	Name       string `json:"name"`
+	StartDate  string `json:"start_date"` // Format YYYY-MM-DD
+	EndDate    string `json:"end_date"`   // Format YYYY-MM-DD
+}
+
+func (s *VacationService) CreateBlackoutPeriod(ctx context.Context, companyID uuid.UUID, input BlackoutPeriodInput) (*domain.BlackoutPeriod, error) {
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	endDate, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	blackout, err := domain.NewBlackoutPeriod(companyID, input.Department, input.Name, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.constraintRepo.CreateBlackoutPeriod(ctx, blackout); err != nil {
+		return nil, err
+	}
+	return blackout, nil
+}
+
+func (s *VacationService) GetBlackoutPeriods(ctx context.Context, companyID uuid.UUID) ([]*domain.BlackoutPeriod, error) {
+	return s.constraintRepo.GetBlackoutPeriodsByCompanyID(ctx, companyID)
+}
+
+func (s *VacationService) DeleteBlackoutPeriod(ctx context.Context, companyID, id uuid.UUID) error {
+	return s.constraintRepo.DeleteBlackoutPeriod(ctx, companyID, id)
+}
+
+type StaffingRuleInput struct {
+	Department string `json:"department,omitempty"`
+	MinPresent int    `json:"min_present"`
+}
+
+func (s *VacationService) CreateStaffingRule(ctx context.Context, companyID uuid.UUID, input StaffingRuleInput) (*domain.StaffingRule, error) {
+	rule, err := domain.NewStaffingRule(companyID, input.Department, input.MinPresent)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.constraintRepo.CreateStaffingRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *VacationService) GetStaffingRules(ctx context.Context, companyID uuid.UUID) ([]*domain.StaffingRule, error) {
+	return s.constraintRepo.GetStaffingRulesByCompanyID(ctx, companyID)
+}
+
+func (s *VacationService) DeleteStaffingRule(ctx context.Context, companyID, id uuid.UUID) error {
+	return s.constraintRepo.DeleteStaffingRule(ctx, companyID, id)
+}
+
+// enforceConstraints evaluates the blackout periods and staffing rules of the
+// user's company against a vacation. Violations are returned as a
+// ConstraintViolationError unless an admin overrides them, which is recorded
+// on the vacation. Leave types that need no approval (e.g. sick leave) cannot
+// be refused and are not checked.
+func (s *VacationService) enforceConstraints(ctx context.Context, vacation *domain.Vacation, leaveType *domain.LeaveType, override bool, actorID uuid.UUID, actorRole domain.Role) error {
+	if !leaveType.RequiresApproval {
+		return nil
+	}
+	if override && !actorRole.Can(domain.PermVacationManage) {
+		return domain.ErrForbidden
+	}
+
+	violations, err := s.checkConstraints(ctx, vacation)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if !override {
+		return &domain.ConstraintViolationError{Violations: violations}
+	}
+	vacation.OverrideConstraints(actorID, time.Now())
+	return nil
+}
+
+func (s *VacationService) checkConstraints(ctx context.Context, vacation *domain.Vacation) ([]domain.ConstraintViolation, error) {
+	user, err := s.userRepo.GetUserByID(ctx, vacation.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []domain.ConstraintViolation
+
+	blackouts, err := s.constraintRepo.GetOverlappingBlackoutPeriods(ctx, user.CompanyID, vacation.StartDate, vacation.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blackouts {
+		if b.AppliesTo(user.Department) {
+			violations = append(violations, domain.ConstraintViolation{
+				Type:       domain.ConstraintBlackout,
+				RuleID:     b.ID,
+				Department: b.Department,
+				Name:       b.Name,
+			})
+		}
+	}
+
+	rules, err := s.constraintRepo.GetStaffingRulesByCompanyID(ctx, user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	var applicable []*domain.StaffingRule
+	for _, rule := range rules {
+		if rule.AppliesTo(user.Department) {
+			applicable = append(applicable, rule)
+		}
+	}
+	if len(applicable) == 0 {
+		return violations, nil
+	}
+
+	colleagues, err := s.userRepo.GetUsersByCompanyID(ctx, user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	teamAbsences, err := s.repo.GetAbsencesByCompanyID(ctx, user.CompanyID, vacation.StartDate, vacation.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	var approved []*domain.Vacation
+	for _, a := range teamAbsences {
+		if a.Status == domain.VacationStatusApproved && a.ID != vacation.ID {
+			approved = append(approved, a.Vacation)
+		}
+	}
+	holidays := domain.HolidaySet{}
+	for year := vacation.StartDate.Year(); year <= vacation.EndDate.Year(); year++ {
+		yearHolidays, err := s.holidays(ctx, user.ID, year)
+		if err != nil {
+			return nil, err
+		}
+		for day := range yearHolidays {
+			holidays[day] = struct{}{}
+		}
+	}
+
+	for _, rule := range applicable {
+		var members []*domain.User
+		for _, u := range colleagues {
+			if rule.AppliesTo(u.Department) {
+				members = append(members, u)
+			}
+		}
+		if days := rule.Shortfall(vacation, members, approved, holidays); len(days) > 0 {
+			violations = append(violations, domain.ConstraintViolation{
+				Type:       domain.ConstraintMinStaffing,
+				RuleID:     rule.ID,
+				Department: rule.Department,
+				MinPresent: rule.MinPresent,
+				Dates:      days,
+			})
+		}
+	}
+	return violations, nil
+}
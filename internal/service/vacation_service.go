@@ -10,33 +10,89 @@ import (
 )
 
 type VacationService struct {
-	repo port.VacationRepository
+	repo           port.VacationRepository
+	contractRepo   port.ContractRepository
+	holidayRepo    port.HolidayRepository
+	leaveTypeRepo  port.LeaveTypeRepository
+	userRepo       port.UserRepository
+	policyRepo     port.VacationPolicyRepository
+	constraintRepo port.VacationConstraintRepository
 }
 
-func NewVacationService(repo port.VacationRepository) *VacationService {
-	return &VacationService{repo: repo}
+func NewVacationService(repo port.VacationRepository, contractRepo port.ContractRepository, holidayRepo port.HolidayRepository, leaveTypeRepo port.LeaveTypeRepository, userRepo port.UserRepository, policyRepo port.VacationPolicyRepository, constraintRepo port.VacationConstraintRepository) *VacationService {
+	return &VacationService{
+		repo:           repo,
+		contractRepo:   contractRepo,
+		holidayRepo:    holidayRepo,
+		leaveTypeRepo:  leaveTypeRepo,
+		userRepo:       userRepo,
+		policyRepo:     policyRepo,
+		constraintRepo: constraintRepo,
+	}
 }
 
+// CreateVacationInput requests an absence. Without LeaveTypeID it is a plain vacation.
 type CreateVacationInput struct {
-	UserID    uuid.UUID `json:"user_id"`
-	StartDate string    `json:"start_date"` // Format YYYY-MM-DD
-	EndDate   string    `json:"end_date"`   // Format YYYY-MM-DD
+	UserID        uuid.UUID  `json:"user_id"`
+	LeaveTypeID   *uuid.UUID `json:"leave_type_id,omitempty"`
+	StartDate     string     `json:"start_date"` // Format YYYY-MM-DD
+	EndDate       string     `json:"end_date"`   // Format YYYY-MM-DD
+	AttachmentURL *string    `json:"attachment_url,omitempty"`
+	// Partial days: MORNING/AFTERNOON, or HOURS with StartTime/EndTime (HH:MM). Defaults to FULL_DAY.
+	Period    domain.DayPeriod `json:"period,omitempty"`
+	StartTime *string          `json:"start_time,omitempty"`
+	EndTime   *string          `json:"end_time,omitempty"`
+	// Admins may accept a request breaking blackout or staffing constraints.
+	OverrideConstraints bool        `json:"override_constraints,omitempty"`
+	ActorID             uuid.UUID   `json:"-"`
+	ActorRole           domain.Role `json:"-"`
 }
 
 func (s *VacationService) CreateVacation(ctx context.Context, input CreateVacationInput) (*domain.Vacation, error) {
-	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	startDate, err := parseVacationDate("start_date", input.StartDate)
 	if err != nil {
 		return nil, err
 	}
-	endDate, err := time.Parse("2006-01-02", input.EndDate)
+	endDate, err := parseVacationDate("end_date", input.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveType, err := s.leaveType(ctx, input.LeaveTypeID)
 	if err != nil {
 		return nil, err
 	}
+	if input.LeaveTypeID != nil {
+		user, err := s.userRepo.GetUserByID(ctx, input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.CompanyID != leaveType.CompanyID {
+			return nil, domain.ErrNotFound
+		}
+	}
 
-	vacation, err := domain.NewVacation(input.UserID, startDate, endDate)
+	vacation, err := domain.NewAbsence(input.UserID, leaveType, startDate, endDate, input.AttachmentURL)
 	if err != nil {
 		return nil, err
 	}
+	if err := vacation.SetPeriod(input.Period, input.StartTime, input.EndTime); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOverlap(ctx, vacation); err != nil {
+		return nil, err
+	}
+
+	if leaveType.CountsAgainstBalance {
+		if err := s.checkBalance(ctx, vacation, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.enforceConstraints(ctx, vacation, leaveType, input.OverrideConstraints, input.ActorID, input.ActorRole); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.CreateVacation(ctx, vacation); err != nil {
 		return nil, err
@@ -45,7 +101,29 @@ func (s *VacationService) CreateVacation(ctx context.Context, input CreateVacati
 	return vacation, nil
 }
 
+// GetVacationsByUserID returns the plain vacations of a user.
 func (s *VacationService) GetVacationsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Vacation, error) {
+	absences, err := s.repo.GetVacationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var vacations []*domain.Vacation
+	for _, a := range absences {
+		if a.LeaveTypeID == nil {
+			vacations = append(vacations, a)
+		}
+	}
+	return vacations, nil
+}
+
+// GetAbsencesByUserID returns the absences of a user of every leave type.
+// Other leave types are health data: a viewer other than the user sees them
+// only with PermAbsenceReadDetails, and otherwise gets the plain vacations.
+func (s *VacationService) GetAbsencesByUserID(ctx context.Context, userID, viewerID uuid.UUID, viewer domain.Role) ([]*domain.Vacation, error) {
+	if viewerID != userID && !viewer.Can(domain.PermAbsenceReadDetails) {
+		return s.GetVacationsByUserID(ctx, userID)
+	}
 	return s.repo.GetVacationsByUserID(ctx, userID)
 }
 
@@ -53,21 +131,35 @@ func (s *VacationService) GetVacationByID(ctx context.Context, id uuid.UUID) (*d
 	return s.repo.GetVacationByID(ctx, id)
 }
 
+// UpdateVacationInput changes the dates or the part of the day of a pending
+// vacation. Status changes go through ApproveVacation, RejectVacation and CancelVacation.
 type UpdateVacationInput struct {
-	ID        uuid.UUID              `json:"id"`
-	StartDate *string                `json:"start_date,omitempty"`
-	EndDate   *string                `json:"end_date,omitempty"`
-	Status    *domain.VacationStatus `json:"status,omitempty"`
+	ID        uuid.UUID         `json:"id"`
+	StartDate *string           `json:"start_date,omitempty"`
+	EndDate   *string           `json:"end_date,omitempty"`
+	Period    *domain.DayPeriod `json:"period,omitempty"`
+	StartTime *string           `json:"start_time,omitempty"`
+	EndTime   *string           `json:"end_time,omitempty"`
+	ActorID   uuid.UUID         `json:"-"`
+	ActorRole domain.Role       `json:"-"`
 }
 
+// UpdateVacation lets the owner of a pending vacation, or an admin, change it.
 func (s *VacationService) UpdateVacation(ctx context.Context, input UpdateVacationInput) (*domain.Vacation, error) {
 	vacation, err := s.repo.GetVacationByID(ctx, input.ID)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkOwner(vacation, input.ActorID, input.ActorRole); err != nil {
+		return nil, err
+	}
+	if vacation.Status != domain.VacationStatusPending {
+		return nil, domain.ErrVacationNotEditable
+	}
+	previous := *vacation
 
 	if input.StartDate != nil {
-		startDate, err := time.Parse("2006-01-02", *input.StartDate)
+		startDate, err := parseVacationDate("start_date", *input.StartDate)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +167,7 @@ func (s *VacationService) UpdateVacation(ctx context.Context, input UpdateVacati
 	}
 
 	if input.EndDate != nil {
-		endDate, err := time.Parse("2006-01-02", *input.EndDate)
+		endDate, err := parseVacationDate("end_date", *input.EndDate)
 		if err != nil {
 			return nil, err
 		}
@@ -83,11 +175,30 @@ func (s *VacationService) UpdateVacation(ctx context.Context, input UpdateVacati
 	}
 
 	if vacation.StartDate.After(vacation.EndDate) {
-		return nil, domain.ErrInvalidInput // Assuming ErrInvalidInput exists or generic error
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "end_date", Message: "cannot be before the start date"}}}
+	}
+
+	// Re-validate the period against the new dates, applying any change to it
+	period, startTime, endTime := vacation.Period, vacation.StartTime, vacation.EndTime
+	if input.Period != nil {
+		period, startTime, endTime = *input.Period, input.StartTime, input.EndTime
+	}
+	if err := vacation.SetPeriod(period, startTime, endTime); err != nil {
+		return nil, err
 	}
 
-	if input.Status != nil {
-		vacation.Status = *input.Status
+	if err := s.checkOverlap(ctx, vacation); err != nil {
+		return nil, err
+	}
+
+	leaveType, err := s.leaveType(ctx, vacation.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType.CountsAgainstBalance {
+		if err := s.checkBalance(ctx, vacation, &previous); err != nil {
+			return nil, err
+		}
 	}
 
 	vacation.UpdatedAt = time.Now()
@@ -99,6 +210,290 @@ func (s *VacationService) UpdateVacation(ctx context.Context, input UpdateVacati
 	return vacation, nil
 }
 
-func (s *VacationService) DeleteVacation(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeleteVacation(ctx, id)
+// VacationDecisionInput identifies who acts on a vacation and why.
+type VacationDecisionInput struct {
+	ID        uuid.UUID   `json:"-"`
+	ActorID   uuid.UUID   `json:"-"`
+	ActorRole domain.Role `json:"-"`
+	Reason    string      `json:"reason,omitempty"`
+	// OverrideConstraints approves despite blackout or staffing violations.
+	OverrideConstraints bool `json:"override_constraints,omitempty"`
+}
+
+// ApproveVacation re-evaluates the balance and the company constraints,
+// since other requests may have been approved since this one was created.
+func (s *VacationService) ApproveVacation(ctx context.Context, input VacationDecisionInput) (*domain.Vacation, error) {
+	return s.decide(ctx, input.ID, func(v *domain.Vacation) error {
+		if err := s.checkApprover(ctx, v, input); err != nil {
+			return err
+		}
+		leaveType, err := s.leaveType(ctx, v.LeaveTypeID)
+		if err != nil {
+			return err
+		}
+		if leaveType.CountsAgainstBalance {
+			// The request already holds its days as pending
+			if err := s.checkBalance(ctx, v, v); err != nil {
+				return err
+			}
+		}
+		if err := s.enforceConstraints(ctx, v, leaveType, input.OverrideConstraints, input.ActorID, input.ActorRole); err != nil {
+			return err
+		}
+		return v.Approve(input.ActorID, time.Now())
+	})
+}
+
+func (s *VacationService) RejectVacation(ctx context.Context, input VacationDecisionInput) (*domain.Vacation, error) {
+	return s.decide(ctx, input.ID, func(v *domain.Vacation) error {
+		if err := s.checkApprover(ctx, v, input); err != nil {
+			return err
+		}
+		return v.Reject(input.ActorID, input.Reason, time.Now())
+	})
+}
+
+// CancelVacation withdraws a vacation. Only its owner or an admin may cancel it.
+func (s *VacationService) CancelVacation(ctx context.Context, input VacationDecisionInput) (*domain.Vacation, error) {
+	return s.decide(ctx, input.ID, func(v *domain.Vacation) error {
+		if err := checkOwner(v, input.ActorID, input.ActorRole); err != nil {
+			return err
+		}
+		return v.Cancel(input.ActorID, time.Now())
+	})
+}
+
+// CheckReader lets users see their own vacations and absences, the roles
+// allowed to see anyone's see them all, and managers those of their direct reports.
+func (s *VacationService) CheckReader(ctx context.Context, userID, actorID uuid.UUID, actorRole domain.Role) error {
+	if userID == actorID || actorRole.Can(domain.PermVacationReadAll) {
+		return nil
+	}
+	if !actorRole.Can(domain.PermVacationRead) {
+		return domain.ErrForbidden
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.ReportsTo(actorID) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// checkApprover lets the roles allowed to decide on any request do so, and
+// managers only on the requests of their direct reports.
+func (s *VacationService) checkApprover(ctx context.Context, v *domain.Vacation, input VacationDecisionInput) error {
+	if input.ActorRole.Can(domain.PermVacationApproveAll) {
+		return nil
+	}
+	if !input.ActorRole.Can(domain.PermVacationApprove) {
+		return domain.ErrForbidden
+	}
+	user, err := s.userRepo.GetUserByID(ctx, v.UserID)
+	if err != nil {
+		return err
+	}
+	if !user.ReportsTo(input.ActorID) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func (s *VacationService) decide(ctx context.Context, id uuid.UUID, apply func(*domain.Vacation) error) (*domain.Vacation, error) {
+	vacation, err := s.repo.GetVacationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(vacation); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateVacation(ctx, vacation); err != nil {
+		return nil, err
+	}
+	return vacation, nil
+}
+
+// checkOwner lets only the owner of a vacation or the roles that manage the
+// vacations of others act on it.
+func checkOwner(v *domain.Vacation, actorID uuid.UUID, actorRole domain.Role) error {
+	if !actorRole.Can(domain.PermVacationManage) && actorID != v.UserID {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// DeleteVacation removes a pending request made by mistake. Decided vacations
+// keep their audit trail and are withdrawn with CancelVacation instead.
+func (s *VacationService) DeleteVacation(ctx context.Context, input VacationDecisionInput) error {
+	vacation, err := s.repo.GetVacationByID(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(vacation, input.ActorID, input.ActorRole); err != nil {
+		return err
+	}
+	if vacation.Status != domain.VacationStatusPending {
+		return domain.ErrVacationNotEditable
+	}
+	return s.repo.DeleteVacation(ctx, input.ID)
+}
+
+// GetBalance computes the vacation balance of a user for the given year from
+// their contract history and the vacations already requested.
+func (s *VacationService) GetBalance(ctx context.Context, userID uuid.UUID, year int) (*domain.VacationBalance, error) {
+	balance, _, err := s.balance(ctx, userID, year)
+	return balance, err
+}
+
+// balance also returns the holidays of the user's calendar for the year so
+// callers can count further days the same way.
+func (s *VacationService) balance(ctx context.Context, userID uuid.UUID, year int) (*domain.VacationBalance, domain.HolidaySet, error) {
+	contracts, err := s.contractRepo.GetContractsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.balanceOf(ctx, userID, year, contracts)
+}
+
+// balanceOf computes the balance the user would have under the given contracts.
+func (s *VacationService) balanceOf(ctx context.Context, userID uuid.UUID, year int, contracts []*domain.Contract) (*domain.VacationBalance, domain.HolidaySet, error) {
+	vacations, err := s.repo.GetVacationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	holidays, err := s.holidays(ctx, userID, year)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	carryOver, err := s.policyRepo.GetCarryOver(ctx, userID, year-1)
+	if err != nil && err != domain.ErrNotFound {
+		return nil, nil, err
+	}
+
+	balance := domain.NewVacationBalance(userID, year, contracts)
+	yearStart, _ := domain.YearBounds(year)
+	var usedBeforeExpiry float64
+	leaveTypes := make(map[uuid.UUID]*domain.LeaveType)
+	for _, v := range vacations {
+		if _, _, ok := domain.ClipToYear(v.StartDate, v.EndDate, year); !ok {
+			continue
+		}
+
+		if v.LeaveTypeID != nil {
+			lt, cached := leaveTypes[*v.LeaveTypeID]
+			if !cached {
+				if lt, err = s.leaveType(ctx, v.LeaveTypeID); err != nil {
+					return nil, nil, err
+				}
+				leaveTypes[*v.LeaveTypeID] = lt
+			}
+			if !lt.CountsAgainstBalance {
+				continue
+			}
+		}
+
+		balance.Consume(v.Status, v.DaysInYear(year, balance.Unit, holidays))
+		// Pending requests do not use carried days until they are approved
+		if carryOver != nil && v.Status == domain.VacationStatusApproved {
+			usedBeforeExpiry += v.DaysBetween(yearStart, carryOver.ExpiresAt, balance.Unit, holidays)
+		}
+	}
+
+	if carryOver != nil {
+		balance.ApplyCarryOver(carryOver, usedBeforeExpiry, time.Now())
+	}
+	return balance, holidays, nil
+}
+
+// parseVacationDate reads a YYYY-MM-DD request date, naming the field when it is invalid.
+func parseVacationDate(field, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, &domain.ValidationError{Fields: []domain.FieldError{{Field: field, Message: "must be a date as YYYY-MM-DD"}}}
+	}
+	return date, nil
+}
+
+// leaveType resolves the leave type of an absence; nil means a plain vacation.
+func (s *VacationService) leaveType(ctx context.Context, id *uuid.UUID) (*domain.LeaveType, error) {
+	if id == nil {
+		return domain.VacationLeaveType, nil
+	}
+	return s.leaveTypeRepo.GetLeaveTypeByID(ctx, *id)
+}
+
+// holidays loads the public holidays of the calendar assigned to the user.
+func (s *VacationService) holidays(ctx context.Context, userID uuid.UUID, year int) (domain.HolidaySet, error) {
+	return userHolidays(ctx, s.holidayRepo, userID, year)
+}
+
+// checkOverlap rejects a vacation whose range intersects another active
+// request of the same user.
+func (s *VacationService) checkOverlap(ctx context.Context, vacation *domain.Vacation) error {
+	candidates, err := s.repo.GetOverlappingVacations(ctx, vacation.UserID, vacation.StartDate, vacation.EndDate, vacation.ID)
+	if err != nil {
+		return err
+	}
+
+	overlapErr := &domain.VacationOverlapError{}
+	for _, c := range candidates {
+		if vacation.Overlaps(c) {
+			overlapErr.ConflictingIDs = append(overlapErr.ConflictingIDs, c.ID)
+		}
+	}
+	if len(overlapErr.ConflictingIDs) == 0 {
+		return nil
+	}
+	return overlapErr
+}
+
+// checkBalance rejects a vacation that does not fit in the remaining balance
+// of any of the years it spans. Days after the carry-over expiry date must fit
+// in the balance without the carried days. When updating or approving,
+// previous holds the stored version of the vacation so its days are given
+// back before checking.
+func (s *VacationService) checkBalance(ctx context.Context, vacation, previous *domain.Vacation) error {
+	for year := vacation.StartDate.Year(); year <= vacation.EndDate.Year(); year++ {
+		balance, holidays, err := s.balance(ctx, vacation.UserID, year)
+		if err != nil {
+			return err
+		}
+
+		requested := vacation.DaysInYear(year, balance.Unit, holidays)
+
+		remaining, entitledRemaining := balance.Remaining, balance.EntitledRemaining()
+		if previous != nil && previous.Status.IsActive() {
+			// Only pending vacations can be updated or approved, and those use
+			// no carried days
+			givenBack := previous.DaysInYear(year, balance.Unit, holidays)
+			remaining += givenBack
+			entitledRemaining += givenBack
+		}
+
+		if requested > remaining {
+			return &domain.InsufficientBalanceError{
+				Year:      year,
+				Requested: requested,
+				Remaining: remaining,
+			}
+		}
+
+		if balance.CarryOverExpiresAt != nil {
+			_, yearEnd := domain.YearBounds(year)
+			afterExpiry := vacation.DaysBetween(balance.CarryOverExpiresAt.AddDate(0, 0, 1), yearEnd, balance.Unit, holidays)
+			if afterExpiry > entitledRemaining {
+				return &domain.InsufficientBalanceError{
+					Year:      year,
+					Requested: afterExpiry,
+					Remaining: entitledRemaining,
+				}
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fuenr/myteam/internal/domain"
+	"github.com/fuenr/myteam/internal/port"
+	"github.com/google/uuid"
+)
+
+// vacationStore keeps vacations in memory. The embedded ports are nil:
+// calling anything not implemented here panics.
+type vacationStore struct {
+	port.VacationRepository
+	port.ContractRepository
+	port.HolidayRepository
+	port.UserRepository
+	port.VacationPolicyRepository
+	port.VacationConstraintRepository
+
+	vacations []*domain.Vacation
+	contracts []*domain.Contract
+	user      *domain.User
+}
+
+func (s *vacationStore) GetVacationByID(ctx context.Context, id uuid.UUID) (*domain.Vacation, error) {
+	for _, v := range s.vacations {
+		if v.ID == id {
+			copied := *v
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *vacationStore) GetVacationsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Vacation, error) {
+	var found []*domain.Vacation
+	for _, v := range s.vacations {
+		if v.UserID == userID {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+func (s *vacationStore) UpdateVacation(ctx context.Context, vacation *domain.Vacation) error {
+	for i, v := range s.vacations {
+		if v.ID == vacation.ID {
+			s.vacations[i] = vacation
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (s *vacationStore) GetContractsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contract, error) {
+	return s.contracts, nil
+}
+
+func (s *vacationStore) GetCalendarByUserID(ctx context.Context, userID uuid.UUID) (*domain.HolidayCalendar, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (s *vacationStore) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return s.user, nil
+}
+
+func (s *vacationStore) GetCarryOver(ctx context.Context, userID uuid.UUID, fromYear int) (*domain.VacationCarryOver, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (s *vacationStore) GetOverlappingBlackoutPeriods(ctx context.Context, companyID uuid.UUID, start, end time.Time) ([]*domain.BlackoutPeriod, error) {
+	return nil, nil
+}
+
+func (s *vacationStore) GetStaffingRulesByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*domain.StaffingRule, error) {
+	return nil, nil
+}
+
+func (s *vacationStore) GetOverlappingVacations(ctx context.Context, userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]*domain.Vacation, error) {
+	var found []*domain.Vacation
+	for _, v := range s.vacations {
+		if v.UserID == userID && v.ID != excludeID && v.Status.IsActive() && !v.StartDate.After(end) && !start.After(v.EndDate) {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+func TestCheckOverlap(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	userID := uuid.New()
+	absence := func(start, end time.Time, status domain.VacationStatus) *domain.Vacation {
+		return &domain.Vacation{ID: uuid.New(), UserID: userID, StartDate: start, EndDate: end, Period: domain.PeriodFullDay, Status: status}
+	}
+	approved := absence(day(time.May, 6), day(time.May, 10), domain.VacationStatusApproved)
+	pending := absence(day(time.May, 20), day(time.May, 20), domain.VacationStatusPending)
+	cancelled := absence(day(time.June, 3), day(time.June, 7), domain.VacationStatusCancelled)
+	s := &VacationService{repo: &vacationStore{vacations: []*domain.Vacation{approved, pending, cancelled}}}
+
+	tests := []struct {
+		name     string
+		vacation *domain.Vacation
+		want     []uuid.UUID
+	}{
+		{"free week", absence(day(time.May, 13), day(time.May, 17), domain.VacationStatusPending), nil},
+		{"last day of an approved one", absence(day(time.May, 10), day(time.May, 14), domain.VacationStatusPending), []uuid.UUID{approved.ID}},
+		{"both", absence(day(time.May, 1), day(time.May, 31), domain.VacationStatusPending), []uuid.UUID{approved.ID, pending.ID}},
+		{"cancelled dates", absence(day(time.June, 3), day(time.June, 4), domain.VacationStatusPending), nil},
+		{"other user", &domain.Vacation{ID: uuid.New(), UserID: uuid.New(), StartDate: day(time.May, 6), EndDate: day(time.May, 6), Period: domain.PeriodFullDay}, nil},
+		// An update does not collide with the stored version of itself
+		{"itself", approved, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkOverlap(context.Background(), tt.vacation)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkOverlap = %v, want nil", err)
+				}
+				return
+			}
+			var overlapErr *domain.VacationOverlapError
+			if !errors.As(err, &overlapErr) || !errors.Is(err, domain.ErrVacationOverlap) {
+				t.Fatalf("checkOverlap = %v, want a *VacationOverlapError", err)
+			}
+			if !slices.Equal(overlapErr.ConflictingIDs, tt.want) {
+				t.Errorf("conflicting ids = %v, want %v", overlapErr.ConflictingIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestApproveVacationChecksBalance(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	user := &domain.User{ID: uuid.New(), CompanyID: uuid.New(), Role: domain.RoleEmployee}
+	absence := func(start, end time.Time, status domain.VacationStatus) *domain.Vacation {
+		return &domain.Vacation{ID: uuid.New(), UserID: user.ID, StartDate: start, EndDate: end, Period: domain.PeriodFullDay, Status: status}
+	}
+	// 22 working days in 2024
+	contract := &domain.Contract{ID: uuid.New(), UserID: user.ID, Type: domain.ContractTypeIndefinite, StartDate: day(time.January, 1)}
+
+	tests := []struct {
+		name     string
+		approved *domain.Vacation
+		request  *domain.Vacation
+		want     error
+	}{
+		// 10 approved and 5 requested
+		{"fits", absence(day(time.January, 8), day(time.January, 19), domain.VacationStatusApproved), absence(day(time.March, 4), day(time.March, 8), domain.VacationStatusPending), nil},
+		// 15 approved since the 10 were requested
+		{"approved since", absence(day(time.February, 5), day(time.February, 23), domain.VacationStatusApproved), absence(day(time.January, 8), day(time.January, 19), domain.VacationStatusPending), domain.ErrInsufficientBalance},
+		// 12 approved and 10 requested use the whole year
+		{"whole balance", absence(day(time.February, 5), day(time.February, 20), domain.VacationStatusApproved), absence(day(time.January, 8), day(time.January, 19), domain.VacationStatusPending), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &vacationStore{
+				vacations: []*domain.Vacation{tt.approved, tt.request},
+				contracts: []*domain.Contract{contract},
+				user:      user,
+			}
+			s := NewVacationService(store, store, store, nil, store, store, store)
+
+			v, err := s.ApproveVacation(context.Background(), VacationDecisionInput{ID: tt.request.ID, ActorID: uuid.New(), ActorRole: domain.RoleAdmin})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ApproveVacation err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && v.Status != domain.VacationStatusApproved {
+				t.Errorf("status = %s, want APPROVED", v.Status)
+			}
+			if tt.want != nil && store.vacations[1].Status != domain.VacationStatusPending {
+				t.Errorf("stored status = %s, want PENDING", store.vacations[1].Status)
+			}
+		})
+	}
+}
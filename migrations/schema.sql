@@ -31,6 +31,49 @@ CREATE TABLE IF NOT EXISTS contracts (
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
+-- Working-time register (registro de jornada, RDL 8/2019)
+CREATE TABLE IF NOT EXISTS time_entries (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    clock_in TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (clock_out IS NULL OR clock_out > clock_in)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_clock_in ON time_entries (user_id, clock_in);
+-- A user can only be clocked in once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_open ON time_entries (user_id) WHERE clock_out IS NULL;
+
+CREATE TABLE IF NOT EXISTS time_breaks (
+    id UUID PRIMARY KEY,
+    time_entry_id UUID NOT NULL REFERENCES time_entries(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE
+);
+
+-- Admin corrections of the register with their justification
+CREATE TABLE IF NOT EXISTS time_entry_edits (
+    id UUID PRIMARY KEY,
+    time_entry_id UUID NOT NULL REFERENCES time_entries(id) ON DELETE CASCADE,
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL,
+    previous_clock_in TIMESTAMP WITH TIME ZONE NOT NULL,
+    previous_clock_out TIMESTAMP WITH TIME ZONE,
+    clock_in TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Employee signature of the monthly register
+CREATE TABLE IF NOT EXISTS time_register_acknowledgements (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    month CHAR(7) NOT NULL, -- YYYY-MM
+    acknowledged_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, month)
+);
+
 CREATE TABLE IF NOT EXISTS vacations (
     id UUID PRIMARY KEY,
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -40,3 +83,324 @@ CREATE TABLE IF NOT EXISTS vacations (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
+
+-- Approval state machine and decision audit
+ALTER TABLE vacations DROP CONSTRAINT IF EXISTS vacations_status_check;
+ALTER TABLE vacations ADD CONSTRAINT vacations_status_check CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'CANCELLED'));
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS approver_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (company_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS holidays (
+    id UUID PRIMARY KEY,
+    calendar_id UUID NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    scope VARCHAR(50) NOT NULL CHECK (scope IN ('NATIONAL', 'REGIONAL', 'LOCAL')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (calendar_id, date)
+);
+
+-- Work centre calendar used to count the employee's working days
+ALTER TABLE users ADD COLUMN IF NOT EXISTS holiday_calendar_id UUID REFERENCES holiday_calendars(id) ON DELETE SET NULL;
+
+-- Secret iCalendar subscription URL per user (only the SHA-256 of the token is stored)
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Kinds of absence besides vacations (sick leave, parental leave, unpaid leave...)
+CREATE TABLE IF NOT EXISTS leave_types (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    paid BOOLEAN NOT NULL DEFAULT TRUE,
+    counts_against_balance BOOLEAN NOT NULL DEFAULT FALSE,
+    requires_attachment BOOLEAN NOT NULL DEFAULT FALSE,
+    requires_approval BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (company_id, name)
+);
+
+-- NULL leave_type_id means a plain vacation
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS leave_type_id UUID REFERENCES leave_types(id) ON DELETE RESTRICT;
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS attachment_url TEXT;
+
+-- Half-day (MORNING/AFTERNOON) and hourly (HOURS, start_time-end_time) absences
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS period VARCHAR(20) NOT NULL DEFAULT 'FULL_DAY' CHECK (period IN ('FULL_DAY', 'MORNING', 'AFTERNOON', 'HOURS'));
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS start_time VARCHAR(5);
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS end_time VARCHAR(5);
+
+CREATE TABLE IF NOT EXISTS vacation_policies (
+    company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+    max_carry_over_days DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    expiry_month SMALLINT NOT NULL DEFAULT 3 CHECK (expiry_month BETWEEN 1 AND 12),
+    expiry_day SMALLINT NOT NULL DEFAULT 31 CHECK (expiry_day BETWEEN 1 AND 31),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Year close audit trail: balance snapshot, carried and forfeited days, and expiry
+CREATE TABLE IF NOT EXISTS vacation_carry_overs (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    from_year INTEGER NOT NULL,
+    entitled DECIMAL(6, 2) NOT NULL,
+    used DECIMAL(6, 2) NOT NULL,
+    remaining DECIMAL(6, 2) NOT NULL,
+    carried_days DECIMAL(6, 2) NOT NULL,
+    forfeited_days DECIMAL(6, 2) NOT NULL,
+    expires_at DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'EXPIRED')),
+    expired_days DECIMAL(6, 2) NOT NULL DEFAULT 0,
+    expired_at TIMESTAMP WITH TIME ZONE,
+    closed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, from_year)
+);
+
+-- Department used to scope blackout periods and minimum staffing rules
+ALTER TABLE users ADD COLUMN IF NOT EXISTS department VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Windows in which vacations are not allowed (empty department = whole company)
+CREATE TABLE IF NOT EXISTS blackout_periods (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (start_date <= end_date)
+);
+
+-- Minimum number of employees that must remain at work (empty department = whole company)
+CREATE TABLE IF NOT EXISTS staffing_rules (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    min_present INTEGER NOT NULL CHECK (min_present > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (company_id, department)
+);
+
+-- Admin who approved a vacation despite the constraints above
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS constraints_overridden_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE vacations ADD COLUMN IF NOT EXISTS constraints_overridden_at TIMESTAMP WITH TIME ZONE;
+
+-- Contracted ordinary working time, used to compute overtime
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS weekly_hours DECIMAL(5, 2) NOT NULL DEFAULT 40;
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS part_time_percentage DECIMAL(5, 2) NOT NULL DEFAULT 100;
+
+-- Shift patterns: one week or a rotation of several weeks starting on a Monday
+CREATE TABLE IF NOT EXISTS schedule_templates (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    cycle_days INTEGER NOT NULL CHECK (cycle_days > 0 AND cycle_days % 7 = 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (company_id, name)
+);
+
+-- Working days of the cycle; days without a row are rest days
+CREATE TABLE IF NOT EXISTS schedule_template_shifts (
+    template_id UUID NOT NULL REFERENCES schedule_templates(id) ON DELETE CASCADE,
+    day_index INTEGER NOT NULL CHECK (day_index >= 0),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL, -- At or before start_time for night shifts
+    PRIMARY KEY (template_id, day_index)
+);
+
+CREATE TABLE IF NOT EXISTS schedule_assignments (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template_id UUID NOT NULL REFERENCES schedule_templates(id) ON DELETE RESTRICT,
+    start_date DATE NOT NULL,
+    end_date DATE, -- NULL while in force indefinitely
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (end_date IS NULL OR start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_assignments_user ON schedule_assignments (user_id, start_date);
+
+-- Changes of contract terms from effective_date on (NULL = unchanged). Rows are never updated;
+-- the contracts row keeps the terms signed originally.
+CREATE TABLE IF NOT EXISTS contract_amendments (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE RESTRICT,
+    effective_date DATE NOT NULL,
+    type VARCHAR(50),
+    end_date DATE,
+    position VARCHAR(100),
+    salary DECIMAL(10, 2),
+    weekly_hours DECIMAL(5, 2),
+    part_time_percentage DECIMAL(5, 2),
+    reason TEXT NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_amendments_contract ON contract_amendments (contract_id, effective_date);
+-- Deleting a contract must not wipe its amendment history
+ALTER TABLE contract_amendments DROP CONSTRAINT IF EXISTS contract_amendments_contract_id_fkey;
+ALTER TABLE contract_amendments ADD CONSTRAINT contract_amendments_contract_id_fkey FOREIGN KEY (contract_id) REFERENCES contracts(id) ON DELETE RESTRICT;
+
+-- Legal ground of temporary contracts / modality of training ones (RDL 32/2021)
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS cause VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS justification TEXT NOT NULL DEFAULT '';
+
+-- Seasons worked under fixed-discontinuous contracts
+CREATE TABLE IF NOT EXISTS contract_activation_periods (
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    PRIMARY KEY (contract_id, start_date),
+    CHECK (start_date <= end_date)
+);
+
+-- UPCOMING, ACTIVE or ENDED; refreshed daily by the API from the dates in force
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+
+CREATE INDEX IF NOT EXISTS idx_contracts_status ON contracts (status);
+
+-- In-app notifications; key identifies the event so background jobs never notify it twice
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    resource_id UUID,
+    key VARCHAR(255) NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at);
+
+-- Monthly payslips (nóminas). Issued once per contract and month and never
+-- updated; the rendered PDF is archived with them.
+CREATE TABLE IF NOT EXISTS payslips (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE RESTRICT,
+    period CHAR(7) NOT NULL, -- YYYY-MM
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    days INTEGER NOT NULL,
+    contract_type VARCHAR(50) NOT NULL,
+    position VARCHAR(100) NOT NULL,
+    contribution_group INTEGER NOT NULL,
+    payments_per_year INTEGER NOT NULL,
+    contribution_base DECIMAL(10, 2) NOT NULL,
+    irpf_rate DECIMAL(5, 2) NOT NULL,
+    gross_pay DECIMAL(10, 2) NOT NULL,
+    total_deductions DECIMAL(10, 2) NOT NULL,
+    net_pay DECIMAL(10, 2) NOT NULL,
+    employer_cost DECIMAL(10, 2) NOT NULL,
+    document BYTEA NOT NULL,
+    issued_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (contract_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payslips_user ON payslips (user_id, period);
+
+-- EARNING, DEDUCTION or EMPLOYER lines of a payslip, in order
+CREATE TABLE IF NOT EXISTS payslip_lines (
+    payslip_id UUID NOT NULL REFERENCES payslips(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    line INTEGER NOT NULL,
+    concept VARCHAR(255) NOT NULL,
+    base DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (payslip_id, kind, line)
+);
+
+-- Bank accounts for salary transfers (SEPA): the employee account and the
+-- company account salaries are paid from
+ALTER TABLE users ADD COLUMN IF NOT EXISTS iban VARCHAR(34) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS iban VARCHAR(34) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS bic VARCHAR(11) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_payslips_company ON payslips (company_id, period);
+
+-- Termination of a contract on its last day worked, with the final
+-- settlement (finiquito). Brings the end date of the contract forward.
+CREATE TABLE IF NOT EXISTS contract_terminations (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    payments_per_year INTEGER NOT NULL,
+    pending_salary_days INTEGER NOT NULL,
+    pending_salary DECIMAL(10, 2) NOT NULL,
+    extra_payments DECIMAL(10, 2) NOT NULL,
+    vacation_days DECIMAL(5, 2) NOT NULL,
+    vacation_unit VARCHAR(20) NOT NULL,
+    vacation_pay DECIMAL(10, 2) NOT NULL,
+    seniority_date DATE NOT NULL,
+    severance_days DECIMAL(6, 2) NOT NULL,
+    severance DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Bumped to revoke the access tokens already issued (role or password change)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+-- Rotating refresh tokens: each one is used once and replaced by another of
+-- the same family (session). Only the SHA-256 of the token is stored.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens (user_id);
+
+-- Managers decide on the vacation requests of their direct reports
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('ADMIN', 'MANAGER', 'EMPLOYEE'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS manager_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_manager ON users (manager_id);
+
+-- Single-use tokens emailed to invited users and to reset forgotten
+-- passwords. Only the SHA-256 of the token is stored.
+CREATE TABLE IF NOT EXISTS account_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('INVITATION', 'PASSWORD_RESET')),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user ON account_tokens (user_id, purpose);
+
+-- Users whose contracts have amendments or payslips are deactivated instead
+-- of deleted: the history of their contracts must be kept
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
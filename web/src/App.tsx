@@ -4,9 +4,11 @@ import Login from './pages/Login';
 import Dashboard from './pages/Dashboard';
 import UsersPage from './pages/UsersPage';
 import Register from './pages/Register';
+import SetPassword from './pages/SetPassword';
 import VacationsPage from './pages/VacationsPage';
 import Layout from './components/Layout';
 import UserDetailPage from './pages/UserDetailPage';
+import { logout } from './utils/api';
 import './index.css';
 
 function App() {
@@ -26,14 +28,14 @@ function App() {
 
   const handleLogout = () => {
     setUser(null);
-    localStorage.removeItem('token');
-    localStorage.removeItem('user');
+    logout();
   };
 
   return (
     <BrowserRouter>
       <Routes>
         <Route path="/register" element={<Register />} />
+        <Route path="/set-password" element={<SetPassword />} />
         <Route path="/login" element={
           !user ? (
             <Login onLoginSuccess={handleLogin} />
@@ -17,6 +17,13 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
     const [type, setType] = useState('Contrato indefinido');
     const [position, setPosition] = useState('');
     const [salary, setSalary] = useState('');
+    const [weeklyHours, setWeeklyHours] = useState('40');
+    const [effectiveDate, setEffectiveDate] = useState('');
+    const [cause, setCause] = useState('');
+    const [justification, setJustification] = useState('');
+    const [seasonStart, setSeasonStart] = useState('');
+    const [seasonEnd, setSeasonEnd] = useState('');
+    const [reason, setReason] = useState('');
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState('');
 
@@ -27,6 +34,11 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
             setType(initialData.type || 'Contrato indefinido');
             setPosition(initialData.position || '');
             setSalary(initialData.salary?.toString() || '');
+            setWeeklyHours(initialData.weekly_hours?.toString() || '40');
+            setEffectiveDate(new Date().toISOString().split('T')[0]);
+            setCause(initialData.cause || '');
+            setJustification(initialData.justification || '');
+            setReason('');
         } else if (isOpen) {
             // Reset for new contract
             setStartDate(new Date().toISOString().split('T')[0]);
@@ -34,6 +46,11 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
             setType('Contrato indefinido');
             setPosition('');
             setSalary('');
+            setWeeklyHours('40');
+            setCause('');
+            setJustification('');
+            setSeasonStart('');
+            setSeasonEnd('');
         }
         setError('');
     }, [isOpen, initialData]);
@@ -46,35 +63,42 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
         setError('');
 
         try {
-            let url = `/users/${userId}/contracts`;
-            let method = 'POST';
+            const terms = {
+                end_date: endDate || null,
+                type,
+                position,
+                salary: parseFloat(salary),
+                weekly_hours: parseFloat(weeklyHours),
+            };
 
-            console.log('ContractModal submit:', { initialData, userId });
-
-            if (initialData && initialData.id) {
-                url = `/contracts/${initialData.id}`;
-                method = 'PUT';
-            }
-
-            console.log(`Fetching: ${method} ${url}`);
-
-            const res = await apiFetch(url, {
-                method,
-                body: JSON.stringify({
-                    start_date: startDate,
-                    end_date: endDate || null,
-                    type,
-                    position,
-                    salary: parseFloat(salary),
+            // Existing contracts are never overwritten: changes are recorded as amendments
+            const isAmendment = initialData && initialData.id;
+            const res = isAmendment
+                ? await apiFetch(`/contracts/${initialData.id}/amendments`, {
+                    method: 'POST',
+                    body: JSON.stringify({ ...terms, effective_date: effectiveDate, reason })
                 })
-            });
+                : await apiFetch(`/users/${userId}/contracts`, {
+                    method: 'POST',
+                    body: JSON.stringify({
+                        ...terms,
+                        start_date: startDate,
+                        cause: cause || undefined,
+                        justification: justification || undefined,
+                        activation_periods: type === 'Contrato fijo-discontinuo' && seasonStart
+                            ? [{ start_date: seasonStart, end_date: seasonEnd }]
+                            : undefined,
+                    })
+                });
 
             if (!res.ok) {
                 const text = await res.text();
                 let errorMessage = `Request failed: ${res.status} ${res.statusText}`;
                 try {
                     const data = JSON.parse(text);
-                    if (data && data.error) errorMessage = data.error;
+                    if (data && data.fields) {
+                        errorMessage = data.fields.map((f: { field: string; message: string }) => `${f.field}: ${f.message}`).join('. ');
+                    } else if (data && data.error) errorMessage = data.error;
                 } catch (e) {
                     console.warn("Server responded with non-JSON error:", text);
                 }
@@ -156,6 +180,58 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
                         </div>
                     </div>
 
+                    {!initialData?.id && (type === 'Contrato temporal' || type === 'Contrato formativo') && (
+                        <div className="input-group">
+                            <label className="input-label">{type === 'Contrato temporal' ? 'Cause' : 'Modality'}</label>
+                            <select
+                                className="input-field"
+                                value={cause}
+                                onChange={e => setCause(e.target.value)}
+                                required
+                            >
+                                <option value="">Select...</option>
+                                {type === 'Contrato temporal' ? (
+                                    <>
+                                        <option value="PRODUCTION_CIRCUMSTANCES">Circunstancias de la producción (max. 6 months)</option>
+                                        <option value="OCCASIONAL_PREDICTABLE">Situaciones ocasionales previsibles (max. 90 days)</option>
+                                        <option value="SUBSTITUTION">Sustitución de persona trabajadora</option>
+                                    </>
+                                ) : (
+                                    <>
+                                        <option value="WORK_STUDY">Formación en alternancia (3 months - 2 years)</option>
+                                        <option value="PROFESSIONAL_PRACTICE">Práctica profesional (6 months - 1 year)</option>
+                                    </>
+                                )}
+                            </select>
+                        </div>
+                    )}
+
+                    {!initialData?.id && type === 'Contrato temporal' && (
+                        <div className="input-group">
+                            <label className="input-label">Justification</label>
+                            <input
+                                className="input-field"
+                                placeholder="Circumstances that justify the temporary hiring"
+                                value={justification}
+                                onChange={e => setJustification(e.target.value)}
+                                required
+                            />
+                        </div>
+                    )}
+
+                    {!initialData?.id && type === 'Contrato fijo-discontinuo' && (
+                        <div style={{ display: 'grid', gridTemplateColumns: '1fr 1fr', gap: '1rem' }}>
+                            <div className="input-group">
+                                <label className="input-label">Activation From</label>
+                                <input type="date" className="input-field" value={seasonStart} onChange={e => setSeasonStart(e.target.value)} required />
+                            </div>
+                            <div className="input-group">
+                                <label className="input-label">Activation To</label>
+                                <input type="date" className="input-field" value={seasonEnd} onChange={e => setSeasonEnd(e.target.value)} required />
+                            </div>
+                        </div>
+                    )}
+
                     <div className="input-group">
                         <label className="input-label">Position</label>
                         <div style={{ position: 'relative' }}>
@@ -182,6 +258,7 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
                                     style={{ paddingLeft: '3rem' }}
                                     value={startDate}
                                     onChange={e => setStartDate(e.target.value)}
+                                    disabled={!!initialData?.id}
                                     required
                                 />
                             </div>
@@ -219,6 +296,45 @@ export default function ContractModal({ isOpen, onClose, onSuccess, initialData,
                         </div>
                     </div>
 
+                    <div className="input-group">
+                        <label className="input-label">Weekly Hours</label>
+                        <input
+                            type="number"
+                            step="0.5"
+                            min="0.5"
+                            max="40"
+                            className="input-field"
+                            value={weeklyHours}
+                            onChange={e => setWeeklyHours(e.target.value)}
+                            required
+                        />
+                    </div>
+
+                    {initialData?.id && (
+                        <div style={{ display: 'grid', gridTemplateColumns: '1fr 2fr', gap: '1rem' }}>
+                            <div className="input-group">
+                                <label className="input-label">Effective From</label>
+                                <input
+                                    type="date"
+                                    className="input-field"
+                                    value={effectiveDate}
+                                    onChange={e => setEffectiveDate(e.target.value)}
+                                    required
+                                />
+                            </div>
+                            <div className="input-group">
+                                <label className="input-label">Reason</label>
+                                <input
+                                    className="input-field"
+                                    placeholder="e.g. Promotion, yearly pay rise"
+                                    value={reason}
+                                    onChange={e => setReason(e.target.value)}
+                                    required
+                                />
+                            </div>
+                        </div>
+                    )}
+
                     <div style={{ display: 'flex', justifyContent: 'flex-end', gap: '0.75rem', marginTop: '1rem' }}>
                         <button type="button" onClick={onClose} className="btn-secondary" style={{ width: 'auto' }}>Cancel</button>
                         <button type="submit" className="btn" style={{ width: 'auto' }} disabled={loading}>
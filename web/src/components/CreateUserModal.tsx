@@ -154,6 +154,7 @@ export default function CreateUserModal({ isOpen, onClose, onSuccess, companyId
                             style={{ appearance: 'none' }} // Custom styled select often needs this
                         >
                             <option value="EMPLOYEE">User</option>
+                            <option value="MANAGER">Manager</option>
                             <option value="ADMIN">Administrator</option>
                         </select>
                     </div>
@@ -16,7 +16,7 @@ interface VacationModalProps {
 export default function VacationModal({ isOpen, onClose, onSuccess, initialData, title, userId, isAdmin }: VacationModalProps) {
     const [startDate, setStartDate] = useState('');
     const [endDate, setEndDate] = useState('');
-    const [status, setStatus] = useState<'PENDING' | 'APPROVED' | 'REJECTED'>('PENDING');
+    const [status, setStatus] = useState<Vacation['status']>('PENDING');
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState('');
 
@@ -41,39 +41,40 @@ export default function VacationModal({ isOpen, onClose, onSuccess, initialData,
         setError('');
 
         try {
-            let url = `/users/${userId}/vacations`;
-            let method = 'POST';
-            const body: any = {
-                start_date: startDate,
-                end_date: endDate,
+            const request = async (url: string, method: string, body?: any) => {
+                const res = await apiFetch(url, {
+                    method,
+                    body: body ? JSON.stringify(body) : undefined
+                });
+
+                if (!res.ok) {
+                    const text = await res.text();
+                    let errorMessage = `Request failed: ${res.status} ${res.statusText}`;
+                    try {
+                        const data = JSON.parse(text);
+                        if (data && data.error) errorMessage = data.error;
+                    } catch (e) {
+                        errorMessage = text.trim() || errorMessage;
+                        console.warn("Server responded with non-JSON error:", text);
+                    }
+                    throw new Error(errorMessage);
+                }
             };
 
             if (initialData && initialData.id) {
-                url = `/vacations/${initialData.id}`;
-                method = 'PUT';
-                if (isAdmin) {
-                    body.status = status;
+                const datesChanged = startDate !== new Date(initialData.start_date).toISOString().split('T')[0]
+                    || endDate !== new Date(initialData.end_date).toISOString().split('T')[0];
+                if (datesChanged) {
+                    await request(`/vacations/${initialData.id}`, 'PUT', { start_date: startDate, end_date: endDate });
                 }
-                // Also allow updating dates if needed?
-                body.start_date = startDate;
-                body.end_date = endDate;
-            }
 
-            const res = await apiFetch(url, {
-                method,
-                body: JSON.stringify(body)
-            });
-
-            if (!res.ok) {
-                const text = await res.text();
-                let errorMessage = `Request failed: ${res.status} ${res.statusText}`;
-                try {
-                    const data = JSON.parse(text);
-                    if (data && data.error) errorMessage = data.error;
-                } catch (e) {
-                    console.warn("Server responded with non-JSON error:", text);
+                // Status changes go through the dedicated transition endpoints
+                if (isAdmin && status !== initialData.status && status !== 'PENDING') {
+                    const action = status === 'APPROVED' ? 'approve' : status === 'REJECTED' ? 'reject' : 'cancel';
+                    await request(`/vacations/${initialData.id}/${action}`, 'POST');
                 }
-                throw new Error(errorMessage);
+            } else {
+                await request(`/users/${userId}/vacations`, 'POST', { start_date: startDate, end_date: endDate });
             }
 
             await onSuccess();
@@ -181,6 +182,10 @@ export default function VacationModal({ isOpen, onClose, onSuccess, initialData,
                                     <input type="radio" name="status" value="REJECTED" checked={status === 'REJECTED'} onChange={() => setStatus('REJECTED')} />
                                     <XCircle size={16} /> Rejected
                                 </label>
+                                <label style={{ display: 'flex', alignItems: 'center', gap: '0.5rem', cursor: 'pointer' }}>
+                                    <input type="radio" name="status" value="CANCELLED" checked={status === 'CANCELLED'} onChange={() => setStatus('CANCELLED')} />
+                                    <X size={16} /> Cancelled
+                                </label>
                             </div>
                         </div>
                     )}
@@ -1,4 +1,4 @@
-import { Users, Building2, DollarSign, Activity } from 'lucide-react';
+import { Users, Building2, DollarSign, Activity, Clock } from 'lucide-react';
 import { apiFetch } from '../utils/api';
 import { useState, useEffect } from 'react';
 import Layout from '../components/Layout';
@@ -50,6 +50,7 @@ export default function Dashboard({ currentUser, onLogout }: DashboardProps) {
         { title: "Total Users", value: "0", change: "0%", icon: Users, trend: 'neutral' },
         { title: "Active Companies", value: "0", change: "0%", icon: Building2, trend: 'neutral' },
         { title: "Annual Payroll", value: "€0", change: "0%", icon: DollarSign, trend: 'neutral' },
+        { title: "Overtime Hours", value: "0 h", change: "0", icon: Clock, trend: 'neutral' },
         { title: "Server Usage", value: "24%", change: "5.0%", icon: Activity, trend: 'neutral' },
     ]);
 
@@ -75,6 +76,11 @@ export default function Dashboard({ currentUser, onLogout }: DashboardProps) {
                                 return { ...stat, value: formatted, trend: 'neutral', change: '+0' };
                             }
                         }
+                        if (stat.title === "Overtime Hours") {
+                            const apiStat = data.displaystats.find((s: any) => s.type === "overtime");
+                            const overCap = data.displaystats.find((s: any) => s.type === "overtime_cap");
+                            if (apiStat) return { ...stat, value: `${apiStat.value} h`, trend: overCap?.value > 0 ? 'down' : 'neutral', change: `${overCap?.value ?? 0} over the 80 h cap` };
+                        }
                         return stat;
                     }));
                 }
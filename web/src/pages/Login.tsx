@@ -12,6 +12,7 @@ export default function Login({ onLoginSuccess }: LoginProps) {
     const [password, setPassword] = useState('');
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState('');
+    const [notice, setNotice] = useState('');
 
     const handleSubmit = async (e: React.FormEvent) => {
         e.preventDefault();
@@ -34,6 +35,7 @@ export default function Login({ onLoginSuccess }: LoginProps) {
             if (data.user) {
                 if (data.token) {
                     localStorage.setItem('token', data.token);
+                    localStorage.setItem('refresh_token', data.refresh_token);
                     // Also store user for potential persistence
                     localStorage.setItem('user', JSON.stringify(data.user));
                 }
@@ -52,6 +54,26 @@ export default function Login({ onLoginSuccess }: LoginProps) {
         }
     };
 
+    // Emails a reset link; the answer is the same whether the account exists or not
+    const handleForgotPassword = async () => {
+        if (!email) {
+            setError('Enter your email to reset your password.');
+            return;
+        }
+        setError('');
+        try {
+            const response = await fetch('/password/forgot', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ email }),
+            });
+            if (!response.ok) throw new Error();
+            setNotice('If the email has an account, you will receive a link to reset your password.');
+        } catch {
+            setError('Failed to request a password reset.');
+        }
+    };
+
     return (
         <div style={{
             display: 'flex',
@@ -81,6 +103,19 @@ export default function Login({ onLoginSuccess }: LoginProps) {
                             {error}
                         </div>
                     )}
+                    {notice && (
+                        <div style={{
+                            backgroundColor: 'rgba(16, 185, 129, 0.1)',
+                            color: '#34d399',
+                            padding: '0.75rem',
+                            borderRadius: 'var(--radius-md)',
+                            fontSize: '0.875rem',
+                            marginBottom: '1rem',
+                            textAlign: 'center'
+                        }}>
+                            {notice}
+                        </div>
+                    )}
 
                     <div className="input-group">
                         <label className="input-label" htmlFor="email">Email</label>
@@ -116,6 +151,16 @@ export default function Login({ onLoginSuccess }: LoginProps) {
                         </div>
                     </div>
 
+                    <div style={{ textAlign: 'right', fontSize: '0.875rem' }}>
+                        <button
+                            type="button"
+                            onClick={handleForgotPassword}
+                            style={{ background: 'none', border: 'none', color: 'var(--color-primary)', cursor: 'pointer' }}
+                        >
+                            Forgot your password?
+                        </button>
+                    </div>
+
                     <button type="submit" className="btn mt-4" disabled={loading}>
                         {loading ? <Loader2 className="animate-spin" /> : <>Sign In <ArrowRight size={18} style={{ marginLeft: '8px' }} /></>}
                     </button>
@@ -17,6 +17,8 @@ export default function Register() {
     const [adminName, setAdminName] = useState('');
     const [adminEmail, setAdminEmail] = useState('');
     const [adminPassword, setAdminPassword] = useState('');
+    // Only the admin is created anonymously: the team is added with the admin's session
+    const [adminToken, setAdminToken] = useState<string | null>(null);
 
     // Step 3: Employees Data
     const [employees, setEmployees] = useState([{ name: '', email: '', password: '', role: 'EMPLOYEE' }]);
@@ -58,6 +60,15 @@ export default function Register() {
                 })
             });
             if (!res.ok) throw new Error('Failed to create admin');
+
+            const loginRes = await fetch('/login', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ email: adminEmail, password: adminPassword })
+            });
+            if (!loginRes.ok) throw new Error('Failed to sign in as admin');
+            const loginData = await loginRes.json();
+            setAdminToken(loginData.token);
             setStep(3);
         } catch (err: any) {
             setError(err.message);
@@ -67,7 +78,7 @@ export default function Register() {
     };
 
     const handleBatchCreateEmployees = async () => {
-        if (!createdCompanyId) return;
+        if (!createdCompanyId || !adminToken) return;
         setLoading(true);
         setError('');
         try {
@@ -77,7 +88,7 @@ export default function Register() {
             if (validEmployees.length > 0) {
                 const res = await fetch(`/companies/${createdCompanyId}/users/batch`, {
                     method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
+                    headers: { 'Content-Type': 'application/json', 'Authorization': `Bearer ${adminToken}` },
                     body: JSON.stringify(validEmployees)
                 });
                 if (!res.ok) throw new Error('Failed to create employees');
@@ -0,0 +1,104 @@
+import React, { useState } from 'react';
+import { Lock, ArrowRight, Loader2 } from 'lucide-react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+
+// Landing page of the invitation and password reset emails
+export default function SetPassword() {
+    const navigate = useNavigate();
+    const [searchParams] = useSearchParams();
+    const token = searchParams.get('token') || '';
+    const [password, setPassword] = useState('');
+    const [confirm, setConfirm] = useState('');
+    const [loading, setLoading] = useState(false);
+    const [error, setError] = useState('');
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        if (password !== confirm) {
+            setError('The passwords do not match.');
+            return;
+        }
+        setLoading(true);
+        setError('');
+
+        try {
+            const response = await fetch('/password/reset', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ token, new_password: password }),
+            });
+            if (response.status === 401) {
+                throw new Error('This link has expired or was already used. Ask for a new one.');
+            }
+            if (!response.ok) {
+                throw new Error('The password must have at least 8 characters.');
+            }
+            navigate('/login');
+        } catch (err: any) {
+            setError(err.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    return (
+        <div style={{
+            display: 'flex',
+            minHeight: '100vh',
+            width: '100vw',
+            alignItems: 'center',
+            justifyContent: 'center',
+            background: 'radial-gradient(circle at 50% 0%, #27272a 0%, #09090b 75%)'
+        }}>
+            <div className="card" style={{ width: '100%', maxWidth: '400px', backdropFilter: 'blur(10px)', backgroundColor: 'rgba(24, 24, 27, 0.8)' }}>
+                <div className="text-center mb-6">
+                    <h1 style={{ fontSize: '1.75rem', fontWeight: 700, marginBottom: '0.5rem' }}>Choose your password</h1>
+                    <p className="text-muted">You will sign in with it from now on</p>
+                </div>
+
+                <form onSubmit={handleSubmit}>
+                    {error && (
+                        <div style={{
+                            backgroundColor: 'rgba(239, 68, 68, 0.1)',
+                            color: '#ef4444',
+                            padding: '0.75rem',
+                            borderRadius: 'var(--radius-md)',
+                            fontSize: '0.875rem',
+                            marginBottom: '1rem',
+                            textAlign: 'center'
+                        }}>
+                            {error}
+                        </div>
+                    )}
+
+                    {[
+                        { id: 'password', label: 'New password', value: password, onChange: setPassword },
+                        { id: 'confirm', label: 'Confirm password', value: confirm, onChange: setConfirm },
+                    ].map(field => (
+                        <div className="input-group" key={field.id}>
+                            <label className="input-label" htmlFor={field.id}>{field.label}</label>
+                            <div style={{ position: 'relative' }}>
+                                <Lock size={18} style={{ position: 'absolute', left: '12px', top: '50%', transform: 'translateY(-50%)', color: 'var(--color-text-muted)' }} />
+                                <input
+                                    id={field.id}
+                                    type="password"
+                                    className="input-field"
+                                    style={{ paddingLeft: '2.5rem' }}
+                                    placeholder="••••••••"
+                                    minLength={8}
+                                    value={field.value}
+                                    onChange={(e) => field.onChange(e.target.value)}
+                                    required
+                                />
+                            </div>
+                        </div>
+                    ))}
+
+                    <button type="submit" className="btn mt-4" disabled={loading || !token}>
+                        {loading ? <Loader2 className="animate-spin" /> : <>Save Password <ArrowRight size={18} style={{ marginLeft: '8px' }} /></>}
+                    </button>
+                </form>
+            </div>
+        </div>
+    );
+}
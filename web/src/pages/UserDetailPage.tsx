@@ -11,6 +11,8 @@ interface User {
     name: string;
     email: string;
     role: string;
+    department?: string;
+    manager_id?: string;
     created_at: string;
     // company_id is not always returned or needed here unless we fetch company details
 }
@@ -32,6 +34,8 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
     const [name, setName] = useState('');
     const [email, setEmail] = useState('');
     const [role, setRole] = useState('');
+    const [managerId, setManagerId] = useState('');
+    const [teammates, setTeammates] = useState<User[]>([]);
 
     const currentUser = JSON.parse(localStorage.getItem('user') || '{}');
     const isAdmin = currentUser?.role === 'ADMIN';
@@ -83,6 +87,7 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
                     setName(userData.name);
                     setEmail(userData.email);
                     setRole(userData.role);
+                    setManagerId(userData.manager_id || '');
 
                     if (contractsData && Array.isArray(contractsData)) {
                         setContracts(contractsData);
@@ -96,6 +101,15 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
         }
     }, [id, isAdmin]);
 
+    // Candidates for manager (Admin Only)
+    useEffect(() => {
+        if (!isAdmin || !currentUser.company_id) return;
+        apiFetch(`/companies/${currentUser.company_id}/users`)
+            .then(res => res.json())
+            .then(data => setTeammates(Array.isArray(data) ? data : []))
+            .catch(err => console.error("Failed to fetch users", err));
+    }, [isAdmin, currentUser.company_id]);
+
     const handleSave = async () => {
         if (!user) return;
         setSaving(true);
@@ -105,7 +119,7 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
         try {
             const res = await apiFetch(`/users/${user.id}`, {
                 method: 'PUT',
-                body: JSON.stringify({ name, email, role })
+                body: JSON.stringify({ name, email, role, department: user.department, manager_id: managerId || null })
             });
 
             if (!res.ok) throw new Error('Failed to update user');
@@ -292,11 +306,32 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
                                     disabled={!isAdmin} // Only Admin can change roles
                                 >
                                     <option value="EMPLOYEE">User (Employee)</option>
+                                    <option value="MANAGER">Manager</option>
                                     <option value="ADMIN">Administrator</option>
                                 </select>
                             </div>
                         </div>
 
+                        <div className="input-group">
+                            <label className="input-label">Manager</label>
+                            <div style={{ position: 'relative' }}>
+                                <UserIcon size={18} style={{ position: 'absolute', left: '1rem', top: '50%', transform: 'translateY(-50%)', color: 'var(--color-text-muted)' }} />
+                                <select
+                                    className="input-field"
+                                    style={{ paddingLeft: '3rem', appearance: 'none' }}
+                                    value={managerId}
+                                    onChange={e => setManagerId(e.target.value)}
+                                    disabled={!isAdmin} // Approves this user's vacations
+                                >
+                                    <option value="">No manager</option>
+                                    {teammates.filter(t => t.id !== user.id).map(t => (
+                                        <option key={t.id} value={t.id}>{t.name}</option>
+                                    ))}
+                                    {!isAdmin && managerId && <option value={managerId}>{managerId}</option>}
+                                </select>
+                            </div>
+                        </div>
+
                         <div className="input-group">
                             <label className="input-label">Joined On</label>
                             <div style={{ position: 'relative' }}>
@@ -400,6 +435,14 @@ export default function UserDetailPage({ onUserUpdate }: UserDetailPageProps) {
                                                 <span>{contract.type}</span>
                                                 <span>•</span>
                                                 <span style={{ color: 'var(--color-primary)' }}>{contract.salary.toLocaleString()}€</span>
+                                                {contract.status && (
+                                                    <>
+                                                        <span>•</span>
+                                                        <span style={{ color: contract.status === 'ACTIVE' ? '#10b981' : contract.status === 'UPCOMING' ? 'var(--color-primary)' : 'var(--color-text-muted)' }}>
+                                                            {contract.status === 'ACTIVE' ? 'Active' : contract.status === 'UPCOMING' ? 'Upcoming' : 'Ended'}
+                                                        </span>
+                                                    </>
+                                                )}
                                             </div>
                                             <div style={{ fontSize: '0.75rem', color: 'var(--color-text-muted)', marginTop: '0.25rem' }}>
                                                 {new Date(contract.start_date).toLocaleDateString()} — {contract.end_date ? new Date(contract.end_date).toLocaleDateString() : 'Indefinite'}
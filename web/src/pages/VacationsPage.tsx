@@ -40,15 +40,16 @@ export default function VacationsPage({ currentUser }: VacationsPageProps) {
         }
     };
 
-    const handleDeleteVacation = async (vacationId: string) => {
-        if (!window.confirm('Are you sure you want to delete this vacation request?')) return;
+    const handleCancelVacation = async (vacationId: string) => {
+        if (!window.confirm('Are you sure you want to cancel this vacation request?')) return;
 
         try {
-            const res = await apiFetch(`/vacations/${vacationId}`, { method: 'DELETE' });
-            if (!res.ok) throw new Error('Failed to delete vacation');
+            // Cancelling keeps the request and its decision history
+            const res = await apiFetch(`/vacations/${vacationId}/cancel`, { method: 'POST' });
+            if (!res.ok) throw new Error('Failed to cancel vacation');
 
-            // Optimistic update
-            setVacations(vacations.filter(v => v.id !== vacationId));
+            const cancelled: Vacation = await res.json();
+            setVacations(vacations.map(v => v.id === vacationId ? cancelled : v));
         } catch (err: any) {
             console.error(err.message);
             // Re-fetch to be safe
@@ -118,6 +119,10 @@ export default function VacationsPage({ currentUser }: VacationsPageProps) {
                                             const end = new Date(vacation.end_date);
                                             const diffTime = Math.abs(end.getTime() - start.getTime());
                                             const diffDays = Math.ceil(diffTime / (1000 * 60 * 60 * 24)) + 1; // Inclusive
+                                            const partial = vacation.period === 'MORNING' ? 'Morning'
+                                                : vacation.period === 'AFTERNOON' ? 'Afternoon'
+                                                : vacation.period === 'HOURS' ? `${vacation.start_time}–${vacation.end_time}`
+                                                : null;
 
                                             return (
                                                 <tr key={vacation.id} style={{ borderBottom: '1px solid var(--color-border)' }}>
@@ -127,7 +132,7 @@ export default function VacationsPage({ currentUser }: VacationsPageProps) {
                                                         </div>
                                                     </td>
                                                     <td style={{ padding: '1rem' }}>
-                                                        {diffDays} days
+                                                        {partial ?? `${diffDays} days`}
                                                     </td>
                                                     <td style={{ padding: '1rem' }}>
                                                         <span style={{
@@ -154,10 +159,11 @@ export default function VacationsPage({ currentUser }: VacationsPageProps) {
                                                                 Edit
                                                             </button>
                                                             <button
-                                                                onClick={() => handleDeleteVacation(vacation.id)}
-                                                                style={{ padding: '0.5rem', background: 'rgba(239, 68, 68, 0.1)', border: 'none', borderRadius: '4px', cursor: 'pointer', color: '#ef4444' }}
+                                                                onClick={() => handleCancelVacation(vacation.id)}
+                                                                disabled={vacation.status !== 'PENDING' && vacation.status !== 'APPROVED'}
+                                                                style={{ padding: '0.5rem', background: 'rgba(239, 68, 68, 0.1)', border: 'none', borderRadius: '4px', cursor: 'pointer', color: '#ef4444', opacity: (vacation.status !== 'PENDING' && vacation.status !== 'APPROVED') ? 0.5 : 1 }}
                                                             >
-                                                                Delete
+                                                                Cancel
                                                             </button>
                                                         </div>
                                                     </td>
@@ -3,7 +3,17 @@ export interface Vacation {
     user_id: string;
     start_date: string;
     end_date: string;
-    status: 'PENDING' | 'APPROVED' | 'REJECTED';
+    period: 'FULL_DAY' | 'MORNING' | 'AFTERNOON' | 'HOURS';
+    start_time?: string;
+    end_time?: string;
+    status: 'PENDING' | 'APPROVED' | 'REJECTED' | 'CANCELLED';
+    approver_id?: string;
+    decided_at?: string;
+    rejection_reason?: string;
+    leave_type_id?: string;
+    attachment_url?: string;
+    constraints_overridden_by?: string;
+    constraints_overridden_at?: string;
     created_at: string;
     updated_at: string;
 }
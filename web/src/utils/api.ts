@@ -1,6 +1,35 @@
 export const API_URL = ''; // Relative path because of Vite proxy
 
-export async function apiFetch(endpoint: string, options: RequestInit = {}) {
+function clearSession() {
+    localStorage.removeItem('token');
+    localStorage.removeItem('refresh_token');
+    localStorage.removeItem('user');
+}
+
+// Access tokens last 15 minutes: renew them with the refresh token, which is
+// single-use and replaced on every refresh. Concurrent 401s share one refresh.
+let refreshing: Promise<boolean> | null = null;
+
+async function refreshTokens(): Promise<boolean> {
+    const refreshToken = localStorage.getItem('refresh_token');
+    if (!refreshToken) {
+        return false;
+    }
+    const response = await fetch(`${API_URL}/token/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+    if (!response.ok) {
+        return false;
+    }
+    const data = await response.json();
+    localStorage.setItem('token', data.token);
+    localStorage.setItem('refresh_token', data.refresh_token);
+    return true;
+}
+
+export async function apiFetch(endpoint: string, options: RequestInit = {}, retry = true): Promise<Response> {
     const token = localStorage.getItem('token');
 
     const headers: any = {
@@ -18,12 +47,31 @@ export async function apiFetch(endpoint: string, options: RequestInit = {}) {
     });
 
     if (response.status === 401) {
-        // Token expired or invalid
-        localStorage.removeItem('token');
-        localStorage.removeItem('user');
+        if (retry) {
+            refreshing = refreshing ?? refreshTokens().finally(() => { refreshing = null; });
+            if (await refreshing) {
+                return apiFetch(endpoint, options, false);
+            }
+        }
+        // Session expired or revoked
+        clearSession();
         window.location.href = '/login'; // Force redirect
         throw new Error('Unauthorized');
     }
 
     return response;
 }
+
+// logout ends the session on the server too, so its refresh token stops working.
+export async function logout() {
+    const refreshToken = localStorage.getItem('refresh_token');
+    const token = localStorage.getItem('token');
+    if (refreshToken && token) {
+        await fetch(`${API_URL}/logout`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', 'Authorization': `Bearer ${token}` },
+            body: JSON.stringify({ refresh_token: refreshToken }),
+        }).catch(() => undefined);
+    }
+    clearSession();
+}
@@ -7,6 +7,9 @@ export default defineConfig({
   server: {
     proxy: {
       '/login': 'http://localhost:8080',
+      '/password': 'http://localhost:8080',
+      '/token': 'http://localhost:8080',
+      '/logout': 'http://localhost:8080',
       '/users': 'http://localhost:8080',
       '/companies': 'http://localhost:8080',
       '/dashboard': 'http://localhost:8080',